@@ -0,0 +1,121 @@
+package gokzg4844
+
+import (
+	"fmt"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/crate-crypto/go-kzg-4844/internal/kzg"
+	"github.com/crate-crypto/go-kzg-4844/internal/multiexp"
+)
+
+// accumulatorBatchSize is the number of scalars that a [CommitmentAccumulator] buffers before adding them to the
+// running commitment with a single multi exponentiation.
+const accumulatorBatchSize = 256
+
+// CommitmentAccumulator computes the commitment to a blob from its scalars as they arrive, in any order, without
+// holding the whole blob in memory. It is created with [Context.NewCommitmentAccumulator].
+//
+// The commitment is the same as [Context.BlobToKZGCommitment] would return for the blob holding the scalars.
+//
+// A CommitmentAccumulator is not safe for concurrent use by multiple go-routines.
+type CommitmentAccumulator struct {
+	commitKey *kzg.CommitKey
+	backend   MultiExpBackend
+
+	// seen[i] is set once the scalar at index i has been added.
+	seen       [ScalarsPerBlob]bool
+	numSeen    int
+	commitment bls12381.G1Jac
+
+	// The scalars which were added but are not in the commitment yet, along with their points in the commit key.
+	pendingScalars []fr.Element
+	pendingPoints  []bls12381.G1Affine
+}
+
+// NewCommitmentAccumulator returns a [CommitmentAccumulator] for a blob, which commits with the trusted setup of the
+// Context.
+func (c *Context) NewCommitmentAccumulator() (*CommitmentAccumulator, error) {
+	if err := c.checkCanProve(); err != nil {
+		return nil, err
+	}
+	if err := c.checkBlobDomain(); err != nil {
+		return nil, err
+	}
+
+	return &CommitmentAccumulator{
+		commitKey:      c.commitKey,
+		backend:        c.multiExpBackend,
+		pendingScalars: make([]fr.Element, 0, accumulatorBatchSize),
+		pendingPoints:  make([]bls12381.G1Affine, 0, accumulatorBatchSize),
+	}, nil
+}
+
+// AddScalar adds the scalar at the given index of the blob to the commitment.
+//
+// Returns [ErrScalarIndexOutOfRange] if the index is not less than [ScalarsPerBlob], [ErrDuplicateScalarIndex] if a
+// scalar was already added at that index, and [ErrNonCanonicalScalar] if the scalar is not canonical. A rejected
+// scalar leaves the accumulator unchanged.
+func (acc *CommitmentAccumulator) AddScalar(index int, s Scalar) error {
+	if index < 0 || index >= ScalarsPerBlob {
+		return fmt.Errorf("%w: got %d", ErrScalarIndexOutOfRange, index)
+	}
+	if acc.seen[index] {
+		return fmt.Errorf("%w: %d", ErrDuplicateScalarIndex, index)
+	}
+
+	// 1. Deserialization
+	//
+	scalar, err := DeserializeScalar(s)
+	if err != nil {
+		return err
+	}
+	acc.seen[index] = true
+	acc.numSeen++
+
+	// 2. Buffer the scalar, along with its point in the commit key
+	//
+	// Zero scalars do not change the commitment
+	if scalar.IsZero() {
+		return nil
+	}
+	acc.pendingScalars = append(acc.pendingScalars, scalar)
+	acc.pendingPoints = append(acc.pendingPoints, acc.commitKey.G1[index])
+	if len(acc.pendingScalars) == accumulatorBatchSize {
+		return acc.flush()
+	}
+	return nil
+}
+
+// Finalize returns the commitment to the blob. Returns an error wrapping [ErrMissingScalars] if a scalar was not
+// added at every index of the blob.
+func (acc *CommitmentAccumulator) Finalize() (KZGCommitment, error) {
+	if acc.numSeen != ScalarsPerBlob {
+		return KZGCommitment{}, fmt.Errorf("%w: got %d of %d", ErrMissingScalars, acc.numSeen, ScalarsPerBlob)
+	}
+	if err := acc.flush(); err != nil {
+		return KZGCommitment{}, err
+	}
+
+	var commitment bls12381.G1Affine
+	commitment.FromJacobian(&acc.commitment)
+	return KZGCommitment(SerializeG1Point(commitment)), nil
+}
+
+// flush adds the buffered scalars to the commitment.
+func (acc *CommitmentAccumulator) flush() error {
+	if len(acc.pendingScalars) == 0 {
+		return nil
+	}
+
+	// The batches are small, so a single go-routine is used for them
+	partialCommitment, err := multiexp.MultiExpWithBackend(acc.backend, acc.pendingScalars, acc.pendingPoints, 1)
+	if err != nil {
+		return err
+	}
+	acc.commitment.AddMixed(partialCommitment)
+
+	acc.pendingScalars = acc.pendingScalars[:0]
+	acc.pendingPoints = acc.pendingPoints[:0]
+	return nil
+}
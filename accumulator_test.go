@@ -0,0 +1,57 @@
+package gokzg4844_test
+
+import (
+	"math/rand"
+	"testing"
+
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitmentAccumulator(t *testing.T) {
+	blob := GetRandBlob(50)
+	// Some zero scalars, which are skipped
+	for i := 0; i < 10; i++ {
+		modifyBlob(&blob, gokzg4844.Scalar{}, i*7)
+	}
+	expected, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+
+	acc, err := ctx.NewCommitmentAccumulator()
+	require.NoError(t, err)
+
+	// Add the scalars in a random order
+	order := rand.New(rand.NewSource(50)).Perm(gokzg4844.ScalarsPerBlob)
+	for n, i := range order {
+		var scalar gokzg4844.Scalar
+		copy(scalar[:], blob[i*gokzg4844.SerializedScalarSize:(i+1)*gokzg4844.SerializedScalarSize])
+		require.NoError(t, acc.AddScalar(i, scalar))
+
+		if n == 0 {
+			require.ErrorIs(t, acc.AddScalar(i, scalar), gokzg4844.ErrDuplicateScalarIndex)
+		}
+		if n == len(order)-2 {
+			_, err = acc.Finalize()
+			require.ErrorIs(t, err, gokzg4844.ErrMissingScalars)
+		}
+	}
+
+	got, err := acc.Finalize()
+	require.NoError(t, err)
+	require.Equal(t, expected, got)
+}
+
+func TestCommitmentAccumulatorInvalidInput(t *testing.T) {
+	acc, err := ctx.NewCommitmentAccumulator()
+	require.NoError(t, err)
+
+	require.ErrorIs(t, acc.AddScalar(-1, gokzg4844.Scalar{}), gokzg4844.ErrScalarIndexOutOfRange)
+	require.ErrorIs(t, acc.AddScalar(gokzg4844.ScalarsPerBlob, gokzg4844.Scalar{}), gokzg4844.ErrScalarIndexOutOfRange)
+
+	// A rejected scalar can be replaced
+	require.ErrorIs(t, acc.AddScalar(3, nonCanonicalScalar(3)), gokzg4844.ErrNonCanonicalScalar)
+	require.NoError(t, acc.AddScalar(3, gokzg4844.Scalar{}))
+
+	_, err = acc.Finalize()
+	require.ErrorIs(t, err, gokzg4844.ErrMissingScalars)
+}
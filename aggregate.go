@@ -0,0 +1,172 @@
+package gokzg4844
+
+import (
+	"fmt"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/crate-crypto/go-kzg-4844/internal/kzg"
+	"github.com/crate-crypto/go-kzg-4844/internal/utils"
+)
+
+// DomSepAggregatedBlobs is the domain separator for the challenge of an aggregated blob proof.
+const DomSepAggregatedBlobs = "FSBLOBAGGREGATE_"
+
+// ComputeAggregatedBlobProof computes a single proof for many blobs, as in the versions of EIP-4844 before the
+// per-blob proofs of [compute_blob_kzg_proof]. This is not part of the Ethereum protocol, but is useful to protocols
+// which commit to many blobs at once.
+//
+// The blobs are folded into one polynomial with the powers of a Fiat-Shamir challenge r, derived from all of the blobs
+// and commitments, and the folded polynomial is opened at r^n, where n is the number of blobs. Alongside the proof, it
+// returns the evaluation of the folded polynomial at that point. The proof is checked with
+// [Context.VerifyAggregatedBlobProof].
+//
+// Returns [ErrBatchLengthCheck] if the number of blobs and commitments differ.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+//
+// [compute_blob_kzg_proof]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#compute_blob_kzg_proof
+func (c *Context) ComputeAggregatedBlobProof(blobs []Blob, commitments []KZGCommitment, numGoRoutines int) (KZGProof, Scalar, error) {
+	if err := c.checkCanProve(); err != nil {
+		return KZGProof{}, Scalar{}, err
+	}
+	if len(blobs) != len(commitments) {
+		return KZGProof{}, Scalar{}, ErrBatchLengthCheck
+	}
+
+	// 1. Deserialization
+	//
+	polynomials := make([]kzg.Polynomial, len(blobs))
+	for i := 0; i < len(blobs); i++ {
+		var err error
+		polynomials[i], err = DeserializeBlob(blobs[i])
+		if err != nil {
+			return KZGProof{}, Scalar{}, fmt.Errorf("blob %d: %w", i, err)
+		}
+
+		// We only do this to check if it is in the correct subgroup
+		_, err = c.deserializeKZGCommitment(commitments[i])
+		if err != nil {
+			return KZGProof{}, Scalar{}, fmt.Errorf("commitment %d: %w", i, err)
+		}
+	}
+
+	// 2. Compute Fiat-Shamir challenges
+	powers, evaluationChallenge := c.computeAggregationChallenges(blobs, commitments)
+
+	// 3. Fold the polynomials
+	//
+	aggregatedPolynomial := make(kzg.Polynomial, ScalarsPerBlob)
+	var tmp fr.Element
+	for i := 0; i < len(polynomials); i++ {
+		for j := 0; j < ScalarsPerBlob; j++ {
+			tmp.Mul(&polynomials[i][j], &powers[i])
+			aggregatedPolynomial[j].Add(&aggregatedPolynomial[j], &tmp)
+		}
+	}
+
+	// 4. Create opening proof
+	stopTiming := c.startTiming(OpOpen)
+	openingProof, err := kzg.Open(c.domain, aggregatedPolynomial, evaluationChallenge, c.commitKey, c.goRoutines(numGoRoutines))
+	stopTiming()
+	if err != nil {
+		return KZGProof{}, Scalar{}, err
+	}
+
+	// 5. Serialization
+	//
+	kzgProof := SerializeG1Point(openingProof.QuotientCommitment)
+
+	return KZGProof(kzgProof), SerializeScalar(openingProof.ClaimedValue), nil
+}
+
+// VerifyAggregatedBlobProof verifies a proof computed by [Context.ComputeAggregatedBlobProof] for the given blobs and
+// commitments.
+//
+// The commitments and the evaluations of the blobs at the evaluation challenge are folded with the same powers of r as
+// the blobs were, so the aggregated polynomial is never computed.
+//
+// Returns [ErrBatchLengthCheck] if the number of blobs and commitments differ, and [ErrVerifyOpeningProof] if the
+// inputs are well-formed but the proof is wrong.
+func (c *Context) VerifyAggregatedBlobProof(blobs []Blob, commitments []KZGCommitment, proof KZGProof) error {
+	if c.closed {
+		return ErrContextClosed
+	}
+	if len(blobs) != len(commitments) {
+		return ErrBatchLengthCheck
+	}
+
+	// 1. Deserialization
+	//
+	polynomials := make([]kzg.Polynomial, len(blobs))
+	polynomialCommitments := make([]bls12381.G1Affine, len(commitments))
+	for i := 0; i < len(blobs); i++ {
+		var err error
+		polynomialCommitments[i], err = c.deserializeKZGCommitment(commitments[i])
+		if err != nil {
+			return fmt.Errorf("commitment %d: %w", i, err)
+		}
+
+		polynomials[i], err = DeserializeBlob(blobs[i])
+		if err != nil {
+			return fmt.Errorf("blob %d: %w", i, err)
+		}
+	}
+
+	quotientCommitment, err := c.deserializeKZGProof(proof)
+	if err != nil {
+		return err
+	}
+
+	// 2. Compute Fiat-Shamir challenges
+	powers, evaluationChallenge := c.computeAggregationChallenges(blobs, commitments)
+
+	// 3. Fold the commitments and the evaluations of the blobs
+	//
+	evaluations := make([]fr.Element, len(polynomials))
+	for i := 0; i < len(polynomials); i++ {
+		evaluation, err := c.domain.EvaluateLagrangePolynomial(polynomials[i], evaluationChallenge)
+		if err != nil {
+			return err
+		}
+		evaluations[i] = *evaluation
+	}
+
+	aggregatedCommitment, claimedValue, err := kzg.FoldCommitments(polynomialCommitments, evaluations, powers, c.goRoutines(c.verifyGoRoutines))
+	if err != nil {
+		return err
+	}
+
+	// 4. Verify opening proof
+	openingProof := kzg.OpeningProof{
+		QuotientCommitment: quotientCommitment,
+		InputPoint:         evaluationChallenge,
+		ClaimedValue:       claimedValue,
+	}
+
+	defer c.startTiming(OpVerify)()
+	return kzg.Verify(&aggregatedCommitment, &openingProof, c.openKey)
+}
+
+// computeAggregationChallenges returns the powers r^0, ..., r^(n-1) that n blobs are folded with, and the evaluation
+// challenge r^n, where r is the hash of [DomSepAggregatedBlobs], the degree of the polynomials and the number of blobs
+// as 16 big endian bytes each, followed by the blobs and then the commitments.
+//
+// The hash is the one configured with [WithChallengeHash].
+func (c *Context) computeAggregationChallenges(blobs []Blob, commitments []KZGCommitment) ([]fr.Element, fr.Element) {
+	data := make([]byte, 0, len(DomSepAggregatedBlobs)+32+len(blobs)*(len(Blob{})+len(KZGCommitment{})))
+	data = append(data, DomSepAggregatedBlobs...)
+	data = append(data, u64ToByteArray16(ScalarsPerBlob)...)
+	data = append(data, u64ToByteArray16(uint64(len(blobs)))...)
+	for i := 0; i < len(blobs); i++ {
+		data = append(data, blobs[i][:]...)
+	}
+	for i := 0; i < len(commitments); i++ {
+		data = append(data, commitments[i][:]...)
+	}
+	r := hashToBLSField(c.newChallengeHash, data)
+
+	powers := utils.ComputePowers(r, uint(len(blobs)+1))
+	return powers[:len(blobs)], powers[len(blobs)]
+}
@@ -0,0 +1,76 @@
+package gokzg4844_test
+
+import (
+	"testing"
+
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregatedBlobProof(t *testing.T) {
+	blobs := []gokzg4844.Blob{GetRandBlob(60), GetRandBlob(61), GetRandBlob(62)}
+	commitments := make([]gokzg4844.KZGCommitment, len(blobs))
+	for i := 0; i < len(blobs); i++ {
+		var err error
+		commitments[i], err = ctx.BlobToKZGCommitment(blobs[i], NumGoRoutines)
+		require.NoError(t, err)
+	}
+
+	proof, _, err := ctx.ComputeAggregatedBlobProof(blobs, commitments, NumGoRoutines)
+	require.NoError(t, err)
+	require.NoError(t, ctx.VerifyAggregatedBlobProof(blobs, commitments, proof))
+
+	// The proof is bound to the order of the blobs
+	swappedBlobs := []gokzg4844.Blob{blobs[1], blobs[0], blobs[2]}
+	swappedCommitments := []gokzg4844.KZGCommitment{commitments[1], commitments[0], commitments[2]}
+	require.ErrorIs(t, ctx.VerifyAggregatedBlobProof(swappedBlobs, swappedCommitments, proof), gokzg4844.ErrVerifyOpeningProof)
+
+	// A modified blob invalidates the proof
+	modifiedBlobs := append([]gokzg4844.Blob{}, blobs...)
+	modifyBlob(&modifiedBlobs[2], GetRandFieldElement(63), 0)
+	require.ErrorIs(t, ctx.VerifyAggregatedBlobProof(modifiedBlobs, commitments, proof), gokzg4844.ErrVerifyOpeningProof)
+
+	// As does a proof for a subset of the blobs
+	subsetProof, _, err := ctx.ComputeAggregatedBlobProof(blobs[:2], commitments[:2], NumGoRoutines)
+	require.NoError(t, err)
+	require.NoError(t, ctx.VerifyAggregatedBlobProof(blobs[:2], commitments[:2], subsetProof))
+	require.ErrorIs(t, ctx.VerifyAggregatedBlobProof(blobs, commitments, subsetProof), gokzg4844.ErrVerifyOpeningProof)
+}
+
+func TestAggregatedBlobProofSingleBlob(t *testing.T) {
+	// With one blob, the aggregated polynomial is the blob itself, so the claimed value is its evaluation
+	blob := GetRandBlob(64)
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+
+	proof, claimedValue, err := ctx.ComputeAggregatedBlobProof([]gokzg4844.Blob{blob}, []gokzg4844.KZGCommitment{commitment}, NumGoRoutines)
+	require.NoError(t, err)
+	require.NoError(t, ctx.VerifyAggregatedBlobProof([]gokzg4844.Blob{blob}, []gokzg4844.KZGCommitment{commitment}, proof))
+
+	require.NotEqual(t, gokzg4844.Scalar{}, claimedValue)
+	require.ErrorIs(t, ctx.VerifyBlobKZGProof(blob, commitment, proof), gokzg4844.ErrVerifyOpeningProof)
+}
+
+func TestAggregatedBlobProofInvalidInput(t *testing.T) {
+	blob := GetRandBlob(65)
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	blobs := []gokzg4844.Blob{blob}
+	commitments := []gokzg4844.KZGCommitment{commitment}
+
+	_, _, err = ctx.ComputeAggregatedBlobProof(blobs, nil, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrBatchLengthCheck)
+	require.ErrorIs(t, ctx.VerifyAggregatedBlobProof(nil, commitments, gokzg4844.KZGProof{}), gokzg4844.ErrBatchLengthCheck)
+
+	badBlob := blob
+	modifyBlob(&badBlob, nonCanonicalScalar(65), 0)
+	_, _, err = ctx.ComputeAggregatedBlobProof([]gokzg4844.Blob{badBlob}, commitments, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+	require.ErrorContains(t, err, "blob 0")
+	require.ErrorIs(t, ctx.VerifyAggregatedBlobProof([]gokzg4844.Blob{badBlob}, commitments, gokzg4844.KZGProof{}), gokzg4844.ErrNonCanonicalScalar)
+
+	// No blobs at all
+	proof, _, err := ctx.ComputeAggregatedBlobProof(nil, nil, NumGoRoutines)
+	require.NoError(t, err)
+	require.NoError(t, ctx.VerifyAggregatedBlobProof(nil, nil, proof))
+}
@@ -1,12 +1,26 @@
 package gokzg4844_test
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
 	"math/big"
+	"os"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/consensys/gnark-crypto/ecc"
 	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	"github.com/crate-crypto/go-kzg-4844/internal/kzg"
 	"github.com/stretchr/testify/require"
 )
 
@@ -26,6 +40,920 @@ func TestPointAtInfinity(t *testing.T) {
 	require.Equal(t, expectedPointAtInfinity[:], gokzg4844.PointAtInfinity[:])
 }
 
+func TestPrecomputedCommitKey(t *testing.T) {
+	precomputedCtx, err := gokzg4844.NewContext4096Insecure1337(gokzg4844.WithPrecomputedCommitKey())
+	require.NoError(t, err)
+
+	blob := GetRandBlob(21)
+	expected, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	got, err := precomputedCtx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expected, got)
+
+	// The quotient commitment goes through the same path
+	expectedProof, err := ctx.ComputeBlobKZGProof(blob, expected, NumGoRoutines)
+	require.NoError(t, err)
+	gotProof, err := precomputedCtx.ComputeBlobKZGProof(blob, got, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expectedProof, gotProof)
+}
+
+// countingBackend is a MultiExpBackend which counts how many times it is called,
+// and records the numGoRoutines of the last call, or returns err if it is set.
+type countingBackend struct {
+	mu             sync.Mutex
+	calls          int
+	lastGoRoutines int
+	numPoints      []int
+	err            error
+}
+
+func (b *countingBackend) MultiExp(points []bls12381.G1Affine, scalars []fr.Element, numGoRoutines int) (*bls12381.G1Affine, error) {
+	b.mu.Lock()
+	b.calls++
+	b.lastGoRoutines = numGoRoutines
+	b.numPoints = append(b.numPoints, len(points))
+	b.mu.Unlock()
+	if b.err != nil {
+		return nil, b.err
+	}
+	return new(bls12381.G1Affine).MultiExp(points, scalars, ecc.MultiExpConfig{})
+}
+
+func (b *countingBackend) numCalls() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.calls
+}
+
+func (b *countingBackend) goRoutines() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.lastGoRoutines
+}
+
+// smallestMultiExp returns the number of points of the smallest multi exponentiation computed by the backend.
+func (b *countingBackend) smallestMultiExp() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	smallest := -1
+	for _, n := range b.numPoints {
+		if smallest == -1 || n < smallest {
+			smallest = n
+		}
+	}
+	return smallest
+}
+
+func TestSingleThreaded(t *testing.T) {
+	singleThreadedCtx, err := gokzg4844.NewContext4096Insecure1337(gokzg4844.WithSingleThreaded(), gokzg4844.WithMonomialSetup())
+	require.NoError(t, err)
+
+	blobs := []gokzg4844.Blob{GetRandBlob(40), GetRandBlob(41)}
+	expectedCommitments, err := ctx.BlobsToKZGCommitments(blobs, NumGoRoutines)
+	require.NoError(t, err)
+	commitments, err := singleThreadedCtx.BlobsToKZGCommitments(blobs, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expectedCommitments, commitments)
+
+	proofs := make([]gokzg4844.KZGProof, len(blobs))
+	for i := range blobs {
+		expected, err := ctx.ComputeBlobKZGProof(blobs[i], commitments[i], NumGoRoutines)
+		require.NoError(t, err)
+		proofs[i], err = singleThreadedCtx.ComputeBlobKZGProof(blobs[i], commitments[i], NumGoRoutines)
+		require.NoError(t, err)
+		require.Equal(t, expected, proofs[i])
+		require.NoError(t, singleThreadedCtx.VerifyBlobKZGProof(blobs[i], commitments[i], proofs[i]))
+	}
+	require.NoError(t, singleThreadedCtx.VerifyBlobKZGProofBatch(blobs, commitments, proofs))
+	require.NoError(t, singleThreadedCtx.VerifyBlobKZGProofBatchPar(blobs, commitments, proofs))
+	proofs[0], proofs[1] = proofs[1], proofs[0]
+	require.ErrorIs(t, singleThreadedCtx.VerifyBlobKZGProofBatch(blobs, commitments, proofs), gokzg4844.ErrVerifyOpeningProof)
+	require.ErrorIs(t, singleThreadedCtx.VerifyBlobKZGProofBatchPar(blobs, commitments, proofs), gokzg4844.ErrVerifyOpeningProof)
+
+	expectedCells, expectedCellProofs, err := ctx.ComputeCellsAndKZGProofs(blobs[0], NumGoRoutines)
+	require.NoError(t, err)
+	cells, cellProofs, err := singleThreadedCtx.ComputeCellsAndKZGProofs(blobs[0], NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expectedCells, cells)
+	require.Equal(t, expectedCellProofs, cellProofs)
+	err = singleThreadedCtx.VerifyCellKZGProofBatch(commitments[:1], []uint64{5}, cells[5:6], cellProofs[5:6])
+	require.NoError(t, err)
+
+	// A backend that was set explicitly is kept
+	backend := &countingBackend{}
+	backendCtx, err := gokzg4844.NewContext4096Insecure1337(gokzg4844.WithMultiExpBackend(backend), gokzg4844.WithSingleThreaded())
+	require.NoError(t, err)
+	_, err = backendCtx.BlobToKZGCommitment(blobs[0], NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, 1, backend.numCalls())
+}
+
+func TestMultiExpBackend(t *testing.T) {
+	backend := &countingBackend{}
+	backendCtx, err := gokzg4844.NewContext4096Insecure1337(gokzg4844.WithMultiExpBackend(backend), gokzg4844.WithPrecomputedCommitKey())
+	require.NoError(t, err)
+
+	blob := GetRandBlob(28)
+	expected, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	got, err := backendCtx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expected, got)
+	require.Equal(t, 1, backend.numCalls())
+
+	proof, err := backendCtx.ComputeBlobKZGProof(blob, got, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, 2, backend.numCalls())
+
+	// Batch verification folds the commitments and quotients with the backend
+	blobs := []gokzg4844.Blob{blob, blob}
+	commitments := []gokzg4844.KZGCommitment{got, got}
+	proofs := []gokzg4844.KZGProof{proof, proof}
+	require.NoError(t, backendCtx.VerifyBlobKZGProofBatch(blobs, commitments, proofs))
+	require.Equal(t, 5, backend.numCalls())
+
+	// So does batch verification of cells
+	cells, cellProofs, err := ctx.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+	require.NoError(t, err)
+	err = backendCtx.VerifyCellKZGProofBatch(commitments, []uint64{0, 7}, []gokzg4844.Cell{cells[0], cells[7]}, []gokzg4844.KZGProof{cellProofs[0], cellProofs[7]})
+	require.NoError(t, err)
+	require.Equal(t, 9, backend.numCalls())
+
+	// Errors from the backend are returned to the caller
+	backend.err = errors.New("backend failure")
+	_, err = backendCtx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.ErrorIs(t, err, backend.err)
+}
+
+func TestCommitmentDeduplication(t *testing.T) {
+	blob := GetRandBlob(31)
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	proof, err := ctx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+	cells, cellProofs, err := ctx.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+	require.NoError(t, err)
+
+	blobs := []gokzg4844.Blob{blob, blob, blob}
+	commitments := []gokzg4844.KZGCommitment{commitment, commitment, commitment}
+	proofs := []gokzg4844.KZGProof{proof, proof, proof}
+	cellIndices := []uint64{1, 2, 3}
+	for _, dedup := range []bool{false, true} {
+		backend := &countingBackend{}
+		opts := []gokzg4844.ContextOption{gokzg4844.WithMultiExpBackend(backend)}
+		if dedup {
+			opts = append(opts, gokzg4844.WithCommitmentDeduplication())
+		}
+		dedupCtx, err := gokzg4844.NewContext4096Insecure1337(opts...)
+		require.NoError(t, err)
+
+		// The repeated commitments are folded as a single point
+		require.NoError(t, dedupCtx.VerifyBlobKZGProofBatch(blobs, commitments, proofs))
+		require.NoError(t, dedupCtx.VerifyCellKZGProofBatch(commitments, cellIndices, cells[1:4], cellProofs[1:4]))
+		require.Equal(t, dedup, backend.smallestMultiExp() == 1)
+
+		err = dedupCtx.VerifyCellKZGProofBatch(commitments, cellIndices, cells[2:5], cellProofs[1:4])
+		require.ErrorIs(t, err, gokzg4844.ErrVerifyOpeningProof)
+	}
+}
+
+func TestVerifyGoRoutines(t *testing.T) {
+	backend := &countingBackend{}
+	backendCtx, err := gokzg4844.NewContext4096Insecure1337(gokzg4844.WithMultiExpBackend(backend), gokzg4844.WithVerifyGoRoutines(3), gokzg4844.WithMonomialSetup())
+	require.NoError(t, err)
+
+	// Methods which take numGoRoutines pass it on to the multi exponentiations
+	blob := GetRandBlob(29)
+	commitment, err := backendCtx.BlobToKZGCommitment(blob, 5)
+	require.NoError(t, err)
+	require.Equal(t, 5, backend.goRoutines())
+	proof, err := backendCtx.ComputeBlobKZGProof(blob, commitment, 5)
+	require.NoError(t, err)
+
+	// Verification methods use the number set by the option
+	blobs := []gokzg4844.Blob{blob, blob}
+	commitments := []gokzg4844.KZGCommitment{commitment, commitment}
+	proofs := []gokzg4844.KZGProof{proof, proof}
+	require.NoError(t, backendCtx.VerifyBlobKZGProofBatch(blobs, commitments, proofs))
+	require.Equal(t, 3, backend.goRoutines())
+
+	cells, cellProofs, err := backendCtx.ComputeCellsAndKZGProofs(blob, 5)
+	require.NoError(t, err)
+	require.Equal(t, 5, backend.goRoutines())
+	err = backendCtx.VerifyCellKZGProofBatch(commitments, []uint64{0, 7}, []gokzg4844.Cell{cells[0], cells[7]}, []gokzg4844.KZGProof{cellProofs[0], cellProofs[7]})
+	require.NoError(t, err)
+	require.Equal(t, 3, backend.goRoutines())
+
+	// A single-threaded Context always uses one go-routine
+	backend = &countingBackend{}
+	singleThreadedCtx, err := gokzg4844.NewContext4096Insecure1337(
+		gokzg4844.WithMultiExpBackend(backend), gokzg4844.WithSingleThreaded(), gokzg4844.WithVerifyGoRoutines(3),
+	)
+	require.NoError(t, err)
+	require.NoError(t, singleThreadedCtx.VerifyBlobKZGProofBatch(blobs, commitments, proofs))
+	require.Equal(t, 1, backend.goRoutines())
+}
+
+func TestConstantTimeScalars(t *testing.T) {
+	ctCtx, err := gokzg4844.NewContext4096Insecure1337(gokzg4844.WithConstantTimeScalars())
+	require.NoError(t, err)
+
+	blob := GetRandBlob(24)
+	inputPoint := GetRandFieldElement(24)
+	expectedProof, expectedValue, err := ctx.ComputeKZGProof(blob, inputPoint, NumGoRoutines)
+	require.NoError(t, err)
+	gotProof, gotValue, err := ctCtx.ComputeKZGProof(blob, inputPoint, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expectedProof, gotProof)
+	require.Equal(t, expectedValue, gotValue)
+
+	gotValue, err = ctCtx.EvaluatePolynomialInEvaluationForm(blob, inputPoint)
+	require.NoError(t, err)
+	require.Equal(t, expectedValue, gotValue)
+
+	_, _, err = ctCtx.ComputeKZGProof(blob, gokzg4844.BlsModulus, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+	_, err = ctCtx.EvaluatePolynomialInEvaluationForm(blob, gokzg4844.BlsModulus)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+}
+
+func TestChallengeHash(t *testing.T) {
+	hashCtx, err := gokzg4844.NewContext4096Insecure1337(gokzg4844.WithChallengeHash(sha512.New))
+	require.NoError(t, err)
+
+	blob := GetRandBlob(25)
+	commitment, err := hashCtx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	require.NotEqual(t, ctx.ComputeChallenge(blob, commitment), hashCtx.ComputeChallenge(blob, commitment))
+
+	// Proofs only verify with the hash that they were computed with
+	proof, err := hashCtx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+	require.NoError(t, hashCtx.VerifyBlobKZGProof(blob, commitment, proof))
+	require.NoError(t, hashCtx.VerifyBlobKZGProofBatch([]gokzg4844.Blob{blob}, []gokzg4844.KZGCommitment{commitment}, []gokzg4844.KZGProof{proof}))
+	require.ErrorIs(t, ctx.VerifyBlobKZGProof(blob, commitment, proof), gokzg4844.ErrVerifyOpeningProof)
+
+	// SHA-256, or nil, is the default
+	for _, newHash := range []func() hash.Hash{sha256.New, nil} {
+		shaCtx, err := gokzg4844.NewContext4096Insecure1337(gokzg4844.WithChallengeHash(newHash))
+		require.NoError(t, err)
+		require.Equal(t, ctx.ComputeChallenge(blob, commitment), shaCtx.ComputeChallenge(blob, commitment))
+	}
+}
+
+func TestTimingHook(t *testing.T) {
+	var (
+		mu  sync.Mutex
+		ops []string
+	)
+	hookCtx, err := gokzg4844.NewContext4096Insecure1337(gokzg4844.WithTimingHook(func(op string, duration time.Duration) {
+		require.True(t, duration >= 0)
+		mu.Lock()
+		ops = append(ops, op)
+		mu.Unlock()
+	}), gokzg4844.WithMonomialSetup())
+	require.NoError(t, err)
+
+	blob := GetRandBlob(27)
+	commitment, err := hookCtx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	proof, err := hookCtx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+	require.NoError(t, hookCtx.VerifyBlobKZGProof(blob, commitment, proof))
+	require.NoError(t, hookCtx.VerifyBlobKZGProofBatch([]gokzg4844.Blob{blob}, []gokzg4844.KZGCommitment{commitment}, []gokzg4844.KZGProof{proof}))
+	require.NoError(t, hookCtx.VerifyBlobKZGProofBatchPar([]gokzg4844.Blob{blob}, []gokzg4844.KZGCommitment{commitment}, []gokzg4844.KZGProof{proof}))
+	cells, cellProofs, err := hookCtx.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+	require.NoError(t, err)
+	err = hookCtx.VerifyCellKZGProofBatch([]gokzg4844.KZGCommitment{commitment}, []uint64{0}, []gokzg4844.Cell{cells[0]}, []gokzg4844.KZGProof{cellProofs[0]})
+	require.NoError(t, err)
+
+	expected := []string{
+		gokzg4844.OpCommit, gokzg4844.OpOpen, gokzg4844.OpVerify, gokzg4844.OpBatchVerify, gokzg4844.OpBatchVerify,
+		gokzg4844.OpComputeCellProofs, gokzg4844.OpBatchVerifyCells,
+	}
+	require.Equal(t, expected, ops)
+
+	// Inputs which fail to deserialize are not timed
+	ops = nil
+	modifyBlob(&blob, nonCanonicalScalar(27), 0)
+	_, err = hookCtx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+	require.Len(t, ops, 0)
+}
+
+func TestCommitToScalars(t *testing.T) {
+	blob := GetRandBlob(30)
+	scalars := make([]gokzg4844.Scalar, gokzg4844.ScalarsPerBlob)
+	for i := range scalars {
+		copy(scalars[i][:], blob[i*gokzg4844.SerializedScalarSize:])
+	}
+
+	expected, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	got, err := ctx.CommitToScalars(scalars, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expected, got)
+
+	// A shorter vector is committed to as if it was padded with zeroes
+	var paddedBlob gokzg4844.Blob
+	copy(paddedBlob[:], blob[:100*gokzg4844.SerializedScalarSize])
+	expected, err = ctx.BlobToKZGCommitment(paddedBlob, NumGoRoutines)
+	require.NoError(t, err)
+	got, err = ctx.CommitToScalars(scalars[:100], NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expected, got)
+
+	_, err = ctx.CommitToScalars(nil, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidPolynomialSize)
+	_, err = ctx.CommitToScalars(append(scalars, scalars[0]), NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidPolynomialSize)
+
+	scalars[7] = gokzg4844.BlsModulus
+	_, err = ctx.CommitToScalars(scalars, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+	require.ErrorContains(t, err, "scalar 7:")
+}
+
+func TestCommitToScalarsInChunks(t *testing.T) {
+	blob := GetRandBlob(31)
+	scalars := make([]gokzg4844.Scalar, gokzg4844.ScalarsPerBlob)
+	for i := range scalars {
+		copy(scalars[i][:], blob[i*gokzg4844.SerializedScalarSize:])
+	}
+
+	for _, n := range []int{100, gokzg4844.ScalarsPerBlob} {
+		expected, err := ctx.CommitToScalars(scalars[:n], NumGoRoutines)
+		require.NoError(t, err)
+		for _, chunkSize := range []int{0, 7, 1024, n} {
+			got, err := ctx.CommitToScalarsInChunks(scalars[:n], chunkSize, NumGoRoutines)
+			require.NoError(t, err)
+			require.Equal(t, expected, got, "chunk size %d", chunkSize)
+		}
+	}
+
+	_, err := ctx.CommitToScalarsInChunks(append(scalars, scalars[0]), 1024, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidPolynomialSize)
+
+	scalars[7] = gokzg4844.BlsModulus
+	_, err = ctx.CommitToScalarsInChunks(scalars, 1024, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+	require.ErrorContains(t, err, "scalar 7:")
+}
+
+func TestCommitMonomial(t *testing.T) {
+	blob := GetRandBlob(28)
+	expected, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+
+	polynomial, err := gokzg4844.DeserializeBlob(blob)
+	require.NoError(t, err)
+	polyCoeff := kzg.NewDomain(gokzg4844.ScalarsPerBlob).LagrangeBitReversedToMonomial(polynomial)
+	got, err := ctx.CommitMonomial(polyCoeff, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expected, got)
+
+	// Fewer coefficients are allowed, but not more
+	_, err = ctx.CommitMonomial(polyCoeff[:10], NumGoRoutines)
+	require.NoError(t, err)
+	_, err = ctx.CommitMonomial(append(polyCoeff, fr.One()), NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidPolynomialSize)
+
+	setup := ethereumTrustedSetupFromEmbedded(t)
+	delete(setup, "g1_monomial")
+	lagrangeOnlyCtx, err := gokzg4844.NewContextFromJSON(bytes.NewReader(marshalJSON(t, setup)), NumGoRoutines)
+	require.NoError(t, err)
+	_, err = lagrangeOnlyCtx.CommitMonomial(polyCoeff, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrMissingMonomialSetup)
+}
+
+func TestWithMonomialSetup(t *testing.T) {
+	blob := GetRandBlob(32)
+	polynomial, err := gokzg4844.DeserializeBlob(blob)
+	require.NoError(t, err)
+	polyCoeff := kzg.NewDomain(gokzg4844.ScalarsPerBlob).LagrangeBitReversedToMonomial(polynomial)
+
+	// Without the option, the monomial points are dropped even though the setup includes them
+	defaultCtx, err := gokzg4844.NewContext4096Insecure1337()
+	require.NoError(t, err)
+	_, err = defaultCtx.CommitMonomial(polyCoeff, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrMissingMonomialSetup)
+	_, err = defaultCtx.ProveVanishingOnSet(polyCoeff, nil, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrMissingMonomialSetup)
+	_, _, err = defaultCtx.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrMissingMonomialSetup)
+
+	// Cell proofs can still be verified
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	cells, proofs, err := ctx.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+	require.NoError(t, err)
+	err = defaultCtx.VerifyCellKZGProofBatch([]gokzg4844.KZGCommitment{commitment}, []uint64{5}, cells[5:6], proofs[5:6])
+	require.NoError(t, err)
+
+	monomialCtx, err := gokzg4844.NewContext4096Insecure1337(gokzg4844.WithMonomialSetup())
+	require.NoError(t, err)
+	got, err := monomialCtx.CommitMonomial(polyCoeff, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, commitment, got)
+}
+
+func TestInterpolatePolynomial(t *testing.T) {
+	numPoints := 20
+	points := make([]gokzg4844.Scalar, numPoints)
+	values := make([]gokzg4844.Scalar, numPoints)
+	for i := 0; i < numPoints; i++ {
+		points[i] = GetRandFieldElement(int64(200 + i))
+		values[i] = GetRandFieldElement(int64(300 + i))
+	}
+
+	polyCoeff, err := gokzg4844.InterpolatePolynomial(points, values)
+	require.NoError(t, err)
+	require.Len(t, polyCoeff, numPoints)
+	commitment, err := ctx.CommitMonomial(polyCoeff, NumGoRoutines)
+	require.NoError(t, err)
+
+	// Opening the interpolated polynomial at each of the points gives its value there
+	for i := 0; i < numPoints; i++ {
+		x, err := gokzg4844.DeserializeScalar(points[i])
+		require.NoError(t, err)
+		quotient, y := gokzg4844.DividePolyByXminusAMonomial(polyCoeff, x)
+		require.Equal(t, values[i], gokzg4844.SerializeScalar(y))
+
+		quotientCommitment, err := ctx.CommitMonomial(quotient, NumGoRoutines)
+		require.NoError(t, err)
+		proof := gokzg4844.KZGProof(quotientCommitment)
+		require.NoError(t, ctx.VerifyKZGProof(commitment, points[i], values[i], proof))
+	}
+
+	_, err = gokzg4844.InterpolatePolynomial(points, values[1:])
+	require.ErrorIs(t, err, gokzg4844.ErrInterpolationLengthMismatch)
+	points[3] = points[7]
+	_, err = gokzg4844.InterpolatePolynomial(points, values)
+	require.ErrorIs(t, err, gokzg4844.ErrDuplicateInterpolationPoint)
+	points[3] = nonCanonicalScalar(200)
+	_, err = gokzg4844.InterpolatePolynomial(points, values)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+}
+
+func TestProveVanishingOnSet(t *testing.T) {
+	// Interpolate a polynomial which is zero at the first numVanishing points, and not at the others
+	numPoints, numVanishing := 20, 8
+	points := make([]gokzg4844.Scalar, numPoints)
+	values := make([]gokzg4844.Scalar, numPoints)
+	for i := 0; i < numPoints; i++ {
+		points[i] = GetRandFieldElement(int64(400 + i))
+		if i >= numVanishing {
+			values[i] = GetRandFieldElement(int64(500 + i))
+		}
+	}
+	polyCoeff, err := gokzg4844.InterpolatePolynomial(points, values)
+	require.NoError(t, err)
+	commitment, err := ctx.CommitMonomial(polyCoeff, NumGoRoutines)
+	require.NoError(t, err)
+
+	proof, err := ctx.ProveVanishingOnSet(polyCoeff, points[:numVanishing], NumGoRoutines)
+	require.NoError(t, err)
+	require.NoError(t, ctx.VerifyVanishingOnSet(commitment, points[:numVanishing], proof))
+	require.ErrorIs(t, ctx.VerifyVanishingOnSet(commitment, points[1:numVanishing], proof), gokzg4844.ErrVerifyOpeningProof)
+	require.ErrorIs(t, ctx.VerifyVanishingOnSet(commitment, points[:numVanishing+1], proof), gokzg4844.ErrVerifyOpeningProof)
+
+	verifierCtx, err := gokzg4844.NewVerifierContext(bytes.NewReader(marshalJSON(t, ethereumTrustedSetupFromEmbedded(t))))
+	require.NoError(t, err)
+	require.NoError(t, verifierCtx.VerifyVanishingOnSet(commitment, points[:numVanishing], proof))
+
+	_, err = ctx.ProveVanishingOnSet(polyCoeff, points[:numVanishing+1], NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrPolynomialDoesNotVanish)
+	require.ErrorContains(t, err, fmt.Sprintf("point %d", numVanishing))
+
+	_, err = ctx.ProveVanishingOnSet(make([]fr.Element, gokzg4844.ScalarsPerBlob+1), points[:1], NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidPolynomialSize)
+
+	tooManyPoints := make([]gokzg4844.Scalar, ctx.NumG2Points())
+	require.ErrorIs(t, ctx.VerifyVanishingOnSet(commitment, tooManyPoints, proof), gokzg4844.ErrTooManyVanishingPoints)
+	invalidPoints := []gokzg4844.Scalar{points[0], nonCanonicalScalar(400)}
+	_, err = ctx.ProveVanishingOnSet(polyCoeff, invalidPoints, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+	require.ErrorIs(t, ctx.VerifyVanishingOnSet(commitment, invalidPoints, proof), gokzg4844.ErrNonCanonicalScalar)
+	require.ErrorIs(t, ctx.VerifyVanishingOnSet(commitment, points[:numVanishing], gokzg4844.KZGProof(gokzg4844.SerializeG1Point(g1PointNotInSubgroup()))), gokzg4844.ErrInvalidProof)
+}
+
+func TestCheckPolynomialSize(t *testing.T) {
+	require.NoError(t, ctx.CheckPolynomialSize(make([]fr.Element, gokzg4844.ScalarsPerBlob)))
+
+	for _, size := range []int{0, 1, gokzg4844.ScalarsPerBlob - 1, gokzg4844.ScalarsPerBlob + 1} {
+		err := ctx.CheckPolynomialSize(make([]fr.Element, size))
+		require.ErrorIs(t, err, gokzg4844.ErrInvalidPolynomialSize)
+	}
+}
+
+func TestFFT(t *testing.T) {
+	blob := GetRandBlob(130)
+	evals := make([]gokzg4844.Scalar, gokzg4844.ScalarsPerBlob)
+	for i := 0; i < len(evals); i++ {
+		var err error
+		evals[i], err = blob.FieldElement(i)
+		require.NoError(t, err)
+	}
+
+	// The coefficients of the blob commit to the same polynomial as the blob
+	coeffs, err := ctx.IFFT(evals)
+	require.NoError(t, err)
+	polyCoeff := make([]fr.Element, len(coeffs))
+	for i := 0; i < len(coeffs); i++ {
+		polyCoeff[i], err = gokzg4844.DeserializeScalar(coeffs[i])
+		require.NoError(t, err)
+	}
+	expected, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	got, err := ctx.CommitMonomial(polyCoeff, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expected, got)
+
+	roundTrip, err := ctx.FFT(coeffs)
+	require.NoError(t, err)
+	require.Equal(t, evals, roundTrip)
+
+	// The polynomial X evaluates to the roots of the domain
+	x := make([]gokzg4844.Scalar, gokzg4844.ScalarsPerBlob)
+	x[1] = gokzg4844.SerializeScalar(fr.One())
+	roots, err := ctx.FFT(x)
+	require.NoError(t, err)
+	require.Equal(t, ctx.DomainRoots(), roots)
+
+	_, err = ctx.FFT(coeffs[1:])
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidPolynomialSize)
+	_, err = ctx.IFFT(evals[1:])
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidPolynomialSize)
+	evals[7] = nonCanonicalScalar(130)
+	_, err = ctx.IFFT(evals)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+	require.ErrorContains(t, err, "evaluation 7")
+}
+
+func TestDomainRoots(t *testing.T) {
+	roots := ctx.DomainRoots()
+	require.Len(t, roots, gokzg4844.ScalarsPerBlob)
+
+	// The roots are bit-reversed, so the second one is -1
+	var one, minusOne fr.Element
+	one.SetOne()
+	minusOne.Neg(&one)
+	require.Equal(t, gokzg4844.SerializeScalar(one), roots[0])
+	require.Equal(t, gokzg4844.SerializeScalar(minusOne), roots[1])
+
+	// A blob holds the evaluations at the roots, in the same order
+	blob := GetRandBlob(23)
+	for _, i := range []int{0, 1, 1000, gokzg4844.ScalarsPerBlob - 1} {
+		evaluation, err := ctx.EvaluatePolynomialInEvaluationForm(blob, roots[i])
+		require.NoError(t, err)
+		require.Equal(t, blob[i*gokzg4844.SerializedScalarSize:(i+1)*gokzg4844.SerializedScalarSize], evaluation[:])
+	}
+
+	// Modifying the copy does not affect the Context
+	roots[1] = roots[0]
+	require.Equal(t, gokzg4844.SerializeScalar(minusOne), ctx.DomainRoots()[1])
+}
+
+func TestSetupSizes(t *testing.T) {
+	require.Equal(t, gokzg4844.ScalarsPerBlob, ctx.SRSSize())
+	require.Equal(t, uint64(gokzg4844.ScalarsPerBlob), ctx.DomainCardinality())
+	require.True(t, ctx.NumG2Points() >= 2)
+
+	setup := ethereumTrustedSetupFromEmbedded(t)
+	ethCtx, err := gokzg4844.NewContextFromJSON(bytes.NewReader(marshalJSON(t, setup)), NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, 4096, ethCtx.SRSSize())
+	require.Equal(t, 65, ethCtx.NumG2Points())
+	require.Equal(t, uint64(4096), ethCtx.DomainCardinality())
+
+	verifierCtx, err := gokzg4844.NewVerifierContext(bytes.NewReader(marshalJSON(t, setup)))
+	require.NoError(t, err)
+	require.Equal(t, 0, verifierCtx.SRSSize())
+	require.Equal(t, 65, verifierCtx.NumG2Points())
+	require.Equal(t, uint64(4096), verifierCtx.DomainCardinality())
+}
+
+func TestIsInDomain(t *testing.T) {
+	roots := ctx.DomainRoots()
+	for _, i := range []int{0, 1, 1000, gokzg4844.ScalarsPerBlob - 1} {
+		require.True(t, ctx.IsInDomain(roots[i]))
+	}
+
+	require.False(t, ctx.IsInDomain(gokzg4844.Scalar{}))
+	require.False(t, ctx.IsInDomain(GetRandFieldElement(26)))
+	require.False(t, ctx.IsInDomain(gokzg4844.BlsModulus))
+}
+
+func TestBitReversalPermutation(t *testing.T) {
+	scalars := ctx.DomainRoots()
+
+	// The roots are bit-reversed, so reversing them gives the powers of the generator
+	reordered := make([]gokzg4844.Scalar, len(scalars))
+	copy(reordered, scalars)
+	require.NoError(t, gokzg4844.InverseBitReversalPermutation(reordered))
+	require.Equal(t, scalars[0], reordered[0])
+	require.Equal(t, scalars[2048], reordered[1])
+	require.Equal(t, scalars[1], reordered[2048])
+
+	// Applying the permutation twice is the identity
+	require.NoError(t, gokzg4844.BitReversalPermutation(reordered))
+	require.Equal(t, scalars, reordered)
+
+	err := gokzg4844.BitReversalPermutation(scalars[:3])
+	require.ErrorIs(t, err, gokzg4844.ErrNotPowerOfTwo)
+}
+
+func TestNewContextFromJSON(t *testing.T) {
+	setup := ethereumTrustedSetupFromEmbedded(t)
+
+	jsonCtx, err := gokzg4844.NewContextFromJSON(bytes.NewReader(marshalJSON(t, setup)), NumGoRoutines)
+	require.NoError(t, err)
+
+	blob := GetRandBlob(7)
+	expected, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	got, err := jsonCtx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expected, got)
+
+	inputPoint := GetRandFieldElement(7)
+	proof, claimedValue, err := jsonCtx.ComputeKZGProof(blob, inputPoint, NumGoRoutines)
+	require.NoError(t, err)
+	require.NoError(t, jsonCtx.VerifyKZGProof(got, inputPoint, claimedValue, proof))
+
+	// The monomial G1 points are optional
+	delete(setup, "g1_monomial")
+	_, err = gokzg4844.NewContextFromJSON(bytes.NewReader(marshalJSON(t, setup)), NumGoRoutines)
+	require.NoError(t, err)
+}
+
+func TestNewContextFromJSONWithSize(t *testing.T) {
+	setup := ethereumTrustedSetupFromEmbedded(t)
+
+	const numScalars = 256
+	smallCtx, err := gokzg4844.NewContextFromJSONWithSize(bytes.NewReader(marshalJSON(t, setup)), NumGoRoutines, numScalars)
+	require.NoError(t, err)
+	require.Equal(t, uint64(numScalars), smallCtx.DomainCardinality())
+
+	scalars := make([]gokzg4844.Scalar, numScalars)
+	poly := make(kzg.Polynomial, numScalars)
+	for i := range scalars {
+		scalars[i] = GetRandFieldElement(int64(i))
+		poly[i], err = gokzg4844.DeserializeScalar(scalars[i])
+		require.NoError(t, err)
+	}
+	commitment, err := smallCtx.CommitToScalars(scalars, NumGoRoutines)
+	require.NoError(t, err)
+
+	// The commitment is to the polynomial interpolating the scalars over the smaller domain
+	domain := kzg.NewDomain(numScalars)
+	domain.ReverseRoots()
+	expected, err := ctx.CommitMonomial(domain.LagrangeBitReversedToMonomial(poly), NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expected, commitment)
+
+	inputPoint := GetRandFieldElement(1000)
+	proof, claimedValue, err := smallCtx.ComputeKZGProofForScalars(scalars, inputPoint, NumGoRoutines)
+	require.NoError(t, err)
+	require.NoError(t, smallCtx.VerifyKZGProof(commitment, inputPoint, claimedValue, proof))
+	require.NoError(t, ctx.VerifyKZGProof(commitment, inputPoint, claimedValue, proof))
+
+	_, _, err = smallCtx.ComputeKZGProofForScalars(scalars[1:], inputPoint, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidPolynomialSize)
+
+	for _, size := range []int{0, -1, 3000, 2 * gokzg4844.ScalarsPerBlob} {
+		_, err = gokzg4844.NewContextFromJSONWithSize(bytes.NewReader(marshalJSON(t, setup)), NumGoRoutines, size)
+		require.ErrorIs(t, err, gokzg4844.ErrInvalidDomainSize)
+	}
+
+	// The lagrange points of a smaller domain are computed from the monomial points
+	delete(setup, "g1_monomial")
+	_, err = gokzg4844.NewContextFromJSONWithSize(bytes.NewReader(marshalJSON(t, setup)), NumGoRoutines, numScalars)
+	require.ErrorIs(t, err, gokzg4844.ErrMissingMonomialSetup)
+}
+
+func TestSetupEqual(t *testing.T) {
+	setup := ethereumTrustedSetupFromEmbedded(t)
+
+	jsonCtx, err := gokzg4844.NewContextFromJSON(bytes.NewReader(marshalJSON(t, setup)), NumGoRoutines, gokzg4844.WithMonomialSetup())
+	require.NoError(t, err)
+	require.True(t, ctx.SetupEqual(ctx))
+	require.True(t, ctx.SetupEqual(jsonCtx))
+	require.True(t, jsonCtx.SetupEqual(ctx))
+
+	// Only one of the Contexts keeps the monomial points
+	lagrangeOnlyCtx, err := gokzg4844.NewContextFromJSON(bytes.NewReader(marshalJSON(t, setup)), NumGoRoutines)
+	require.NoError(t, err)
+	require.False(t, ctx.SetupEqual(lagrangeOnlyCtx))
+
+	otherCtx, err := gokzg4844.NewContextFromJSONWithSize(bytes.NewReader(marshalJSON(t, setup)), NumGoRoutines, 256)
+	require.NoError(t, err)
+	require.False(t, ctx.SetupEqual(otherCtx))
+
+	verifierCtx, err := gokzg4844.NewVerifierContext(bytes.NewReader(marshalJSON(t, setup)))
+	require.NoError(t, err)
+	require.False(t, ctx.SetupEqual(verifierCtx))
+	require.False(t, verifierCtx.SetupEqual(ctx))
+
+	// A single differing point is detected
+	setup["g2_monomial"][64] = setup["g2_monomial"][63]
+	otherCtx, err = gokzg4844.NewContextFromJSON(bytes.NewReader(marshalJSON(t, setup)), NumGoRoutines, gokzg4844.WithMonomialSetup())
+	require.NoError(t, err)
+	require.False(t, ctx.SetupEqual(otherCtx))
+}
+
+func TestNewVerifierContext(t *testing.T) {
+	setup := ethereumTrustedSetupFromEmbedded(t)
+	verifierCtx, err := gokzg4844.NewVerifierContext(bytes.NewReader(marshalJSON(t, setup)))
+	require.NoError(t, err)
+	require.NoError(t, verifierCtx.ValidateSetup())
+
+	// Proofs made with a full Context verify
+	blob := GetRandBlob(8)
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	proof, err := ctx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+	require.NoError(t, verifierCtx.VerifyBlobKZGProof(blob, commitment, proof))
+	require.NoError(t, verifierCtx.VerifyBlobKZGProofBatch([]gokzg4844.Blob{blob}, []gokzg4844.KZGCommitment{commitment}, []gokzg4844.KZGProof{proof}))
+
+	inputPoint := GetRandFieldElement(8)
+	pointProof, claimedValue, err := ctx.ComputeKZGProof(blob, inputPoint, NumGoRoutines)
+	require.NoError(t, err)
+	require.NoError(t, verifierCtx.VerifyKZGProof(commitment, inputPoint, claimedValue, pointProof))
+
+	cells, cellProofs, err := ctx.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+	require.NoError(t, err)
+	err = verifierCtx.VerifyCellKZGProofBatch([]gokzg4844.KZGCommitment{commitment}, []uint64{3}, []gokzg4844.Cell{cells[3]}, []gokzg4844.KZGProof{cellProofs[3]})
+	require.NoError(t, err)
+
+	// The proving methods are not supported
+	_, err = verifierCtx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrProvingNotSupported)
+	_, err = verifierCtx.BlobsToKZGCommitments([]gokzg4844.Blob{blob}, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrProvingNotSupported)
+	_, err = verifierCtx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrProvingNotSupported)
+	_, _, err = verifierCtx.ComputeKZGProof(blob, inputPoint, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrProvingNotSupported)
+	_, _, err = verifierCtx.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrProvingNotSupported)
+	_, err = verifierCtx.NewCommitmentAccumulator()
+	require.ErrorIs(t, err, gokzg4844.ErrProvingNotSupported)
+	err = verifierCtx.SerializeSetup(&bytes.Buffer{})
+	require.ErrorIs(t, err, gokzg4844.ErrProvingNotSupported)
+
+	// Computing cells does not need the trusted setup
+	verifierCells, err := verifierCtx.ComputeCells(blob)
+	require.NoError(t, err)
+	require.Equal(t, cells, verifierCells)
+
+	// Without the monomial points, blob proofs can still be verified
+	delete(setup, "g1_monomial")
+	verifierCtx, err = gokzg4844.NewVerifierContext(bytes.NewReader(marshalJSON(t, setup)), gokzg4844.WithPrecomputedCommitKey())
+	require.NoError(t, err)
+	require.NoError(t, verifierCtx.ValidateSetup())
+	require.NoError(t, verifierCtx.VerifyBlobKZGProof(blob, commitment, proof))
+
+	// The G2 points are checked
+	setup["g2_monomial"] = setup["g2_monomial"][1:]
+	_, err = gokzg4844.NewVerifierContext(bytes.NewReader(marshalJSON(t, setup)))
+	require.ErrorIs(t, err, gokzg4844.ErrTrustedSetupLength)
+}
+
+func TestNewVerifierContextFromG2(t *testing.T) {
+	setup := ethereumTrustedSetupFromEmbedded(t)
+	var genG1 gokzg4844.G1Point
+	var g2Points [3]gokzg4844.G2Point
+	_, err := hex.Decode(genG1[:], []byte(setup["g1_monomial"][0][2:]))
+	require.NoError(t, err)
+	for i := 0; i < len(g2Points); i++ {
+		_, err = hex.Decode(g2Points[i][:], []byte(setup["g2_monomial"][i][2:]))
+		require.NoError(t, err)
+	}
+
+	verifierCtx, err := gokzg4844.NewVerifierContextFromG2(genG1, g2Points[0], g2Points[1])
+	require.NoError(t, err)
+	require.NoError(t, verifierCtx.ValidateSetup())
+	require.Equal(t, 2, verifierCtx.NumG2Points())
+
+	blob := GetRandBlob(9)
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	proof, err := ctx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+	require.NoError(t, verifierCtx.VerifyBlobKZGProof(blob, commitment, proof))
+
+	_, err = verifierCtx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrProvingNotSupported)
+	cells, cellProofs, err := ctx.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+	require.NoError(t, err)
+	err = verifierCtx.VerifyCellKZGProofBatch([]gokzg4844.KZGCommitment{commitment}, []uint64{3}, []gokzg4844.Cell{cells[3]}, []gokzg4844.KZGProof{cellProofs[3]})
+	require.ErrorIs(t, err, gokzg4844.ErrMissingMonomialSetup)
+
+	// With the degree-1 point of another setup, the proofs no longer verify
+	otherCtx, err := gokzg4844.NewVerifierContextFromG2(genG1, g2Points[0], g2Points[2])
+	require.NoError(t, err)
+	require.ErrorIs(t, otherCtx.VerifyBlobKZGProof(blob, commitment, proof), gokzg4844.ErrVerifyOpeningProof)
+
+	// The points are validated
+	uncompressed := g2Points[1]
+	uncompressed[0] &^= 0x80
+	_, err = gokzg4844.NewVerifierContextFromG2(genG1, g2Points[0], uncompressed)
+	require.ErrorIs(t, err, gokzg4844.ErrUncompressedPoint)
+	_, err = gokzg4844.NewVerifierContextFromG2(gokzg4844.SerializeG1Point(g1PointNotInSubgroup()), g2Points[0], g2Points[1])
+	require.ErrorIs(t, err, gokzg4844.ErrPointNotInSubgroup)
+}
+
+func TestValidateSetup(t *testing.T) {
+	require.NoError(t, ctx.ValidateSetup())
+
+	setup := ethereumTrustedSetupFromEmbedded(t)
+	jsonCtx, err := gokzg4844.NewContextFromJSON(bytes.NewReader(marshalJSON(t, setup)), NumGoRoutines, gokzg4844.WithMonomialSetup())
+	require.NoError(t, err)
+	require.NoError(t, jsonCtx.ValidateSetup())
+
+	// Swapping points keeps each of them valid, but breaks the consistency of the setup
+	swap := func(points []string, i, j int) []string {
+		swapped := append([]string(nil), points...)
+		swapped[i], swapped[j] = swapped[j], swapped[i]
+		return swapped
+	}
+	for name, modify := range map[string]func(map[string][]string){
+		"g2 secret":    func(s map[string][]string) { s["g2_monomial"] = swap(s["g2_monomial"], 1, 2) },
+		"g1 generator": func(s map[string][]string) { s["g1_monomial"] = swap(s["g1_monomial"], 0, 1) },
+		"g1 monomial":  func(s map[string][]string) { s["g1_monomial"] = swap(s["g1_monomial"], 1, 2) },
+		"g1 lagrange":  func(s map[string][]string) { delete(s, "g1_monomial"); s["g1_lagrange"][5] = s["g1_lagrange"][6] },
+	} {
+		t.Run(name, func(t *testing.T) {
+			setup := ethereumTrustedSetupFromEmbedded(t)
+			modify(setup)
+			badCtx, err := gokzg4844.NewContextFromJSON(bytes.NewReader(marshalJSON(t, setup)), NumGoRoutines, gokzg4844.WithMonomialSetup())
+			require.NoError(t, err)
+			require.ErrorIs(t, badCtx.ValidateSetup(), gokzg4844.ErrInvalidTrustedSetup)
+		})
+	}
+}
+
+func TestNewContextFromJSONInvalid(t *testing.T) {
+	notInSubgroup := gokzg4844.SerializeG1Point(g1PointNotInSubgroup())
+
+	tests := []struct {
+		name   string
+		modify func(setup map[string][]string)
+		err    error
+	}{
+		{"missing g2 point", func(setup map[string][]string) {
+			setup["g2_monomial"] = setup["g2_monomial"][1:]
+		}, gokzg4844.ErrTrustedSetupLength},
+		{"extra g1 point", func(setup map[string][]string) {
+			setup["g1_lagrange"] = append(setup["g1_lagrange"], setup["g1_lagrange"][0])
+		}, gokzg4844.ErrTrustedSetupLength},
+		{"missing 0x prefix", func(setup map[string][]string) {
+			setup["g1_lagrange"][3] = setup["g1_lagrange"][3][2:]
+		}, gokzg4844.ErrMalformedHexPoint},
+		{"invalid hex", func(setup map[string][]string) {
+			setup["g2_monomial"][1] = "0xzz" + setup["g2_monomial"][1][4:]
+		}, gokzg4844.ErrMalformedHexPoint},
+		{"g1 point not in subgroup", func(setup map[string][]string) {
+			setup["g1_lagrange"][5] = "0x" + hex.EncodeToString(notInSubgroup[:])
+		}, gokzg4844.ErrPointNotInSubgroup},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setup := ethereumTrustedSetupFromEmbedded(t)
+			test.modify(setup)
+			_, err := gokzg4844.NewContextFromJSON(bytes.NewReader(marshalJSON(t, setup)), NumGoRoutines)
+			require.ErrorIs(t, err, test.err)
+		})
+	}
+}
+
+// ethereumTrustedSetupFromEmbedded converts the test trusted setup into the format
+// published by the Ethereum KZG ceremony.
+func ethereumTrustedSetupFromEmbedded(t *testing.T) map[string][]string {
+	t.Helper()
+	data, err := os.ReadFile("trusted_setup.json")
+	require.NoError(t, err)
+	var parsedSetup gokzg4844.JSONTrustedSetup
+	require.NoError(t, json.Unmarshal(data, &parsedSetup))
+
+	return map[string][]string{
+		"g1_monomial": append([]string(nil), parsedSetup.SetupG1[:]...),
+		"g1_lagrange": append([]string(nil), parsedSetup.SetupG1Lagrange[:]...),
+		"g2_monomial": parsedSetup.SetupG2,
+	}
+}
+
+func marshalJSON(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return data
+}
+
 func TestNonCanonicalScalar(t *testing.T) {
 	reducedScalar := GetRandFieldElement(13)
 	_, err := gokzg4844.DeserializeScalar(reducedScalar)
@@ -64,9 +992,14 @@ func TestNonCanonicalSmoke(t *testing.T) {
 
 	err = ctx.VerifyKZGProof(commitment, inputPointGood, claimedValueBad, proof)
 	require.Error(t, err, "expected an error since claimed value was not canonical")
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidProof)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+	require.ErrorContains(t, err, "claimed value")
 
 	err = ctx.VerifyKZGProof(commitment, inputPointBad, claimedValueGood, proof)
 	require.Error(t, err, "expected an error since input point was not canonical")
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidProof)
+	require.ErrorContains(t, err, "input point")
 
 	blobProof, err := ctx.ComputeBlobKZGProof(blobBad, commitment, NumGoRoutines)
 	require.Error(t, err, "expected an error since blob was not canonical")
@@ -78,6 +1011,110 @@ func TestNonCanonicalSmoke(t *testing.T) {
 	require.Error(t, err, "expected an error since blob was not canonical")
 }
 
+func TestBlobToKZGCommitmentReader(t *testing.T) {
+	blob := GetRandBlob(987654321)
+	expectedCommitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+
+	gotCommitment, err := ctx.BlobToKZGCommitmentReader(bytes.NewReader(blob[:]), NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expectedCommitment, gotCommitment)
+
+	// A non-canonical scalar should be reported along with its index
+	badIndex := 1234
+	blobBad := blob
+	modifyBlob(&blobBad, nonCanonicalScalar(98765), badIndex*gokzg4844.SerializedScalarSize)
+	_, err = ctx.BlobToKZGCommitmentReader(bytes.NewReader(blobBad[:]), NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+	require.ErrorContains(t, err, "field element 1234")
+
+	// A reader which does not contain a full blob should error with the number of bytes read
+	_, err = ctx.BlobToKZGCommitmentReader(bytes.NewReader(blob[:len(blob)-1]), NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidBlobLength)
+	require.ErrorContains(t, err, fmt.Sprintf("got %d bytes", len(blob)-1))
+	_, err = ctx.BlobToKZGCommitmentReader(bytes.NewReader(nil), NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidBlobLength)
+	require.ErrorContains(t, err, "got 0 bytes")
+}
+
+func TestBlobsToKZGCommitments(t *testing.T) {
+	blobs := make([]gokzg4844.Blob, 3)
+	for i := range blobs {
+		blobs[i] = GetRandBlob(int64(30 + i))
+	}
+
+	for _, numGoRoutines := range []int{NumGoRoutines, 1, 2, 8} {
+		commitments, err := ctx.BlobsToKZGCommitments(blobs, numGoRoutines)
+		require.NoError(t, err)
+		require.Len(t, commitments, len(blobs))
+		for i := range blobs {
+			expected, err := ctx.BlobToKZGCommitment(blobs[i], NumGoRoutines)
+			require.NoError(t, err)
+			require.Equal(t, expected, commitments[i])
+		}
+	}
+
+	commitments, err := ctx.BlobsToKZGCommitments(nil, NumGoRoutines)
+	require.NoError(t, err)
+	require.Len(t, commitments, 0)
+
+	// The error should say which blob is invalid
+	modifyBlob(&blobs[2], nonCanonicalScalar(123), 0)
+	_, err = ctx.BlobsToKZGCommitments(blobs, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+	require.ErrorContains(t, err, "blob 2:")
+}
+
+func TestVerifyBlobKZGProofBatchWithFailures(t *testing.T) {
+	numBlobs := 5
+	blobs := make([]gokzg4844.Blob, numBlobs)
+	for i := 0; i < numBlobs; i++ {
+		blobs[i] = GetRandBlob(int64(100 + i))
+	}
+	commitments, err := ctx.BlobsToKZGCommitments(blobs, NumGoRoutines)
+	require.NoError(t, err)
+	proofs := make([]gokzg4844.KZGProof, numBlobs)
+	for i := 0; i < numBlobs; i++ {
+		proofs[i], err = ctx.ComputeBlobKZGProof(blobs[i], commitments[i], NumGoRoutines)
+		require.NoError(t, err)
+	}
+
+	failed, err := ctx.VerifyBlobKZGProofBatchWithFailures(blobs, commitments, proofs)
+	require.NoError(t, err)
+	require.Len(t, failed, 0)
+
+	// Swapped proofs are valid points, but do not verify, while a malformed commitment fails to deserialize
+	proofs[1], proofs[3] = proofs[3], proofs[1]
+	commitments[4] = gokzg4844.KZGCommitment(gokzg4844.SerializeG1Point(g1PointNotInSubgroup()))
+	require.Error(t, ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs))
+	failed, err = ctx.VerifyBlobKZGProofBatchWithFailures(blobs, commitments, proofs)
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 3, 4}, failed)
+
+	_, err = ctx.VerifyBlobKZGProofBatchWithFailures(blobs, commitments, proofs[1:])
+	require.ErrorIs(t, err, gokzg4844.ErrBatchLengthCheck)
+}
+
+func TestVerifyBlobCommitment(t *testing.T) {
+	blob := GetRandBlob(110)
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	require.NoError(t, ctx.VerifyBlobCommitment(blob, commitment, NumGoRoutines))
+
+	otherCommitment, err := ctx.BlobToKZGCommitment(GetRandBlob(111), NumGoRoutines)
+	require.NoError(t, err)
+	err = ctx.VerifyBlobCommitment(blob, otherCommitment, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrBlobCommitmentMismatch)
+	require.ErrorContains(t, err, "0x"+hex.EncodeToString(commitment[:]))
+
+	modifyBlob(&blob, nonCanonicalScalar(110), 0)
+	require.ErrorIs(t, ctx.VerifyBlobCommitment(blob, commitment, NumGoRoutines), gokzg4844.ErrNonCanonicalScalar)
+
+	verifierCtx, err := gokzg4844.NewVerifierContext(bytes.NewReader(marshalJSON(t, ethereumTrustedSetupFromEmbedded(t))))
+	require.NoError(t, err)
+	require.ErrorIs(t, verifierCtx.VerifyBlobCommitment(blob, commitment, NumGoRoutines), gokzg4844.ErrProvingNotSupported)
+}
+
 // Below are helper methods which allow us to change a serialized element into
 // its non-canonical counterpart by adding the modulus
 func modifyBlob(blob *gokzg4844.Blob, newValue gokzg4844.Scalar, index int) {
@@ -120,3 +1157,262 @@ func addModP(x big.Int) big.Int {
 
 	return xPlusModulus
 }
+
+func TestCancellation(t *testing.T) {
+	blobs := []gokzg4844.Blob{GetRandBlob(90), GetRandBlob(91)}
+	commitments, err := ctx.BlobsToKZGCommitmentsContext(context.Background(), blobs, NumGoRoutines)
+	require.NoError(t, err)
+	proofs := make([]gokzg4844.KZGProof, len(blobs))
+	for i := 0; i < len(blobs); i++ {
+		proofs[i], err = ctx.ComputeBlobKZGProof(blobs[i], commitments[i], NumGoRoutines)
+		require.NoError(t, err)
+	}
+	cells, cellProofs, err := ctx.ComputeCellsAndKZGProofs(blobs[0], NumGoRoutines)
+	require.NoError(t, err)
+	cellCommitments := []gokzg4844.KZGCommitment{commitments[0]}
+	cellIndices := []uint64{5}
+	batchCells := []gokzg4844.Cell{cells[5]}
+	batchCellProofs := []gokzg4844.KZGProof{cellProofs[5]}
+
+	require.NoError(t, ctx.VerifyBlobKZGProofBatchContext(context.Background(), blobs, commitments, proofs))
+	require.NoError(t, ctx.VerifyCellKZGProofBatchContext(context.Background(), cellCommitments, cellIndices, batchCells, batchCellProofs))
+
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = ctx.BlobsToKZGCommitmentsContext(cancelledCtx, blobs, NumGoRoutines)
+	require.ErrorIs(t, err, context.Canceled)
+	err = ctx.VerifyBlobKZGProofBatchContext(cancelledCtx, blobs, commitments, proofs)
+	require.ErrorIs(t, err, context.Canceled)
+	err = ctx.VerifyCellKZGProofBatchContext(cancelledCtx, cellCommitments, cellIndices, batchCells, batchCellProofs)
+	require.ErrorIs(t, err, context.Canceled)
+
+	expiredCtx, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+	_, err = ctx.BlobsToKZGCommitmentsContext(expiredCtx, blobs, 1)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// A failing blob is still reported as such
+	modifyBlob(&blobs[1], nonCanonicalScalar(90), 0)
+	_, err = ctx.BlobsToKZGCommitmentsContext(context.Background(), blobs, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+}
+
+func TestContextClose(t *testing.T) {
+	closedCtx, err := gokzg4844.NewContext4096Insecure1337(gokzg4844.WithPrecomputedCommitKey())
+	require.NoError(t, err)
+
+	blob := GetRandBlob(26)
+	commitment, err := closedCtx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	proof, err := closedCtx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+
+	require.NoError(t, closedCtx.Close())
+	require.ErrorIs(t, closedCtx.Close(), gokzg4844.ErrContextClosed)
+
+	_, err = closedCtx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrContextClosed)
+	_, err = closedCtx.BlobsToKZGCommitments([]gokzg4844.Blob{blob}, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrContextClosed)
+	_, err = closedCtx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrContextClosed)
+	_, _, err = closedCtx.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrContextClosed)
+	err = closedCtx.VerifyBlobKZGProof(blob, commitment, proof)
+	require.ErrorIs(t, err, gokzg4844.ErrContextClosed)
+	err = closedCtx.VerifyBlobKZGProofBatch(nil, nil, nil)
+	require.ErrorIs(t, err, gokzg4844.ErrContextClosed)
+	err = closedCtx.SerializeSetup(&bytes.Buffer{})
+	require.ErrorIs(t, err, gokzg4844.ErrContextClosed)
+
+	// Other contexts are unaffected
+	require.NoError(t, ctx.VerifyBlobKZGProof(blob, commitment, proof))
+}
+
+// TestContextConcurrentUse calls the methods of a single Context from many go-routines at once. Run with -race to
+// check that they do not share any mutable state.
+func TestContextConcurrentUse(t *testing.T) {
+	const numWorkers = 8
+	blobs := []gokzg4844.Blob{GetRandBlob(40), GetRandBlob(41)}
+	commitments := make([]gokzg4844.KZGCommitment, len(blobs))
+	proofs := make([]gokzg4844.KZGProof, len(blobs))
+	for i, blob := range blobs {
+		var err error
+		commitments[i], err = ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+		require.NoError(t, err)
+		proofs[i], err = ctx.ComputeBlobKZGProof(blob, commitments[i], NumGoRoutines)
+		require.NoError(t, err)
+	}
+	point := GetRandFieldElement(42)
+	pointProof, claimedValue, err := ctx.ComputeKZGProof(blobs[0], point, NumGoRoutines)
+	require.NoError(t, err)
+	cells, cellProofs, err := ctx.ComputeCellsAndKZGProofs(blobs[0], NumGoRoutines)
+	require.NoError(t, err)
+
+	errs := make(chan error, numWorkers)
+	var wg sync.WaitGroup
+	wg.Add(numWorkers)
+	for w := 0; w < numWorkers; w++ {
+		go func(w int) {
+			defer wg.Done()
+			errs <- func() error {
+				blob := blobs[w%len(blobs)]
+				commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+				if err != nil {
+					return err
+				}
+				if commitment != commitments[w%len(blobs)] {
+					return errors.New("unexpected commitment")
+				}
+				proof, err := ctx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+				if err != nil {
+					return err
+				}
+				if proof != proofs[w%len(blobs)] {
+					return errors.New("unexpected proof")
+				}
+				if err := ctx.VerifyBlobKZGProof(blob, commitment, proof); err != nil {
+					return err
+				}
+				if err := ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs); err != nil {
+					return err
+				}
+				if err := ctx.VerifyKZGProof(commitments[0], point, claimedValue, pointProof); err != nil {
+					return err
+				}
+				gotProof, gotClaimedValue, err := ctx.ComputeKZGProof(blobs[0], point, NumGoRoutines)
+				if err != nil {
+					return err
+				}
+				if gotProof != pointProof || gotClaimedValue != claimedValue {
+					return errors.New("unexpected point proof")
+				}
+				gotCells, err := ctx.ComputeCells(blobs[0])
+				if err != nil {
+					return err
+				}
+				if gotCells != cells {
+					return errors.New("unexpected cells")
+				}
+				cellIndex := uint64(w)
+				return ctx.VerifyCellKZGProofBatch(
+					[]gokzg4844.KZGCommitment{commitments[0]},
+					[]uint64{cellIndex},
+					[]gokzg4844.Cell{cells[cellIndex]},
+					[]gokzg4844.KZGProof{cellProofs[cellIndex]},
+				)
+			}()
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+}
+
+func TestNewContextInsecure(t *testing.T) {
+	// The insecure setup with the secret 1337 is the embedded test setup
+	insecureCtx, err := gokzg4844.NewContextInsecure(gokzg4844.ScalarsPerBlob, big.NewInt(1337), gokzg4844.WithMonomialSetup())
+	require.NoError(t, err)
+	require.True(t, insecureCtx.SetupEqual(ctx))
+
+	// Every path works with a random secret
+	randomCtx, err := gokzg4844.NewContextInsecure(gokzg4844.ScalarsPerBlob, nil, gokzg4844.WithMonomialSetup())
+	require.NoError(t, err)
+	require.False(t, randomCtx.SetupEqual(ctx))
+	require.NoError(t, randomCtx.ValidateSetup())
+	require.NoError(t, randomCtx.SelfTest())
+
+	blob := GetRandBlob(7)
+	commitment, err := randomCtx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	proof, err := randomCtx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+	require.NoError(t, randomCtx.VerifyBlobKZGProof(blob, commitment, proof))
+	require.Error(t, ctx.VerifyBlobKZGProof(blob, commitment, proof))
+
+	cells, cellProofs, err := randomCtx.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+	require.NoError(t, err)
+	err = randomCtx.VerifyCellKZGProofBatch([]gokzg4844.KZGCommitment{commitment}, []uint64{3}, cells[3:4], cellProofs[3:4])
+	require.NoError(t, err)
+
+	// Smaller domains only prove over scalars
+	smallCtx, err := gokzg4844.NewContextInsecure(16, nil)
+	require.NoError(t, err)
+	scalars := make([]gokzg4844.Scalar, 16)
+	for i := range scalars {
+		scalars[i] = GetRandFieldElement(int64(i))
+	}
+	commitment, err = smallCtx.CommitToScalars(scalars, NumGoRoutines)
+	require.NoError(t, err)
+	inputPoint := GetRandFieldElement(99)
+	proof, claimedValue, err := smallCtx.ComputeKZGProofForScalars(scalars, inputPoint, NumGoRoutines)
+	require.NoError(t, err)
+	require.NoError(t, smallCtx.VerifyKZGProof(commitment, inputPoint, claimedValue, proof))
+
+	// and reject the methods which index the setup or the domain by the position in a blob
+	smallCtx, err = gokzg4844.NewContextInsecure(1024, nil, gokzg4844.WithMonomialSetup())
+	require.NoError(t, err)
+	_, err = smallCtx.ComputeCells(blob)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidDomainSize)
+	_, _, err = smallCtx.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidDomainSize)
+	_, _, err = smallCtx.RecoverCellsAndKZGProofs([]uint64{0}, cells[:1], NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidDomainSize)
+	_, err = smallCtx.BlobToKZGCommitmentReader(bytes.NewReader(blob[:]), NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidDomainSize)
+	_, err = smallCtx.NewCommitmentAccumulator()
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidDomainSize)
+
+	for _, numPoints := range []int{0, -1, 3, 2 * gokzg4844.ScalarsPerBlob} {
+		_, err := gokzg4844.NewContextInsecure(numPoints, nil)
+		require.ErrorIs(t, err, gokzg4844.ErrInvalidDomainSize)
+	}
+}
+
+func TestRebasedTrustedSetup(t *testing.T) {
+	// Scale every point of the setup, so that the generators are no longer the standard ones. Without the monomial
+	// points, the G1 generator has to be recovered from the lagrange points.
+	setup := ethereumTrustedSetupFromEmbedded(t)
+	delete(setup, "g1_monomial")
+	factor := big.NewInt(7)
+	for i, hexPoint := range setup["g1_lagrange"] {
+		byts, err := hex.DecodeString(hexPoint[2:])
+		require.NoError(t, err)
+		var point bls12381.G1Affine
+		_, err = point.SetBytes(byts)
+		require.NoError(t, err)
+		point.ScalarMultiplication(&point, factor)
+		serialized := point.Bytes()
+		setup["g1_lagrange"][i] = "0x" + hex.EncodeToString(serialized[:])
+	}
+	for i, hexPoint := range setup["g2_monomial"] {
+		byts, err := hex.DecodeString(hexPoint[2:])
+		require.NoError(t, err)
+		var point bls12381.G2Affine
+		_, err = point.SetBytes(byts)
+		require.NoError(t, err)
+		point.ScalarMultiplication(&point, factor)
+		serialized := point.Bytes()
+		setup["g2_monomial"][i] = "0x" + hex.EncodeToString(serialized[:])
+	}
+	rebasedCtx, err := gokzg4844.NewContextFromJSON(bytes.NewReader(marshalJSON(t, setup)), NumGoRoutines)
+	require.NoError(t, err)
+
+	blob := GetRandBlob(123)
+	commitment, err := rebasedCtx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	inputPoint := GetRandFieldElement(123)
+	proof, claimedValue, err := rebasedCtx.ComputeKZGProof(blob, inputPoint, NumGoRoutines)
+	require.NoError(t, err)
+	require.NoError(t, rebasedCtx.VerifyKZGProof(commitment, inputPoint, claimedValue, proof))
+	require.NoError(t, rebasedCtx.VerifyKZGProofBatch([]gokzg4844.KZGCommitment{commitment}, []gokzg4844.Scalar{inputPoint}, []gokzg4844.Scalar{claimedValue}, []gokzg4844.KZGProof{proof}))
+	blobProof, err := rebasedCtx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+	require.NoError(t, rebasedCtx.VerifyBlobKZGProof(blob, commitment, blobProof))
+
+	// The proofs are not valid for the standard setup, which ValidateSetup requires
+	require.ErrorIs(t, ctx.VerifyKZGProof(commitment, inputPoint, claimedValue, proof), gokzg4844.ErrVerifyOpeningProof)
+	require.ErrorIs(t, rebasedCtx.ValidateSetup(), gokzg4844.ErrInvalidTrustedSetup)
+}
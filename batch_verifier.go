@@ -0,0 +1,183 @@
+package gokzg4844
+
+import (
+	"math/big"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/crate-crypto/go-kzg-4844/internal/multiexp"
+)
+
+// batchVerifierFlushSize is the number of proofs that a [BatchVerifier] buffers before folding them into its running
+// sums with multi exponentiations.
+const batchVerifierFlushSize = 64
+
+// BatchVerifier verifies KZG proofs as they arrive, with a single pairing check for all of them. It is created with
+// [Context.NewBatchVerifier].
+//
+// As in [Context.VerifyKZGProofBatch], the i'th proof is scaled by r^i, where r is sampled from crypto/rand when the
+// BatchVerifier is created and is never exposed, so that it cannot be predicted by whoever submits the proofs. Each
+// proof is folded into running sums shortly after it is added, so that [BatchVerifier.Finalize] only has to do the
+// pairing check.
+//
+// A BatchVerifier is not safe for concurrent use by multiple go-routines. Once its Context is closed, its methods which
+// return an error return [ErrContextClosed].
+type BatchVerifier struct {
+	ctx *Context
+
+	// randomNumber is r, and factor is r^i, where i is the number of proofs added so far.
+	randomNumber fr.Element
+	factor       fr.Element
+	numProofs    int
+
+	// foldedLhs is the sum of r^i * (C_i + z_i * π_i), foldedQuotients the sum of r^i * π_i, and foldedEvaluations the
+	// sum of r^i * y_i, over the proofs which were flushed.
+	foldedLhs         bls12381.G1Jac
+	foldedQuotients   bls12381.G1Jac
+	foldedEvaluations fr.Element
+
+	// The points and factors of the proofs which were added but are not in the running sums yet. The lhs points are
+	// the commitment and the quotient of each proof, in that order.
+	pendingLhsPoints       []bls12381.G1Affine
+	pendingLhsFactors      []fr.Element
+	pendingQuotients       []bls12381.G1Affine
+	pendingQuotientFactors []fr.Element
+}
+
+// NewBatchVerifier returns an empty [BatchVerifier], which verifies proofs with the trusted setup of the Context. This
+// works with a Context created with [NewVerifierContext].
+func (c *Context) NewBatchVerifier() (*BatchVerifier, error) {
+	if c.closed {
+		return nil, ErrContextClosed
+	}
+
+	var randomNumber fr.Element
+	if _, err := randomNumber.SetRandom(); err != nil {
+		return nil, err
+	}
+	var factor fr.Element
+	factor.SetOne()
+
+	return &BatchVerifier{
+		ctx:                    c,
+		randomNumber:           randomNumber,
+		factor:                 factor,
+		pendingLhsPoints:       make([]bls12381.G1Affine, 0, 2*batchVerifierFlushSize),
+		pendingLhsFactors:      make([]fr.Element, 0, 2*batchVerifierFlushSize),
+		pendingQuotients:       make([]bls12381.G1Affine, 0, batchVerifierFlushSize),
+		pendingQuotientFactors: make([]fr.Element, 0, batchVerifierFlushSize),
+	}, nil
+}
+
+// Add adds the proof that the polynomial committed to by `commitment` evaluates to `claimedValue` at `inputPoint`, as
+// in [Context.VerifyKZGProof], to the batch.
+//
+// The inputs are validated as in [Context.VerifyKZGProof], and a rejected proof leaves the BatchVerifier unchanged.
+// Whether the proof is correct is only known when [BatchVerifier.Finalize] is called.
+func (bv *BatchVerifier) Add(commitment KZGCommitment, inputPoint, claimedValue Scalar, proof KZGProof) error {
+	if bv.ctx.closed {
+		return ErrContextClosed
+	}
+
+	// 1. Deserialization
+	//
+	polyCommitment, err := bv.ctx.deserializeKZGCommitment(commitment)
+	if err != nil {
+		return err
+	}
+	openingProof, err := bv.ctx.deserializeOpeningProof(inputPoint, claimedValue, proof)
+	if err != nil {
+		return err
+	}
+
+	// 2. Buffer the proof, scaled by the next power of the random number
+	//
+	var scaledFactor, scaledEvaluation fr.Element
+	scaledFactor.Mul(&bv.factor, &openingProof.InputPoint)
+	scaledEvaluation.Mul(&bv.factor, &openingProof.ClaimedValue)
+	bv.foldedEvaluations.Add(&bv.foldedEvaluations, &scaledEvaluation)
+	bv.pendingLhsPoints = append(bv.pendingLhsPoints, polyCommitment, openingProof.QuotientCommitment)
+	bv.pendingLhsFactors = append(bv.pendingLhsFactors, bv.factor, scaledFactor)
+	bv.pendingQuotients = append(bv.pendingQuotients, openingProof.QuotientCommitment)
+	bv.pendingQuotientFactors = append(bv.pendingQuotientFactors, bv.factor)
+
+	bv.factor.Mul(&bv.factor, &bv.randomNumber)
+	bv.numProofs++
+	if len(bv.pendingQuotients) == batchVerifierFlushSize {
+		return bv.flush()
+	}
+	return nil
+}
+
+// Len returns the number of proofs which were added to the batch.
+func (bv *BatchVerifier) Len() int {
+	return bv.numProofs
+}
+
+// Finalize checks every proof which was added so far with a single pairing check. It returns nil if the batch is
+// empty, and [ErrVerifyOpeningProof] if any of the proofs is incorrect.
+//
+// More proofs can be added after Finalize, and the next call checks them along with the earlier ones.
+func (bv *BatchVerifier) Finalize() error {
+	if bv.ctx.closed {
+		return ErrContextClosed
+	}
+	if bv.numProofs == 0 {
+		return nil
+	}
+	if err := bv.flush(); err != nil {
+		return err
+	}
+
+	// The check is e(Σ r^i * (C_i - [y_i]G₁ + z_i * π_i), G₂) = e(Σ r^i * π_i, [s]G₂)
+	var foldedEvaluationsBigInt big.Int
+	bv.foldedEvaluations.BigInt(&foldedEvaluationsBigInt)
+	var foldedEvaluationsCommit bls12381.G1Jac
+	foldedEvaluationsCommit.FromAffine(&bv.ctx.openKey.GenG1)
+	foldedEvaluationsCommit.ScalarMultiplication(&foldedEvaluationsCommit, &foldedEvaluationsBigInt)
+
+	var lhsJac bls12381.G1Jac
+	lhsJac.Set(&bv.foldedLhs)
+	lhsJac.SubAssign(&foldedEvaluationsCommit)
+	var lhs, quotients bls12381.G1Affine
+	lhs.FromJacobian(&lhsJac)
+	quotients.FromJacobian(&bv.foldedQuotients)
+	quotients.Neg(&quotients)
+
+	check, err := bls12381.PairingCheck(
+		[]bls12381.G1Affine{lhs, quotients},
+		[]bls12381.G2Affine{bv.ctx.openKey.GenG2, bv.ctx.openKey.AlphaG2},
+	)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// flush folds the buffered proofs into the running sums.
+func (bv *BatchVerifier) flush() error {
+	if len(bv.pendingQuotients) == 0 {
+		return nil
+	}
+
+	// The batches are small, so a single go-routine is used for them
+	lhs, err := multiexp.MultiExpWithBackend(bv.ctx.multiExpBackend, bv.pendingLhsFactors, bv.pendingLhsPoints, 1)
+	if err != nil {
+		return err
+	}
+	quotients, err := multiexp.MultiExpWithBackend(bv.ctx.multiExpBackend, bv.pendingQuotientFactors, bv.pendingQuotients, 1)
+	if err != nil {
+		return err
+	}
+	bv.foldedLhs.AddMixed(lhs)
+	bv.foldedQuotients.AddMixed(quotients)
+
+	bv.pendingLhsPoints = bv.pendingLhsPoints[:0]
+	bv.pendingLhsFactors = bv.pendingLhsFactors[:0]
+	bv.pendingQuotients = bv.pendingQuotients[:0]
+	bv.pendingQuotientFactors = bv.pendingQuotientFactors[:0]
+	return nil
+}
@@ -0,0 +1,78 @@
+package gokzg4844_test
+
+import (
+	"bytes"
+	"testing"
+
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchVerifier(t *testing.T) {
+	const numDistinctProofs = 4
+	commitments := make([]gokzg4844.KZGCommitment, numDistinctProofs)
+	inputPoints := make([]gokzg4844.Scalar, numDistinctProofs)
+	claimedValues := make([]gokzg4844.Scalar, numDistinctProofs)
+	proofs := make([]gokzg4844.KZGProof, numDistinctProofs)
+	for i := 0; i < numDistinctProofs; i++ {
+		blob := GetRandBlob(int64(60 + i))
+		commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+		require.NoError(t, err)
+		inputPoint := GetRandFieldElement(int64(70 + i))
+		proof, claimedValue, err := ctx.ComputeKZGProof(blob, inputPoint, NumGoRoutines)
+		require.NoError(t, err)
+		commitments[i], inputPoints[i], claimedValues[i], proofs[i] = commitment, inputPoint, claimedValue, proof
+	}
+
+	bv, err := ctx.NewBatchVerifier()
+	require.NoError(t, err)
+	require.NoError(t, bv.Finalize())
+
+	// Enough proofs for the buffered ones to be folded more than once
+	for n := 0; n < 70; n++ {
+		i := n % numDistinctProofs
+		require.NoError(t, bv.Add(commitments[i], inputPoints[i], claimedValues[i], proofs[i]))
+	}
+	require.Equal(t, 70, bv.Len())
+	require.NoError(t, bv.Finalize())
+
+	// Invalid inputs are rejected without being added
+	err = bv.Add(commitments[0], inputPoints[0], nonCanonicalScalar(1), proofs[0])
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidProof)
+	err = bv.Add(gokzg4844.KZGCommitment{0x01}, inputPoints[0], claimedValues[0], proofs[0])
+	require.Error(t, err)
+	require.Equal(t, 70, bv.Len())
+	require.NoError(t, bv.Finalize())
+
+	// A single incorrect proof fails the whole batch, including when it is added after Finalize
+	require.NoError(t, bv.Add(commitments[1], inputPoints[1], claimedValues[0], proofs[1]))
+	require.ErrorIs(t, bv.Finalize(), gokzg4844.ErrVerifyOpeningProof)
+
+	// A verifier Context can verify the same proofs
+	setup := ethereumTrustedSetupFromEmbedded(t)
+	verifierCtx, err := gokzg4844.NewVerifierContext(bytes.NewReader(marshalJSON(t, setup)))
+	require.NoError(t, err)
+	bv, err = verifierCtx.NewBatchVerifier()
+	require.NoError(t, err)
+	for i := 0; i < numDistinctProofs; i++ {
+		require.NoError(t, bv.Add(commitments[i], inputPoints[i], claimedValues[i], proofs[i]))
+	}
+	require.NoError(t, bv.Finalize())
+
+	closedCtx, err := gokzg4844.NewContext4096Insecure1337()
+	require.NoError(t, err)
+	require.NoError(t, closedCtx.Close())
+	_, err = closedCtx.NewBatchVerifier()
+	require.ErrorIs(t, err, gokzg4844.ErrContextClosed)
+
+	// Closing the Context of a BatchVerifier stops it, rather than panicking
+	closedCtx, err = gokzg4844.NewContext4096Insecure1337()
+	require.NoError(t, err)
+	bv, err = closedCtx.NewBatchVerifier()
+	require.NoError(t, err)
+	require.NoError(t, bv.Add(commitments[0], inputPoints[0], claimedValues[0], proofs[0]))
+	require.NoError(t, closedCtx.Close())
+	require.ErrorIs(t, bv.Finalize(), gokzg4844.ErrContextClosed)
+	require.ErrorIs(t, bv.Add(commitments[1], inputPoints[1], claimedValues[1], proofs[1]), gokzg4844.ErrContextClosed)
+	require.Equal(t, 1, bv.Len())
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	// We do not require crypto/rand in tests
 	"math/rand"
+	"sync"
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
@@ -37,72 +38,201 @@ func GetRandBlob(seed int64) gokzg4844.Blob {
 	return blob
 }
 
-func Benchmark(b *testing.B) {
-	const length = 64
-	blobs := make([]gokzg4844.Blob, length)
-	commitments := make([]gokzg4844.KZGCommitment, length)
-	proofs := make([]gokzg4844.KZGProof, length)
-	fields := make([]gokzg4844.Scalar, length)
+// benchBatchSize is the number of blobs that the benchmarks share, and the largest batch that is verified.
+const benchBatchSize = 64
 
-	for i := 0; i < length; i++ {
-		blob := GetRandBlob(int64(i))
-		commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
-		require.NoError(b, err)
-		proof, err := ctx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
-		require.NoError(b, err)
-
-		blobs[i] = blob
-		commitments[i] = commitment
-		proofs[i] = proof
-		fields[i] = GetRandFieldElement(int64(i))
-	}
+// benchInputs holds random blobs along with their commitments and proofs, and random field elements, which are shared
+// by the benchmarks of the public operations.
+type benchInputs struct {
+	blobs       []gokzg4844.Blob
+	commitments []gokzg4844.KZGCommitment
+	proofs      []gokzg4844.KZGProof
+	fields      []gokzg4844.Scalar
+}
 
-	///////////////////////////////////////////////////////////////////////////
-	// Public functions
-	///////////////////////////////////////////////////////////////////////////
+var (
+	benchInputsOnce sync.Once
+	sharedInputs    benchInputs
+	benchInputsErr  error
+)
 
-	b.Run("BlobToKZGCommitment", func(b *testing.B) {
-		for n := 0; n < b.N; n++ {
-			_, _ = ctx.BlobToKZGCommitment(blobs[0], NumGoRoutines)
+// getBenchInputs returns the inputs shared by the benchmarks, which are computed on first use, outside of the timing
+// of the benchmark.
+func getBenchInputs(b *testing.B) *benchInputs {
+	b.Helper()
+	b.StopTimer()
+	defer b.StartTimer()
+
+	benchInputsOnce.Do(func() {
+		sharedInputs = benchInputs{
+			blobs:       make([]gokzg4844.Blob, benchBatchSize),
+			commitments: make([]gokzg4844.KZGCommitment, benchBatchSize),
+			proofs:      make([]gokzg4844.KZGProof, benchBatchSize),
+			fields:      make([]gokzg4844.Scalar, benchBatchSize),
 		}
-	})
-
-	b.Run("ComputeKZGProof", func(b *testing.B) {
-		for n := 0; n < b.N; n++ {
-			_, _, _ = ctx.ComputeKZGProof(blobs[0], fields[0], NumGoRoutines)
+		for i := 0; i < benchBatchSize; i++ {
+			sharedInputs.blobs[i] = GetRandBlob(int64(i))
+			sharedInputs.commitments[i], benchInputsErr = ctx.BlobToKZGCommitment(sharedInputs.blobs[i], NumGoRoutines)
+			if benchInputsErr != nil {
+				return
+			}
+			sharedInputs.proofs[i], benchInputsErr = ctx.ComputeBlobKZGProof(sharedInputs.blobs[i], sharedInputs.commitments[i], NumGoRoutines)
+			if benchInputsErr != nil {
+				return
+			}
+			sharedInputs.fields[i] = GetRandFieldElement(int64(i))
 		}
 	})
+	require.NoError(b, benchInputsErr)
+	return &sharedInputs
+}
 
-	b.Run("ComputeBlobKZGProof", func(b *testing.B) {
-		for n := 0; n < b.N; n++ {
-			_, _ = ctx.ComputeBlobKZGProof(blobs[0], commitments[0], NumGoRoutines)
-		}
-	})
+func BenchmarkBlobToKZGCommitment(b *testing.B) {
+	inputs := getBenchInputs(b)
+	for n := 0; n < b.N; n++ {
+		_, _ = ctx.BlobToKZGCommitment(inputs.blobs[0], NumGoRoutines)
+	}
+}
 
-	b.Run("VerifyKZGProof", func(b *testing.B) {
-		for n := 0; n < b.N; n++ {
-			_ = ctx.VerifyKZGProof(commitments[0], fields[0], fields[1], proofs[0])
-		}
-	})
+func BenchmarkBlobsToKZGCommitments(b *testing.B) {
+	inputs := getBenchInputs(b)
+	for _, count := range []int{1, 8, benchBatchSize} {
+		b.Run(fmt.Sprintf("count=%v", count), func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				_, _ = ctx.BlobsToKZGCommitments(inputs.blobs[:count], NumGoRoutines)
+			}
+		})
+	}
+}
 
-	b.Run("VerifyBlobKZGProof", func(b *testing.B) {
-		for n := 0; n < b.N; n++ {
-			_ = ctx.VerifyBlobKZGProof(blobs[0], commitments[0], proofs[0])
-		}
-	})
+func BenchmarkComputeKZGProof(b *testing.B) {
+	inputs := getBenchInputs(b)
+	for n := 0; n < b.N; n++ {
+		_, _, _ = ctx.ComputeKZGProof(inputs.blobs[0], inputs.fields[0], NumGoRoutines)
+	}
+}
+
+func BenchmarkComputeBlobKZGProof(b *testing.B) {
+	inputs := getBenchInputs(b)
+	for n := 0; n < b.N; n++ {
+		_, _ = ctx.ComputeBlobKZGProof(inputs.blobs[0], inputs.commitments[0], NumGoRoutines)
+	}
+}
+
+func BenchmarkVerifyKZGProof(b *testing.B) {
+	inputs := getBenchInputs(b)
+	b.StopTimer()
+	proof, claimedValue, err := ctx.ComputeKZGProof(inputs.blobs[0], inputs.fields[0], NumGoRoutines)
+	require.NoError(b, err)
+	b.StartTimer()
+
+	for n := 0; n < b.N; n++ {
+		_ = ctx.VerifyKZGProof(inputs.commitments[0], inputs.fields[0], claimedValue, proof)
+	}
+}
 
-	for i := 1; i <= len(blobs); i *= 2 {
-		b.Run(fmt.Sprintf("VerifyBlobKZGProofBatch(count=%v)", i), func(b *testing.B) {
+func BenchmarkVerifyBlobKZGProof(b *testing.B) {
+	inputs := getBenchInputs(b)
+	for n := 0; n < b.N; n++ {
+		_ = ctx.VerifyBlobKZGProof(inputs.blobs[0], inputs.commitments[0], inputs.proofs[0])
+	}
+}
+
+func BenchmarkVerifyBlobKZGProofBatch(b *testing.B) {
+	inputs := getBenchInputs(b)
+	for count := 1; count <= benchBatchSize; count *= 2 {
+		b.Run(fmt.Sprintf("count=%v", count), func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				_ = ctx.VerifyBlobKZGProofBatch(inputs.blobs[:count], inputs.commitments[:count], inputs.proofs[:count])
+			}
+		})
+	}
+}
+
+func BenchmarkVerifyBlobKZGProofBatchPar(b *testing.B) {
+	inputs := getBenchInputs(b)
+	for count := 1; count <= benchBatchSize; count *= 2 {
+		b.Run(fmt.Sprintf("count=%v", count), func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				_ = ctx.VerifyBlobKZGProofBatchPar(inputs.blobs[:count], inputs.commitments[:count], inputs.proofs[:count])
+			}
+		})
+	}
+}
+
+func BenchmarkComputeCells(b *testing.B) {
+	inputs := getBenchInputs(b)
+	for n := 0; n < b.N; n++ {
+		_, _ = ctx.ComputeCells(inputs.blobs[0])
+	}
+}
+
+func BenchmarkComputeCellsAndKZGProofs(b *testing.B) {
+	inputs := getBenchInputs(b)
+	for n := 0; n < b.N; n++ {
+		_, _, _ = ctx.ComputeCellsAndKZGProofs(inputs.blobs[0], NumGoRoutines)
+	}
+}
+
+func BenchmarkVerifyCellKZGProofBatch(b *testing.B) {
+	inputs := getBenchInputs(b)
+	b.StopTimer()
+	cells, proofs, err := ctx.ComputeCellsAndKZGProofs(inputs.blobs[0], NumGoRoutines)
+	require.NoError(b, err)
+	commitments := make([]gokzg4844.KZGCommitment, gokzg4844.CellsPerExtBlob)
+	cellIndices := make([]uint64, gokzg4844.CellsPerExtBlob)
+	for i := 0; i < gokzg4844.CellsPerExtBlob; i++ {
+		commitments[i] = inputs.commitments[0]
+		cellIndices[i] = uint64(i)
+	}
+	b.StartTimer()
+
+	for _, count := range []int{1, 16, gokzg4844.CellsPerExtBlob} {
+		b.Run(fmt.Sprintf("count=%v", count), func(b *testing.B) {
 			for n := 0; n < b.N; n++ {
-				_ = ctx.VerifyBlobKZGProofBatch(blobs[:i], commitments[:i], proofs[:i])
+				_ = ctx.VerifyCellKZGProofBatch(commitments[:count], cellIndices[:count], cells[:count], proofs[:count])
 			}
 		})
 	}
+}
+
+func BenchmarkRecoverCellsAndKZGProofs(b *testing.B) {
+	inputs := getBenchInputs(b)
+	b.StopTimer()
+	cells, _, err := ctx.ComputeCellsAndKZGProofs(inputs.blobs[0], NumGoRoutines)
+	require.NoError(b, err)
+	// Half of the cells, which is the fewest that recovery needs
+	cellIndices := make([]uint64, 0, gokzg4844.CellsPerExtBlob/2)
+	halfCells := make([]gokzg4844.Cell, 0, gokzg4844.CellsPerExtBlob/2)
+	for i := 0; i < gokzg4844.CellsPerExtBlob; i += 2 {
+		cellIndices = append(cellIndices, uint64(i))
+		halfCells = append(halfCells, cells[i])
+	}
+	b.StartTimer()
 
-	for i := 1; i <= len(blobs); i *= 2 {
-		b.Run(fmt.Sprintf("VerifyBlobKZGProofBatchPar(count=%v)", i), func(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		_, _, _ = ctx.RecoverCellsAndKZGProofs(cellIndices, halfCells, NumGoRoutines)
+	}
+}
+
+func BenchmarkBlobToKZGCommitmentPrecomputed(b *testing.B) {
+	const numCommitments = 1000
+	blobs := make([]gokzg4844.Blob, 16)
+	for i := 0; i < len(blobs); i++ {
+		blobs[i] = GetRandBlob(int64(i))
+	}
+	precomputedCtx, err := gokzg4844.NewContext4096Insecure1337(gokzg4844.WithPrecomputedCommitKey())
+	require.NoError(b, err)
+
+	for _, c := range []struct {
+		name string
+		ctx  *gokzg4844.Context
+	}{{"generic", ctx}, {"precomputed", precomputedCtx}} {
+		b.Run(fmt.Sprintf("%s(count=%v)", c.name, numCommitments), func(b *testing.B) {
 			for n := 0; n < b.N; n++ {
-				_ = ctx.VerifyBlobKZGProofBatchPar(blobs[:i], commitments[:i], proofs[:i])
+				for i := 0; i < numCommitments; i++ {
+					_, _ = c.ctx.BlobToKZGCommitment(blobs[i%len(blobs)], NumGoRoutines)
+				}
 			}
 		})
 	}
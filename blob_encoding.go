@@ -0,0 +1,90 @@
+package gokzg4844
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// bytesPerEncodedScalar is the number of bytes of data in each field element of a blob from [EncodeToBlobs]. The
+// first byte of each field element is left zero, so that the field element is always canonical.
+const bytesPerEncodedScalar = SerializedScalarSize - 1
+
+// EncodeToBlobs packs arbitrary data into as few blobs as possible, so that it can be recovered with
+// [DecodeFromBlobs].
+//
+// The first field element of the first blob is a header, which holds the length of the data as a big-endian uint64 in
+// its last 8 bytes. The data then follows in groups of 31 bytes, each in the last 31 bytes of a field element whose
+// first byte is zero. The last group and the rest of the last blob are padded with zeros. Every blob holds 4096 * 31
+// bytes of data, less 31 bytes for the header in the first one, and an empty input is encoded as a single blob.
+func EncodeToBlobs(data []byte) []Blob {
+	blobs := make([]Blob, numEncodedBlobs(uint64(len(data))))
+
+	binary.BigEndian.PutUint64(blobs[0][SerializedScalarSize-8:SerializedScalarSize], uint64(len(data)))
+	for i := 1; len(data) > 0; i++ {
+		blob := &blobs[i/ScalarsPerBlob]
+		offset := (i%ScalarsPerBlob)*SerializedScalarSize + 1
+		data = data[copy(blob[offset:offset+bytesPerEncodedScalar], data):]
+	}
+	return blobs
+}
+
+// DecodeFromBlobs recovers the data which [EncodeToBlobs] encoded into the blobs.
+//
+// The blobs must be exactly the ones that [EncodeToBlobs] returns for the data: there must be as many blobs as the
+// length in the header needs, and the first byte of every field element, the unused bytes of the header and the
+// padding must all be zero. Otherwise, an error wrapping [ErrInvalidBlobEncoding] is returned.
+func DecodeFromBlobs(blobs []Blob) ([]byte, error) {
+	if len(blobs) == 0 {
+		return nil, fmt.Errorf("%w: no blobs", ErrInvalidBlobEncoding)
+	}
+
+	// 1. Read the length of the data from the header
+	//
+	header := blobs[0][:SerializedScalarSize]
+	if !isZero(header[:SerializedScalarSize-8]) {
+		return nil, fmt.Errorf("%w: the header is not a uint64", ErrInvalidBlobEncoding)
+	}
+	length := binary.BigEndian.Uint64(header[SerializedScalarSize-8:])
+	capacity := uint64(len(blobs))*ScalarsPerBlob*bytesPerEncodedScalar - bytesPerEncodedScalar
+	if length > capacity || numEncodedBlobs(length) != uint64(len(blobs)) {
+		return nil, fmt.Errorf("%w: got %d blobs for %d bytes", ErrInvalidBlobEncoding, len(blobs), length)
+	}
+
+	// 2. Read the data from the remaining field elements, checking that the rest is zero
+	//
+	data := make([]byte, 0, length)
+	for i := 1; i < len(blobs)*ScalarsPerBlob; i++ {
+		blob := &blobs[i/ScalarsPerBlob]
+		offset := (i % ScalarsPerBlob) * SerializedScalarSize
+		scalar := blob[offset : offset+SerializedScalarSize]
+		if scalar[0] != 0 {
+			return nil, fmt.Errorf("%w: field element %d does not start with a zero byte", ErrInvalidBlobEncoding, i)
+		}
+
+		n := length - uint64(len(data))
+		if n > bytesPerEncodedScalar {
+			n = bytesPerEncodedScalar
+		}
+		data = append(data, scalar[1:1+n]...)
+		if !isZero(scalar[1+n:]) {
+			return nil, fmt.Errorf("%w: the padding in field element %d is not zero", ErrInvalidBlobEncoding, i)
+		}
+	}
+	return data, nil
+}
+
+// numEncodedBlobs returns the number of blobs that [EncodeToBlobs] encodes `length` bytes of data into.
+func numEncodedBlobs(length uint64) uint64 {
+	numScalars := 1 + (length+bytesPerEncodedScalar-1)/bytesPerEncodedScalar
+	return (numScalars + ScalarsPerBlob - 1) / ScalarsPerBlob
+}
+
+// isZero reports whether every byte of b is zero.
+func isZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
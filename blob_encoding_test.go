@@ -0,0 +1,66 @@
+package gokzg4844_test
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"testing"
+
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeToBlobs(t *testing.T) {
+	const blobCapacity = (gokzg4844.ScalarsPerBlob - 1) * 31
+	rng := rand.New(rand.NewSource(92))
+	for _, tc := range []struct {
+		length   int
+		numBlobs int
+	}{
+		{0, 1}, {1, 1}, {31, 1}, {32, 1}, {blobCapacity, 1}, {blobCapacity + 1, 2}, {blobCapacity + 4096*31, 2}, {3 * blobCapacity, 3},
+	} {
+		data := make([]byte, tc.length)
+		rng.Read(data)
+
+		blobs := gokzg4844.EncodeToBlobs(data)
+		require.Len(t, blobs, tc.numBlobs)
+		for _, blob := range blobs {
+			_, err := gokzg4844.DeserializeBlob(blob)
+			require.NoError(t, err)
+		}
+		decoded, err := gokzg4844.DecodeFromBlobs(blobs)
+		require.NoError(t, err)
+		require.Equal(t, data, decoded)
+	}
+}
+
+func TestDecodeFromBlobsInvalid(t *testing.T) {
+	data := []byte("the quick brown fox jumps over the lazy dog")
+	blobs := gokzg4844.EncodeToBlobs(data)
+
+	_, err := gokzg4844.DecodeFromBlobs(nil)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidBlobEncoding)
+
+	// A header which is too large for the blobs, or for which the blobs are too many
+	tooLong := []gokzg4844.Blob{blobs[0]}
+	binary.BigEndian.PutUint64(tooLong[0][24:32], 4096*31)
+	_, err = gokzg4844.DecodeFromBlobs(tooLong)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidBlobEncoding)
+	_, err = gokzg4844.DecodeFromBlobs(append(blobs, gokzg4844.Blob{}))
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidBlobEncoding)
+	notUint64 := []gokzg4844.Blob{blobs[0]}
+	notUint64[0][23] = 1
+	_, err = gokzg4844.DecodeFromBlobs(notUint64)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidBlobEncoding)
+
+	// Non-zero high bytes and padding
+	for _, i := range []int{32, 64, 4095 * 32} {
+		modified := []gokzg4844.Blob{blobs[0]}
+		modified[0][i] = 1
+		_, err = gokzg4844.DecodeFromBlobs(modified)
+		require.ErrorIs(t, err, gokzg4844.ErrInvalidBlobEncoding)
+	}
+	modified := []gokzg4844.Blob{blobs[0]}
+	modified[0][2*32+1+len(data)%31] = 1
+	_, err = gokzg4844.DecodeFromBlobs(modified)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidBlobEncoding)
+}
@@ -0,0 +1,374 @@
+package gokzg4844
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/crate-crypto/go-kzg-4844/internal/kzg"
+)
+
+// ScalarsPerExtBlob is the number of scalars in a blob, once it has been extended using a Reed-Solomon code of rate 1/2.
+//
+// It matches [FIELD_ELEMENTS_PER_EXT_BLOB] in the spec.
+//
+// [FIELD_ELEMENTS_PER_EXT_BLOB]: https://github.com/ethereum/consensus-specs/blob/dev/specs/_features/eip7594/polynomial-commitments-sampling.md#preset
+const ScalarsPerExtBlob = 2 * ScalarsPerBlob
+
+// ScalarsPerCell is the number of scalars in a cell.
+//
+// It matches [FIELD_ELEMENTS_PER_CELL] in the spec.
+//
+// [FIELD_ELEMENTS_PER_CELL]: https://github.com/ethereum/consensus-specs/blob/dev/specs/_features/eip7594/polynomial-commitments-sampling.md#preset
+const ScalarsPerCell = 64
+
+// CellsPerExtBlob is the number of cells that an extended blob is split into.
+//
+// It matches [CELLS_PER_EXT_BLOB] in the spec.
+//
+// [CELLS_PER_EXT_BLOB]: https://github.com/ethereum/consensus-specs/blob/dev/specs/_features/eip7594/polynomial-commitments-sampling.md#preset
+const CellsPerExtBlob = ScalarsPerExtBlob / ScalarsPerCell
+
+// Cell is a flattened representation of the serialized evaluations of a polynomial over a coset of the extended
+// domain.
+//
+// It matches [Cell] in the spec.
+//
+// [Cell]: https://github.com/ethereum/consensus-specs/blob/dev/specs/_features/eip7594/polynomial-commitments-sampling.md#custom-types
+type Cell [ScalarsPerCell * SerializedScalarSize]byte
+
+// ComputeCells implements [compute_cells]. It extends the blob to [ScalarsPerExtBlob] evaluations and splits the
+// result into [CellsPerExtBlob] cells.
+//
+// Since the extended evaluations are in bit-reversed order, the first half of the cells are the blob itself, and each
+// cell holds the evaluations over a coset of the subgroup of order [ScalarsPerCell].
+//
+// [compute_cells]: https://github.com/ethereum/consensus-specs/blob/dev/specs/_features/eip7594/polynomial-commitments-sampling.md#compute_cells
+func (c *Context) ComputeCells(blob Blob) ([CellsPerExtBlob]Cell, error) {
+	if c.closed {
+		return [CellsPerExtBlob]Cell{}, ErrContextClosed
+	}
+	if err := c.checkBlobDomain(); err != nil {
+		return [CellsPerExtBlob]Cell{}, err
+	}
+
+	// 1. Deserialization
+	//
+	polynomial, err := DeserializeBlob(blob)
+	if err != nil {
+		return [CellsPerExtBlob]Cell{}, err
+	}
+
+	// 2. Extend the polynomial
+	//
+	// Convert the polynomial to monomial form, then evaluate it over the extended domain
+	polyCoeff := c.domain.LagrangeBitReversedToMonomial(polynomial)
+	extendedEvaluations := c.extDomain.EvaluateMonomialBitReversed(polyCoeff)
+
+	// 3. Serialization
+	//
+	return serializeCells(extendedEvaluations), nil
+}
+
+// ComputeCellsAndKZGProofs implements [compute_cells_and_kzg_proofs]. It returns the same cells as
+// [Context.ComputeCells], along with the KZG proof for each of them.
+//
+// The proofs are all computed at once using the FK20 algorithm, which costs about as much as a handful of
+// commitments rather than one opening per cell. The precomputations it needs are done on the first call and kept in
+// the Context. This requires the monomial G1 points of the trusted setup, which the Context only keeps if it was
+// created with [WithMonomialSetup]; otherwise [ErrMissingMonomialSetup] is returned.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+//
+// [compute_cells_and_kzg_proofs]: https://github.com/ethereum/consensus-specs/blob/dev/specs/_features/eip7594/polynomial-commitments-sampling.md#compute_cells_and_kzg_proofs
+func (c *Context) ComputeCellsAndKZGProofs(blob Blob, numGoRoutines int) ([CellsPerExtBlob]Cell, [CellsPerExtBlob]KZGProof, error) {
+	if err := c.checkCanProve(); err != nil {
+		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, err
+	}
+	if err := c.checkBlobDomain(); err != nil {
+		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, err
+	}
+
+	// 1. Deserialization
+	//
+	polynomial, err := DeserializeBlob(blob)
+	if err != nil {
+		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, err
+	}
+
+	// 2. Compute the cells and proofs from the monomial form of the polynomial
+	polyCoeff := c.domain.LagrangeBitReversedToMonomial(polynomial)
+	return c.computeCellsAndKZGProofsFromCoeffs(polyCoeff, numGoRoutines)
+}
+
+// computeCellsAndKZGProofsFromCoeffs computes the cells and their proofs for a polynomial in monomial form.
+func (c *Context) computeCellsAndKZGProofsFromCoeffs(polyCoeff []fr.Element, numGoRoutines int) ([CellsPerExtBlob]Cell, [CellsPerExtBlob]KZGProof, error) {
+	numGoRoutines = c.goRoutines(numGoRoutines)
+	fk20, err := c.fk20.get(c.monomialG1, c.multiExpBackend, numGoRoutines)
+	if err != nil {
+		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, err
+	}
+
+	// Compute the proofs
+	stopTiming := c.startTiming(OpComputeCellProofs)
+	proofs, err := fk20.ComputeMultiProofs(polyCoeff, numGoRoutines)
+	stopTiming()
+	if err != nil {
+		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, err
+	}
+
+	// Compute the cells
+	extendedEvaluations := c.extDomain.EvaluateMonomialBitReversed(polyCoeff)
+
+	// Serialization
+	//
+	var serProofs [CellsPerExtBlob]KZGProof
+	for i := 0; i < CellsPerExtBlob; i++ {
+		serProofs[i] = KZGProof(SerializeG1Point(proofs[i]))
+	}
+
+	return serializeCells(extendedEvaluations), serProofs, nil
+}
+
+// RecoverCellsAndKZGProofs implements [recover_cells_and_kzg_proofs]. Given at least half of the cells of an extended
+// blob, it recovers all of the cells and computes their proofs, as [Context.ComputeCellsAndKZGProofs] would, and so
+// also needs a Context created with [WithMonomialSetup].
+//
+// The cells may be given in any order, but their indices must be distinct. Returns [ErrNotEnoughCells] if fewer than
+// CellsPerExtBlob/2 cells are given, and [ErrInconsistentCells] if the cells do not all come from the same blob.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+//
+// [recover_cells_and_kzg_proofs]: https://github.com/ethereum/consensus-specs/blob/dev/specs/_features/eip7594/polynomial-commitments-sampling.md#recover_cells_and_kzg_proofs
+func (c *Context) RecoverCellsAndKZGProofs(cellIndices []uint64, cells []Cell, numGoRoutines int) ([CellsPerExtBlob]Cell, [CellsPerExtBlob]KZGProof, error) {
+	if err := c.checkCanProve(); err != nil {
+		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, err
+	}
+	if err := c.checkBlobDomain(); err != nil {
+		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, err
+	}
+
+	// 1. Check the cell indices
+	if len(cellIndices) != len(cells) {
+		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, ErrCellRecoveryLengthCheck
+	}
+	var seen [CellsPerExtBlob]bool
+	for _, cellIndex := range cellIndices {
+		if cellIndex >= CellsPerExtBlob {
+			return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, ErrInvalidCellIndex
+		}
+		if seen[cellIndex] {
+			return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, ErrDuplicateCellIndex
+		}
+		seen[cellIndex] = true
+	}
+	if len(cellIndices) < CellsPerExtBlob/2 {
+		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, ErrNotEnoughCells
+	}
+
+	// 2. Deserialization
+	//
+	cosetEvaluations := make([][]fr.Element, len(cells))
+	for i := 0; i < len(cells); i++ {
+		evaluations, err := DeserializeCell(cells[i])
+		if err != nil {
+			return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, err
+		}
+		cosetEvaluations[i] = evaluations
+	}
+
+	// 3. Recover the polynomial in monomial form
+	polyCoeff, err := c.extDomain.RecoverPolynomialCoeffs(cellIndices, cosetEvaluations, ScalarsPerCell)
+	if err != nil {
+		return [CellsPerExtBlob]Cell{}, [CellsPerExtBlob]KZGProof{}, err
+	}
+
+	// 4. Compute all of the cells and proofs
+	return c.computeCellsAndKZGProofsFromCoeffs(polyCoeff, numGoRoutines)
+}
+
+// RecoverPolynomialFromEvaluations recovers the polynomial of a blob from at least [ScalarsPerBlob] of the
+// [ScalarsPerExtBlob] evaluations of its extension. This is the erasure decoding step of
+// [Context.RecoverCellsAndKZGProofs], with single evaluations instead of whole cells.
+//
+// indices[i] is the position of values[i] among the evaluations of the extended blob, in the bit-reversed order that
+// the cells hold them in, so the evaluations of cell k are at the indices k*[ScalarsPerCell] to
+// (k+1)*[ScalarsPerCell]-1. The indices must be distinct, but may be in any order. The returned polynomial is in
+// lagrange form over the domain of the blob, ie it holds the field elements of the blob.
+//
+// Returns [ErrEvaluationRecoveryLengthCheck] if the number of indices and values differ, [ErrInvalidEvaluationIndex]
+// and [ErrDuplicateEvaluationIndex] for invalid indices, [ErrNotEnoughEvaluations] if fewer than [ScalarsPerBlob]
+// evaluations are given, and [ErrInconsistentCells] if the evaluations do not all come from the same blob. If a value
+// is not canonical, the returned error contains its index and wraps [ErrNonCanonicalScalar].
+func (c *Context) RecoverPolynomialFromEvaluations(indices []uint64, values []Scalar) (kzg.Polynomial, error) {
+	if c.closed {
+		return nil, ErrContextClosed
+	}
+	if err := c.checkBlobDomain(); err != nil {
+		return nil, err
+	}
+
+	// 1. Check the indices
+	if len(indices) != len(values) {
+		return nil, ErrEvaluationRecoveryLengthCheck
+	}
+	var seen [ScalarsPerExtBlob]bool
+	for _, index := range indices {
+		if index >= ScalarsPerExtBlob {
+			return nil, ErrInvalidEvaluationIndex
+		}
+		if seen[index] {
+			return nil, ErrDuplicateEvaluationIndex
+		}
+		seen[index] = true
+	}
+	if len(indices) < ScalarsPerBlob {
+		return nil, ErrNotEnoughEvaluations
+	}
+
+	// 2. Deserialization
+	//
+	evaluations := make([][]fr.Element, len(values))
+	for i := 0; i < len(values); i++ {
+		value, err := DeserializeScalar(values[i])
+		if err != nil {
+			return nil, fmt.Errorf("value %d: %w", i, err)
+		}
+		evaluations[i] = []fr.Element{value}
+	}
+
+	// 3. Recover the polynomial in monomial form, with each evaluation as a coset of size one
+	polyCoeff, err := c.extDomain.RecoverPolynomialCoeffs(indices, evaluations, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Evaluate it over the domain of the blob
+	return c.domain.EvaluateMonomialBitReversed(polyCoeff), nil
+}
+
+// VerifyCellKZGProofBatch implements [verify_cell_kzg_proof_batch]. It verifies that each cell holds the evaluations
+// of the polynomial committed to by the corresponding commitment, over the coset of the extended domain given by the
+// cell index.
+//
+// All of the proofs are folded using random powers and checked with a single pairing check. The same commitment may
+// appear several times in the batch, for example when verifying many cells of the same blob.
+//
+// This is [Context.VerifyCellKZGProofBatchContext] with [context.Background], and so cannot be cancelled.
+//
+// [verify_cell_kzg_proof_batch]: https://github.com/ethereum/consensus-specs/blob/dev/specs/_features/eip7594/polynomial-commitments-sampling.md#verify_cell_kzg_proof_batch
+func (c *Context) VerifyCellKZGProofBatch(commitments []KZGCommitment, cellIndices []uint64, cells []Cell, proofs []KZGProof) error {
+	return c.VerifyCellKZGProofBatchContext(context.Background(), commitments, cellIndices, cells, proofs)
+}
+
+// VerifyCellKZGProofBatchContext is [Context.VerifyCellKZGProofBatch], but returns ctx.Err() once ctx is done. The
+// context is checked before each cell is deserialized, and before the final pairing check.
+func (c *Context) VerifyCellKZGProofBatchContext(ctx context.Context, commitments []KZGCommitment, cellIndices []uint64, cells []Cell, proofs []KZGProof) error {
+	if c.closed {
+		return ErrContextClosed
+	}
+
+	// 1. Check that all components in the batch have the same size
+	batchSize := len(commitments)
+	if len(cellIndices) != batchSize || len(cells) != batchSize || len(proofs) != batchSize {
+		return ErrCellBatchLengthCheck
+	}
+	if c.cellOpenKey == nil {
+		return ErrMissingMonomialSetup
+	}
+
+	// 2. Deserialize everything
+	polyCommitments := make([]kzg.Commitment, batchSize)
+	openingProofs := make([]kzg.CosetOpeningProof, batchSize)
+	for i := 0; i < batchSize; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if cellIndices[i] >= CellsPerExtBlob {
+			return ErrInvalidCellIndex
+		}
+
+		polyCommitment, err := c.deserializeKZGCommitment(commitments[i])
+		if err != nil {
+			return err
+		}
+		polyCommitments[i] = polyCommitment
+
+		quotientCommitment, err := c.deserializeKZGProof(proofs[i])
+		if err != nil {
+			return err
+		}
+
+		cosetEvaluations, err := DeserializeCell(cells[i])
+		if err != nil {
+			return err
+		}
+
+		openingProofs[i] = kzg.CosetOpeningProof{
+			QuotientCommitment: quotientCommitment,
+			CosetShift:         c.extDomain.Roots[cellIndices[i]*ScalarsPerCell],
+			CosetEvaluations:   cosetEvaluations,
+		}
+	}
+
+	// 3. Verify opening proofs
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer c.startTiming(OpBatchVerifyCells)()
+	return kzg.BatchVerifyCosetOpenings(polyCommitments, openingProofs, c.cellOpenKey)
+}
+
+// DeserializeCell converts a [Cell] into the scalars it holds. It returns [ErrNonCanonicalScalar] if any of the
+// scalars is not canonical.
+func DeserializeCell(cell Cell) ([]fr.Element, error) {
+	scalars := make([]fr.Element, ScalarsPerCell)
+	for i := 0; i < ScalarsPerCell; i++ {
+		chunk := cell[i*SerializedScalarSize : (i+1)*SerializedScalarSize]
+		scalar, err := DeserializeScalar(*(*Scalar)(chunk))
+		if err != nil {
+			return nil, err
+		}
+		scalars[i] = scalar
+	}
+	return scalars, nil
+}
+
+// lazyFK20 computes the FK20 precomputations on first use, since they take a few seconds
+// and are only needed for cell proofs.
+type lazyFK20 struct {
+	once sync.Once
+	fk20 *kzg.FK20
+	err  error
+}
+
+// get returns the FK20 precomputations for the given monomial SRS, computing them on the first call using
+// numGoRoutines go-routines. The multi exponentiations of the proofs are then computed with the given backend.
+func (l *lazyFK20) get(srsMonomial []bls12381.G1Affine, backend MultiExpBackend, numGoRoutines int) (*kzg.FK20, error) {
+	l.once.Do(func() {
+		if srsMonomial == nil {
+			l.err = ErrMissingMonomialSetup
+			return
+		}
+		l.fk20, l.err = kzg.NewFK20(srsMonomial, ScalarsPerBlob, ScalarsPerCell, numGoRoutines)
+		if l.err == nil {
+			l.fk20.SetMultiExpBackend(backend)
+		}
+	})
+	return l.fk20, l.err
+}
+
+// serializeCells splits the bit-reversed evaluations of an extended polynomial into cells.
+func serializeCells(extendedEvaluations []fr.Element) [CellsPerExtBlob]Cell {
+	var cells [CellsPerExtBlob]Cell
+	for i := 0; i < ScalarsPerExtBlob; i++ {
+		cellIndex, offset := i/ScalarsPerCell, (i%ScalarsPerCell)*SerializedScalarSize
+		serScalar := SerializeScalar(extendedEvaluations[i])
+		copy(cells[cellIndex][offset:offset+SerializedScalarSize], serScalar[:])
+	}
+	return cells
+}
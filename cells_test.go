@@ -0,0 +1,267 @@
+package gokzg4844_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	"github.com/crate-crypto/go-kzg-4844/internal/kzg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeCells(t *testing.T) {
+	blob := GetRandBlob(1)
+	cells, err := ctx.ComputeCells(blob)
+	require.NoError(t, err)
+
+	// The first half of the cells is the blob itself
+	for i := 0; i < gokzg4844.CellsPerExtBlob/2; i++ {
+		require.Equal(t, blob[i*len(cells[i]):(i+1)*len(cells[i])], cells[i][:])
+	}
+
+	// Every scalar in a cell is the evaluation of the blob polynomial at the
+	// corresponding point of the bit-reversed extended domain
+	extDomain := kzg.NewDomain(gokzg4844.ScalarsPerExtBlob)
+	extDomain.ReverseRoots()
+	for _, cellIndex := range []int{0, 63, 64, 100, 127} {
+		for _, j := range []int{0, 1, 63} {
+			point := gokzg4844.SerializeScalar(extDomain.Roots[cellIndex*gokzg4844.ScalarsPerCell+j])
+			expected, err := ctx.EvaluatePolynomialInEvaluationForm(blob, point)
+			require.NoError(t, err)
+
+			offset := j * gokzg4844.SerializedScalarSize
+			require.Equal(t, expected[:], cells[cellIndex][offset:offset+gokzg4844.SerializedScalarSize])
+		}
+	}
+}
+
+func TestComputeCellsNonCanonicalBlob(t *testing.T) {
+	blob := GetRandBlob(1)
+	modifyBlob(&blob, nonCanonicalScalar(1), 0)
+	_, err := ctx.ComputeCells(blob)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+}
+
+func TestComputeCellsAndKZGProofs(t *testing.T) {
+	blob := GetRandBlob(2)
+	expectedCells, err := ctx.ComputeCells(blob)
+	require.NoError(t, err)
+
+	cells, proofs, err := ctx.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expectedCells, cells)
+
+	// Check some of the proofs against a commitment to the quotient
+	// (f(X) - I(X)) / (X^ScalarsPerCell - c), computed by long division.
+	srsMonomial := monomialSRSFromEmbedded(t)
+	polynomial, err := gokzg4844.DeserializeBlob(blob)
+	require.NoError(t, err)
+	polyCoeff := kzg.NewDomain(gokzg4844.ScalarsPerBlob).LagrangeBitReversedToMonomial(polynomial)
+
+	extDomain := kzg.NewDomain(gokzg4844.ScalarsPerExtBlob)
+	extDomain.ReverseRoots()
+	for _, cellIndex := range []int{0, 1, 64, 127} {
+		var c fr.Element
+		c.Exp(extDomain.Roots[cellIndex*gokzg4844.ScalarsPerCell], big.NewInt(gokzg4844.ScalarsPerCell))
+
+		quotient := make([]fr.Element, len(polyCoeff)-gokzg4844.ScalarsPerCell)
+		remainder := append([]fr.Element(nil), polyCoeff...)
+		for i := len(polyCoeff) - 1; i >= gokzg4844.ScalarsPerCell; i-- {
+			quotient[i-gokzg4844.ScalarsPerCell] = remainder[i]
+			var tmp fr.Element
+			tmp.Mul(&remainder[i], &c)
+			remainder[i-gokzg4844.ScalarsPerCell].Add(&remainder[i-gokzg4844.ScalarsPerCell], &tmp)
+		}
+
+		expected, err := kzg.Commit(quotient, &kzg.CommitKey{G1: srsMonomial}, NumGoRoutines)
+		require.NoError(t, err)
+		require.Equal(t, gokzg4844.KZGProof(gokzg4844.SerializeG1Point(*expected)), proofs[cellIndex])
+	}
+}
+
+func TestComputeCellsAndKZGProofsMissingMonomialSetup(t *testing.T) {
+	setup := ethereumTrustedSetupFromEmbedded(t)
+	delete(setup, "g1_monomial")
+	lagrangeOnlyCtx, err := gokzg4844.NewContextFromJSON(bytes.NewReader(marshalJSON(t, setup)), NumGoRoutines)
+	require.NoError(t, err)
+
+	_, _, err = lagrangeOnlyCtx.ComputeCellsAndKZGProofs(GetRandBlob(3), NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrMissingMonomialSetup)
+}
+
+// monomialSRSFromEmbedded returns the monomial G1 points of the test trusted setup.
+func monomialSRSFromEmbedded(t *testing.T) []bls12381.G1Affine {
+	t.Helper()
+	data, err := os.ReadFile("trusted_setup.json")
+	require.NoError(t, err)
+	var parsedSetup gokzg4844.JSONTrustedSetup
+	require.NoError(t, json.Unmarshal(data, &parsedSetup))
+
+	points := make([]bls12381.G1Affine, len(parsedSetup.SetupG1))
+	for i, hexPoint := range parsedSetup.SetupG1 {
+		byts, err := hex.DecodeString(strings.TrimPrefix(hexPoint, "0x"))
+		require.NoError(t, err)
+		_, err = points[i].SetBytes(byts)
+		require.NoError(t, err)
+	}
+	return points
+}
+
+func TestVerifyCellKZGProofBatch(t *testing.T) {
+	var commitments []gokzg4844.KZGCommitment
+	var cellIndices []uint64
+	var cells []gokzg4844.Cell
+	var proofs []gokzg4844.KZGProof
+	for i := int64(0); i < 2; i++ {
+		blob := GetRandBlob(10 + i)
+		commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+		require.NoError(t, err)
+		blobCells, blobProofs, err := ctx.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+		require.NoError(t, err)
+
+		for _, cellIndex := range []uint64{0, 5, 64, 127} {
+			commitments = append(commitments, commitment)
+			cellIndices = append(cellIndices, cellIndex)
+			cells = append(cells, blobCells[cellIndex])
+			proofs = append(proofs, blobProofs[cellIndex])
+		}
+	}
+	require.NoError(t, ctx.VerifyCellKZGProofBatch(commitments, cellIndices, cells, proofs))
+	require.NoError(t, ctx.VerifyCellKZGProofBatch(nil, nil, nil, nil))
+
+	// A cell checked at the wrong index
+	badIndices := append([]uint64(nil), cellIndices...)
+	badIndices[1] = 6
+	require.Error(t, ctx.VerifyCellKZGProofBatch(commitments, badIndices, cells, proofs))
+
+	// A modified cell
+	badCells := append([]gokzg4844.Cell(nil), cells...)
+	badCells[2][gokzg4844.SerializedScalarSize-1] ^= 1
+	require.Error(t, ctx.VerifyCellKZGProofBatch(commitments, cellIndices, badCells, proofs))
+
+	// A cell with a non-canonical scalar
+	copy(badCells[2][:], gokzg4844.BlsModulus[:])
+	err := ctx.VerifyCellKZGProofBatch(commitments, cellIndices, badCells, proofs)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+
+	badIndices[1] = gokzg4844.CellsPerExtBlob
+	err = ctx.VerifyCellKZGProofBatch(commitments, badIndices, cells, proofs)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidCellIndex)
+
+	err = ctx.VerifyCellKZGProofBatch(commitments[1:], cellIndices, cells, proofs)
+	require.ErrorIs(t, err, gokzg4844.ErrCellBatchLengthCheck)
+}
+
+func TestRecoverCellsAndKZGProofs(t *testing.T) {
+	blob := GetRandBlob(20)
+	expectedCells, expectedProofs, err := ctx.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+	require.NoError(t, err)
+
+	// Recover from exactly half of the cells, given in descending order
+	var cellIndices []uint64
+	var cells []gokzg4844.Cell
+	for i := gokzg4844.CellsPerExtBlob - 1; i >= 0; i -= 2 {
+		cellIndices = append(cellIndices, uint64(i))
+		cells = append(cells, expectedCells[i])
+	}
+	recoveredCells, recoveredProofs, err := ctx.RecoverCellsAndKZGProofs(cellIndices, cells, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expectedCells, recoveredCells)
+	require.Equal(t, expectedProofs, recoveredProofs)
+
+	// Recover from the extension only, with some redundant cells
+	cellIndices, cells = nil, nil
+	for i := gokzg4844.CellsPerExtBlob / 2; i < gokzg4844.CellsPerExtBlob; i++ {
+		cellIndices = append(cellIndices, uint64(i))
+		cells = append(cells, expectedCells[i])
+	}
+	cellIndices = append(cellIndices, 3, 17)
+	cells = append(cells, expectedCells[3], expectedCells[17])
+	recoveredCells, recoveredProofs, err = ctx.RecoverCellsAndKZGProofs(cellIndices, cells, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expectedCells, recoveredCells)
+	require.Equal(t, expectedProofs, recoveredProofs)
+
+	// A redundant cell that does not match the others
+	badCells := append([]gokzg4844.Cell(nil), cells...)
+	badCells[len(badCells)-1][gokzg4844.SerializedScalarSize-1] ^= 1
+	_, _, err = ctx.RecoverCellsAndKZGProofs(cellIndices, badCells, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrInconsistentCells)
+
+	_, _, err = ctx.RecoverCellsAndKZGProofs(cellIndices[1:len(cellIndices)-2], cells[1:len(cells)-2], NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrNotEnoughCells)
+
+	badIndices := append([]uint64(nil), cellIndices...)
+	badIndices[0] = badIndices[1]
+	_, _, err = ctx.RecoverCellsAndKZGProofs(badIndices, cells, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrDuplicateCellIndex)
+
+	badIndices[0] = gokzg4844.CellsPerExtBlob
+	_, _, err = ctx.RecoverCellsAndKZGProofs(badIndices, cells, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidCellIndex)
+
+	_, _, err = ctx.RecoverCellsAndKZGProofs(cellIndices[1:], cells, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrCellRecoveryLengthCheck)
+}
+
+func TestRecoverPolynomialFromEvaluations(t *testing.T) {
+	blob := GetRandBlob(21)
+	expected, err := gokzg4844.DeserializeBlob(blob)
+	require.NoError(t, err)
+	cells, err := ctx.ComputeCells(blob)
+	require.NoError(t, err)
+	extEvaluations := make([]gokzg4844.Scalar, 0, gokzg4844.ScalarsPerExtBlob)
+	for _, cell := range cells {
+		for k := 0; k < gokzg4844.ScalarsPerCell; k++ {
+			var value gokzg4844.Scalar
+			copy(value[:], cell[k*gokzg4844.SerializedScalarSize:])
+			extEvaluations = append(extEvaluations, value)
+		}
+	}
+
+	// Recover from every third evaluation, along with enough of the others, in descending order
+	var indices []uint64
+	var values []gokzg4844.Scalar
+	for i := gokzg4844.ScalarsPerExtBlob - 1; i >= 0 && len(indices) < gokzg4844.ScalarsPerBlob; i-- {
+		if i%3 == 0 || i < gokzg4844.ScalarsPerExtBlob/2 {
+			indices = append(indices, uint64(i))
+			values = append(values, extEvaluations[i])
+		}
+	}
+	poly, err := ctx.RecoverPolynomialFromEvaluations(indices, values)
+	require.NoError(t, err)
+	require.Equal(t, expected, poly)
+
+	// A redundant evaluation that does not match the others
+	badIndices := append([]uint64{}, indices...)
+	badValues := append([]gokzg4844.Scalar{}, values...)
+	badIndices = append(badIndices, 1)
+	badValues = append(badValues, extEvaluations[2])
+	_, err = ctx.RecoverPolynomialFromEvaluations(badIndices, badValues)
+	require.ErrorIs(t, err, gokzg4844.ErrInconsistentCells)
+
+	_, err = ctx.RecoverPolynomialFromEvaluations(indices[1:], values[1:])
+	require.ErrorIs(t, err, gokzg4844.ErrNotEnoughEvaluations)
+	_, err = ctx.RecoverPolynomialFromEvaluations(indices, values[1:])
+	require.ErrorIs(t, err, gokzg4844.ErrEvaluationRecoveryLengthCheck)
+
+	badIndices = append([]uint64{}, indices...)
+	badIndices[0] = badIndices[1]
+	_, err = ctx.RecoverPolynomialFromEvaluations(badIndices, values)
+	require.ErrorIs(t, err, gokzg4844.ErrDuplicateEvaluationIndex)
+	badIndices[0] = gokzg4844.ScalarsPerExtBlob
+	_, err = ctx.RecoverPolynomialFromEvaluations(badIndices, values)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidEvaluationIndex)
+
+	badValues = append([]gokzg4844.Scalar{}, values...)
+	badValues[5] = gokzg4844.BlsModulus
+	_, err = ctx.RecoverPolynomialFromEvaluations(indices, badValues)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+}
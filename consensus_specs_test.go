@@ -17,13 +17,14 @@ import (
 )
 
 var (
-	testDir                      = "tests"
-	blobToKZGCommitmentTests     = filepath.Join(testDir, "blob_to_kzg_commitment/*/*/*")
-	computeKZGProofTests         = filepath.Join(testDir, "compute_kzg_proof/*/*/*")
-	computeBlobKZGProofTests     = filepath.Join(testDir, "compute_blob_kzg_proof/*/*/*")
-	verifyKZGProofTests          = filepath.Join(testDir, "verify_kzg_proof/*/*/*")
-	verifyBlobKZGProofTests      = filepath.Join(testDir, "verify_blob_kzg_proof/*/*/*")
-	verifyBlobKZGProofBatchTests = filepath.Join(testDir, "verify_blob_kzg_proof_batch/*/*/*")
+	testDir                       = "tests"
+	blobToKZGCommitmentTests      = filepath.Join(testDir, "blob_to_kzg_commitment/*/*/*")
+	computeKZGProofTests          = filepath.Join(testDir, "compute_kzg_proof/*/*/*")
+	computeBlobKZGProofTests      = filepath.Join(testDir, "compute_blob_kzg_proof/*/*/*")
+	verifyKZGProofTests           = filepath.Join(testDir, "verify_kzg_proof/*/*/*")
+	verifyBlobKZGProofTests       = filepath.Join(testDir, "verify_blob_kzg_proof/*/*/*")
+	verifyBlobKZGProofBatchTests  = filepath.Join(testDir, "verify_blob_kzg_proof_batch/*/*/*")
+	computeCellsAndKZGProofsTests = filepath.Join(testDir, "compute_cells_and_kzg_proofs/*/*/*")
 )
 
 func TestBlobToKZGCommitment(t *testing.T) {
@@ -355,6 +356,58 @@ func TestVerifyBlobKZGProofBatch(t *testing.T) {
 	}
 }
 
+func TestComputeCellsAndKZGProofsSpec(t *testing.T) {
+	type Test struct {
+		Input struct {
+			Blob string `yaml:"blob"`
+		}
+		CellsAndProofs *[2][]string `yaml:"output"`
+	}
+
+	tests, err := filepath.Glob(computeCellsAndKZGProofsTests)
+	require.True(t, len(tests) > 0)
+
+	require.NoError(t, err)
+	for _, testPath := range tests {
+		t.Run(testPath, func(t *testing.T) {
+			testFile, err := os.Open(testPath)
+			require.NoError(t, err)
+			test := Test{}
+			err = yaml.NewDecoder(testFile).Decode(&test)
+			require.NoError(t, testFile.Close())
+			require.NoError(t, err)
+			testCaseValid := test.CellsAndProofs != nil
+
+			blob, err := hexStrToBlob(test.Input.Blob)
+			if err != nil {
+				require.False(t, testCaseValid)
+				return
+			}
+			cells, proofs, err := ctx.ComputeCellsAndKZGProofs(blob, NumGoRoutines)
+			if err != nil {
+				require.False(t, testCaseValid)
+				return
+			}
+
+			require.True(t, testCaseValid)
+			require.Len(t, test.CellsAndProofs[0], gokzg4844.CellsPerExtBlob)
+			require.Len(t, test.CellsAndProofs[1], gokzg4844.CellsPerExtBlob)
+			for i := 0; i < gokzg4844.CellsPerExtBlob; i++ {
+				expectedCell, err := hexStrToCell(test.CellsAndProofs[0][i])
+				require.NoError(t, err)
+				expectedProof, err := hexStrToProof(test.CellsAndProofs[1][i])
+				require.NoError(t, err)
+				require.Equal(t, expectedCell, cells[i])
+				require.Equal(t, expectedProof, proofs[i])
+			}
+
+			onlyCells, err := ctx.ComputeCells(blob)
+			require.NoError(t, err)
+			require.Equal(t, cells, onlyCells)
+		})
+	}
+}
+
 func hexStrToBlob(hexStr string) (gokzg4844.Blob, error) {
 	var blob gokzg4844.Blob
 	byts, err := hexStrToBytes(hexStr)
@@ -383,6 +436,20 @@ func hexStrToScalar(hexStr string) (gokzg4844.Scalar, error) {
 	return scalar, nil
 }
 
+func hexStrToCell(hexStr string) (gokzg4844.Cell, error) {
+	var cell gokzg4844.Cell
+	byts, err := hexStrToBytes(hexStr)
+	if err != nil {
+		return cell, err
+	}
+
+	if len(cell) != len(byts) {
+		return cell, fmt.Errorf("cell does not have the correct length, %d ", len(byts))
+	}
+	copy(cell[:], byts)
+	return cell, nil
+}
+
 func hexStrToCommitment(hexStr string) (gokzg4844.KZGCommitment, error) {
 	point, err := hexStrToG1Point(hexStr)
 	return gokzg4844.KZGCommitment(point), err
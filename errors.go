@@ -1,9 +1,74 @@
 package gokzg4844
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/crate-crypto/go-kzg-4844/internal/kzg"
+)
 
 var (
 	ErrBatchLengthCheck               = errors.New("the number of blobs, commitments, and proofs must be the same")
-	ErrNonCanonicalScalar             = errors.New("scalar is not canonical when interpreted as a big integer in big-endian")
+	ErrKZGProofBatchLengthCheck       = errors.New("the number of commitments, input points, claimed values, and proofs must be the same")
+	ErrInvalidPolynomialSize          = kzg.ErrInvalidPolynomialSize
+	ErrPointNotInSubgroup             = errors.New("point is not in the prime-order subgroup")
+	ErrInvalidInfinityEncoding        = errors.New("point has the infinity flag set but is not the encoding of the point at infinity")
+	ErrUncompressedPoint              = errors.New("point does not have the compression flag set")
+	ErrCompressedPoint                = errors.New("point has the compression flag set but an uncompressed point was expected")
+	ErrPointNotOnCurve                = errors.New("point is not on the curve")
+	ErrMalformedHexPoint              = errors.New("point is not a 0x-prefixed hex string of the expected length")
+	ErrTrustedSetupLength             = errors.New("unexpected number of points in the trusted setup")
+	ErrSetupCacheCorrupted            = errors.New("the cached trusted setup is truncated or does not match its checksum")
+	ErrSetupCacheVersion              = errors.New("the cached trusted setup was written with an unsupported format version")
+	ErrMissingMonomialSetup           = errors.New("the monomial G1 points of the trusted setup are not available")
+	ErrCellBatchLengthCheck           = errors.New("the number of commitments, cell indices, cells, and proofs must be the same")
+	ErrInvalidCellIndex               = errors.New("cell index must be less than CellsPerExtBlob")
+	ErrCellRecoveryLengthCheck        = errors.New("the number of cell indices and cells must be the same")
+	ErrNotEnoughCells                 = errors.New("at least half of the cells are needed to recover the rest")
+	ErrDuplicateCellIndex             = errors.New("cell indices must be distinct")
+	ErrInconsistentCells              = kzg.ErrInconsistentEvaluations
+	ErrEvaluationRecoveryLengthCheck  = errors.New("the number of evaluation indices and values must be the same")
+	ErrInvalidEvaluationIndex         = errors.New("evaluation index must be less than ScalarsPerExtBlob")
+	ErrNotEnoughEvaluations           = errors.New("at least ScalarsPerBlob evaluations are needed to recover the polynomial")
+	ErrDuplicateEvaluationIndex       = errors.New("evaluation indices must be distinct")
+	ErrInterpolationLengthMismatch    = kzg.ErrInterpolationLengthMismatch
+	ErrInvalidProof                   = kzg.ErrInvalidOpeningProof
+	ErrDuplicateInterpolationPoint    = kzg.ErrDuplicateInterpolationPoint
+	ErrNonCanonicalScalar             = kzg.ErrNonCanonicalScalar
+	ErrInvalidTrustedSetup            = errors.New("the trusted setup is not internally consistent")
+	ErrContextClosed                  = errors.New("the context was closed")
+	ErrNotPowerOfTwo                  = kzg.ErrNotPowerOfTwo
+	ErrInvalidDomainSize              = kzg.ErrInvalidDomainSize
+	ErrInvalidOpeningProofLength      = kzg.ErrInvalidOpeningProofLength
+	ErrVerifyOpeningProof             = kzg.ErrVerifyOpeningProof
+	ErrScalarIndexOutOfRange          = errors.New("scalar index must be less than ScalarsPerBlob")
+	ErrDuplicateScalarIndex           = errors.New("a scalar was already added at this index")
+	ErrMissingScalars                 = errors.New("a scalar must be added at every index of the blob")
+	ErrProvingNotSupported            = errors.New("the context was created for verification only")
+	ErrVersionedHashMismatch          = errors.New("the versioned hash does not match the commitment")
+	ErrBlobCommitmentMismatch         = errors.New("the commitment to the blob does not match the expected commitment")
+	ErrSelfTestFailed                 = errors.New("the self-test did not produce the expected output")
+	ErrCommitKeyClosed                = errors.New("the commit key was closed")
+	ErrInvalidBlobEncoding            = errors.New("the blobs are not an encoding produced by EncodeToBlobs")
+	ErrInvalidBlobLength              = errors.New("the blob does not have ScalarsPerBlob * 32 bytes")
+	ErrPolynomialDoesNotVanish        = kzg.ErrPolynomialDoesNotVanish
+	ErrTooManyVanishingPoints         = errors.New("the trusted setup does not have enough G2 points for the number of points")
 	errLagrangeMonomialLengthMismatch = errors.New("the number of points in monomial SRS should equal number of points in lagrange SRS")
 )
+
+// invalidProofError is returned when the fields of a proof fail to deserialize. It matches [ErrInvalidProof] with
+// errors.Is, and unwraps to the deserialization error.
+type invalidProofError struct {
+	cause error
+}
+
+func (e invalidProofError) Error() string {
+	return ErrInvalidProof.Error() + ": " + e.cause.Error()
+}
+
+func (e invalidProofError) Unwrap() error {
+	return e.cause
+}
+
+func (e invalidProofError) Is(target error) bool {
+	return target == ErrInvalidProof
+}
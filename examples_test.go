@@ -1,14 +1,17 @@
 package gokzg4844_test
 
 import (
+	"errors"
 	"testing"
 
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
 	"github.com/stretchr/testify/require"
 )
 
 // Globally initialize a ctx for tests.
-var ctx, _ = gokzg4844.NewContext4096Insecure1337()
+var ctx, _ = gokzg4844.NewContext4096Insecure1337(gokzg4844.WithMonomialSetup())
 
 func TestBlobProveVerifyRandomPointIntegration(t *testing.T) {
 	blob := GetRandBlob(123)
@@ -20,6 +23,18 @@ func TestBlobProveVerifyRandomPointIntegration(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestComputeBlobKZGProofMismatchedCommitment(t *testing.T) {
+	blob := GetRandBlob(123)
+	otherCommitment, err := ctx.BlobToKZGCommitment(GetRandBlob(456), NumGoRoutines)
+	require.NoError(t, err)
+
+	// The commitment is not checked against the blob when proving, but the proof must not verify.
+	proof, err := ctx.ComputeBlobKZGProof(blob, otherCommitment, NumGoRoutines)
+	require.NoError(t, err)
+	err = ctx.VerifyBlobKZGProof(blob, otherCommitment, proof)
+	require.Error(t, err)
+}
+
 func TestBlobProveVerifySpecifiedPointIntegration(t *testing.T) {
 	blob := GetRandBlob(123)
 	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
@@ -31,6 +46,41 @@ func TestBlobProveVerifySpecifiedPointIntegration(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestComputeKZGProofClaimedValue(t *testing.T) {
+	// A blob where every evaluation is the same value c, is the constant polynomial f(X) = c.
+	// Hence, the claimed value must be c, regardless of the input point.
+	constant := GetRandFieldElement(42)
+	var blob gokzg4844.Blob
+	for i := 0; i < gokzg4844.ScalarsPerBlob; i++ {
+		copy(blob[i*gokzg4844.SerializedScalarSize:], constant[:])
+	}
+
+	inputPoint := GetRandFieldElement(123)
+	_, claimedValue, err := ctx.ComputeKZGProof(blob, inputPoint, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, gokzg4844.Scalar(constant), claimedValue)
+}
+
+func TestEvaluatePolynomialInEvaluationForm(t *testing.T) {
+	blob := GetRandBlob(99)
+
+	// Outside of the domain, this must match the claimed value of a proof
+	inputPoint := GetRandFieldElement(99)
+	_, claimedValue, err := ctx.ComputeKZGProof(blob, inputPoint, NumGoRoutines)
+	require.NoError(t, err)
+	evaluation, err := ctx.EvaluatePolynomialInEvaluationForm(blob, inputPoint)
+	require.NoError(t, err)
+	require.Equal(t, claimedValue, evaluation)
+
+	// The first root of unity is 1, which is in the domain. Since the domain
+	// is bit-reversed, its evaluation is the first scalar in the blob.
+	var one gokzg4844.Scalar
+	one[gokzg4844.SerializedScalarSize-1] = 1
+	evaluation, err = ctx.EvaluatePolynomialInEvaluationForm(blob, one)
+	require.NoError(t, err)
+	require.Equal(t, blob[:gokzg4844.SerializedScalarSize], evaluation[:])
+}
+
 func TestBlobProveVerifyBatchIntegration(t *testing.T) {
 	batchSize := 5
 	blobs := make([]gokzg4844.Blob, batchSize)
@@ -51,3 +101,207 @@ func TestBlobProveVerifyBatchIntegration(t *testing.T) {
 	err := ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs)
 	require.NoError(t, err)
 }
+
+func TestVerifyBlobKZGProofBatchMalformedInput(t *testing.T) {
+	batchSize := 3
+	blobs := make([]gokzg4844.Blob, batchSize)
+	commitments := make([]gokzg4844.KZGCommitment, batchSize)
+	proofs := make([]gokzg4844.KZGProof, batchSize)
+	for i := 0; i < batchSize; i++ {
+		blobs[i] = GetRandBlob(int64(30 + i))
+		commitment, err := ctx.BlobToKZGCommitment(blobs[i], NumGoRoutines)
+		require.NoError(t, err)
+		proof, err := ctx.ComputeBlobKZGProof(blobs[i], commitment, NumGoRoutines)
+		require.NoError(t, err)
+		commitments[i] = commitment
+		proofs[i] = proof
+	}
+
+	// A wrong proof is a verification failure
+	proofs[0], proofs[1] = proofs[1], proofs[0]
+	err := ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+	require.ErrorIs(t, err, gokzg4844.ErrVerifyOpeningProof)
+
+	// Malformed inputs are reported with their index, even if a proof is also wrong
+	badBlobs := append([]gokzg4844.Blob(nil), blobs...)
+	modifyBlob(&badBlobs[2], nonCanonicalScalar(1), 0)
+	err = ctx.VerifyBlobKZGProofBatch(badBlobs, commitments, proofs)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+	require.ErrorContains(t, err, "blob 2")
+
+	// The infinity flag with non-zero coordinates is an invalid encoding
+	var invalidPoint [48]byte
+	invalidPoint[0] = 0xc0
+	invalidPoint[47] = 1
+
+	badCommitments := append([]gokzg4844.KZGCommitment(nil), commitments...)
+	badCommitments[1] = invalidPoint
+	err = ctx.VerifyBlobKZGProofBatch(blobs, badCommitments, proofs)
+	require.Error(t, err)
+	require.False(t, errors.Is(err, gokzg4844.ErrVerifyOpeningProof))
+	require.ErrorContains(t, err, "commitment 1")
+
+	badProofs := append([]gokzg4844.KZGProof(nil), proofs...)
+	badProofs[2] = invalidPoint
+	err = ctx.VerifyBlobKZGProofBatch(blobs, commitments, badProofs)
+	require.Error(t, err)
+	require.False(t, errors.Is(err, gokzg4844.ErrVerifyOpeningProof))
+	require.ErrorContains(t, err, "proof 2")
+}
+
+func TestKZGProofBatchIntegration(t *testing.T) {
+	batchSize := 5
+	commitments := make([]gokzg4844.KZGCommitment, batchSize)
+	inputPoints := make([]gokzg4844.Scalar, batchSize)
+	claimedValues := make([]gokzg4844.Scalar, batchSize)
+	proofs := make([]gokzg4844.KZGProof, batchSize)
+
+	for i := 0; i < batchSize; i++ {
+		blob := GetRandBlob(int64(i))
+		commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+		require.NoError(t, err)
+		inputPoint := GetRandFieldElement(int64(i))
+		proof, claimedValue, err := ctx.ComputeKZGProof(blob, inputPoint, NumGoRoutines)
+		require.NoError(t, err)
+
+		commitments[i] = commitment
+		inputPoints[i] = inputPoint
+		claimedValues[i] = claimedValue
+		proofs[i] = proof
+	}
+	err := ctx.VerifyKZGProofBatch(commitments, inputPoints, claimedValues, proofs)
+	require.NoError(t, err)
+
+	// An empty batch is trivially valid
+	err = ctx.VerifyKZGProofBatch(nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	// Mismatched lengths should be rejected
+	err = ctx.VerifyKZGProofBatch(commitments, inputPoints[1:], claimedValues, proofs)
+	require.ErrorIs(t, err, gokzg4844.ErrKZGProofBatchLengthCheck)
+
+	// Swapping two claimed values should invalidate the batch
+	claimedValues[0], claimedValues[1] = claimedValues[1], claimedValues[0]
+	err = ctx.VerifyKZGProofBatch(commitments, inputPoints, claimedValues, proofs)
+	require.Error(t, err)
+}
+
+func TestVerifyKZGProofWithCommitment(t *testing.T) {
+	blob := GetRandBlob(25)
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+
+	// Deserialize the commitment once, and verify several proofs against it
+	deserializedCommitment, err := ctx.DeserializeCommitment(commitment)
+	require.NoError(t, err)
+	for i := int64(0); i < 3; i++ {
+		inputPoint := GetRandFieldElement(i)
+		proof, claimedValue, err := ctx.ComputeKZGProof(blob, inputPoint, NumGoRoutines)
+		require.NoError(t, err)
+		require.NoError(t, ctx.VerifyKZGProofWithCommitment(deserializedCommitment, inputPoint, claimedValue, proof))
+
+		// A wrong claimed value must be rejected
+		err = ctx.VerifyKZGProofWithCommitment(deserializedCommitment, inputPoint, inputPoint, proof)
+		require.Error(t, err)
+	}
+
+	badCommitment := gokzg4844.KZGCommitment(gokzg4844.SerializeG1Point(g1PointNotInSubgroup()))
+	_, err = ctx.DeserializeCommitment(badCommitment)
+	require.ErrorIs(t, err, gokzg4844.ErrPointNotInSubgroup)
+}
+
+func TestVerifyBlobKZGProofDebug(t *testing.T) {
+	blob := GetRandBlob(26)
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	proof, err := ctx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+
+	z, y, ok, err := ctx.VerifyBlobKZGProofDebug(blob, commitment, proof)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// The blob proof is the proof of the evaluation y at z
+	pointProof, claimedValue, err := ctx.ComputeKZGProof(blob, z, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, claimedValue, y)
+	require.Equal(t, proof, pointProof)
+
+	// A proof for another commitment does not verify, but the evaluation point is still returned
+	otherCommitment, err := ctx.BlobToKZGCommitment(GetRandBlob(27), NumGoRoutines)
+	require.NoError(t, err)
+	otherZ, _, ok, err := ctx.VerifyBlobKZGProofDebug(blob, otherCommitment, proof)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.NotEqual(t, z, otherZ)
+
+	// A malformed blob is an error
+	blobBad := blob
+	copy(blobBad[:gokzg4844.SerializedScalarSize], gokzg4844.BlsModulus[:])
+	_, _, ok, err = ctx.VerifyBlobKZGProofDebug(blobBad, commitment, proof)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+	require.False(t, ok)
+}
+
+func TestComputeChallenge(t *testing.T) {
+	// Test vector computed independently as
+	// sha256(b"FSBLOBVERIFY_V1_" + int.to_bytes(4096, 16, 'big') + blob + commitment) mod BLS_MODULUS
+	// with blob[i] = i % 256 and the commitment being the generator of G1.
+	var blob gokzg4844.Blob
+	for i := 0; i < len(blob); i++ {
+		blob[i] = byte(i)
+	}
+	_, _, genG1, _ := bls12381.Generators()
+	commitment := gokzg4844.KZGCommitment(gokzg4844.SerializeG1Point(genG1))
+	expected := gokzg4844.Scalar{
+		0x2a, 0x9a, 0xf4, 0x48, 0x1f, 0x97, 0x8d, 0x07,
+		0xb8, 0x7c, 0xfa, 0x3b, 0x56, 0x7e, 0x94, 0x1a,
+		0x58, 0x1a, 0xeb, 0x37, 0x30, 0xb3, 0xf4, 0x34,
+		0x43, 0x03, 0x92, 0xbf, 0x22, 0xf9, 0x74, 0xff,
+	}
+	require.Equal(t, expected, ctx.ComputeChallenge(blob, commitment))
+
+	// It is the challenge used when verifying a blob proof
+	blob = GetRandBlob(28)
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	proof, err := ctx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+	z, _, ok, err := ctx.VerifyBlobKZGProofDebug(blob, commitment, proof)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, z, ctx.ComputeChallenge(blob, commitment))
+}
+
+func TestZeroBlobIntegration(t *testing.T) {
+	var blob gokzg4844.Blob
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, gokzg4844.KZGCommitment(gokzg4844.PointAtInfinity), commitment)
+
+	proof, err := ctx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, gokzg4844.KZGProof(gokzg4844.PointAtInfinity), proof)
+	require.NoError(t, ctx.VerifyBlobKZGProof(blob, commitment, proof))
+
+	inputPoint := GetRandFieldElement(31)
+	proof, claimedValue, err := ctx.ComputeKZGProof(blob, inputPoint, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, gokzg4844.Scalar{}, claimedValue)
+	require.NoError(t, ctx.VerifyKZGProof(commitment, inputPoint, claimedValue, proof))
+
+	// A non-zero claimed value must be rejected
+	one := gokzg4844.SerializeScalar(fr.One())
+	require.Error(t, ctx.VerifyKZGProof(commitment, inputPoint, one, proof))
+
+	// Padding blobs may be batched with other blobs
+	otherBlob := GetRandBlob(31)
+	otherCommitment, err := ctx.BlobToKZGCommitment(otherBlob, NumGoRoutines)
+	require.NoError(t, err)
+	otherProof, err := ctx.ComputeBlobKZGProof(otherBlob, otherCommitment, NumGoRoutines)
+	require.NoError(t, err)
+	blobProof, err := ctx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+	err = ctx.VerifyBlobKZGProofBatch([]gokzg4844.Blob{blob, otherBlob}, []gokzg4844.KZGCommitment{commitment, otherCommitment}, []gokzg4844.KZGProof{blobProof, otherProof})
+	require.NoError(t, err)
+}
@@ -3,6 +3,7 @@ package gokzg4844
 import (
 	"crypto/sha256"
 	"encoding/binary"
+	"hash"
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 )
@@ -14,27 +15,49 @@ import (
 // [FIAT_SHAMIR_PROTOCOL_DOMAIN]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#blob
 const DomSepProtocol = "FSBLOBVERIFY_V1_"
 
+// ComputeChallenge returns the evaluation challenge `z` that [Context.ComputeBlobKZGProof] and
+// [Context.VerifyBlobKZGProof] derive from the blob and its commitment, following [compute_challenge].
+//
+// By default, this is the SHA-256 hash of [DomSepProtocol], the degree of the polynomial as 16 big endian bytes, the
+// blob and the commitment, reduced modulo the scalar field order. The hash is configured with [WithChallengeHash]. It
+// is exposed to compare the Fiat-Shamir transcript with other implementations, and so neither the blob nor the
+// commitment is validated.
+//
+// [compute_challenge]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#compute_challenge
+func (c *Context) ComputeChallenge(blob Blob, commitment KZGCommitment) Scalar {
+	challenge := computeChallenge(c.newChallengeHash, blob, commitment)
+	return SerializeScalar(challenge)
+}
+
 // computeChallenge is provided to match the spec at [compute_challenge].
 //
+// The data is hashed with the hash returned by newHash, or with SHA-256 if newHash is nil.
+//
 // [compute_challenge]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#compute_challenge
-func computeChallenge(blob Blob, commitment KZGCommitment) fr.Element {
+func computeChallenge(newHash func() hash.Hash, blob Blob, commitment KZGCommitment) fr.Element {
 	polyDegreeBytes := u64ToByteArray16(ScalarsPerBlob)
 	data := append([]byte(DomSepProtocol), polyDegreeBytes...)
 	data = append(data, blob[:]...)
 	data = append(data, commitment[:]...)
 
-	return hashToBLSField(data)
+	return hashToBLSField(newHash, data)
 }
 
 // hashToBLSField hashed the given binary data to a field element according to [hash_to_bls_field].
+// The hash returned by newHash is used in place of SHA-256 if newHash is not nil.
 //
 // [hash_to_bls_field]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#hash_to_bls_field
-func hashToBLSField(data []byte) fr.Element {
-	digest := sha256.Sum256(data)
+func hashToBLSField(newHash func() hash.Hash, data []byte) fr.Element {
+	if newHash == nil {
+		newHash = sha256.New
+	}
+	h := newHash()
+	h.Write(data)
+	digest := h.Sum(nil)
 
 	// Now interpret those bytes as a field element
 	var challenge fr.Element
-	challenge.SetBytes(digest[:])
+	challenge.SetBytes(digest)
 
 	return challenge
 }
@@ -0,0 +1,90 @@
+package gokzg4844
+
+import (
+	"fmt"
+	"math/big"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// AddCommitments returns the commitment to f1 + f2, given commitments c1 to f1 and c2 to f2, by adding them in G1.
+// Commitments are linear in the polynomials they commit to, so this does not need either polynomial. Both commitments
+// must have been made with the same trusted setup, over the same domain, for the result to be meaningful.
+//
+// The commitments are deserialized with [DeserializeKZGCommitment], so they are always checked to be in the
+// prime-order subgroup. If one of them is malformed, the returned error says which one, as in "commitment 2: ...".
+func AddCommitments(c1, c2 KZGCommitment) (KZGCommitment, error) {
+	// 1. Deserialization
+	//
+	point1, err := DeserializeKZGCommitment(c1)
+	if err != nil {
+		return KZGCommitment{}, fmt.Errorf("commitment 1: %w", err)
+	}
+	point2, err := DeserializeKZGCommitment(c2)
+	if err != nil {
+		return KZGCommitment{}, fmt.Errorf("commitment 2: %w", err)
+	}
+
+	// 2. Add the commitments
+	var sum bls12381.G1Affine
+	sum.Add(&point1, &point2)
+
+	// 3. Serialization
+	//
+	return KZGCommitment(SerializeG1Point(sum)), nil
+}
+
+// ScaleCommitment returns the commitment to s * f, given a commitment c to f, by multiplying it by s in G1. Together
+// with [AddCommitments], this gives the commitment to any linear combination of polynomials from their commitments.
+//
+// The commitment is deserialized with [DeserializeKZGCommitment], so it is always checked to be in the prime-order
+// subgroup. Returns [ErrNonCanonicalScalar] if s is not canonical.
+func ScaleCommitment(c KZGCommitment, s Scalar) (KZGCommitment, error) {
+	// 1. Deserialization
+	//
+	point, err := DeserializeKZGCommitment(c)
+	if err != nil {
+		return KZGCommitment{}, err
+	}
+	scalar, err := DeserializeScalar(s)
+	if err != nil {
+		return KZGCommitment{}, err
+	}
+
+	// 2. Scale the commitment
+	var scalarBigInt big.Int
+	scalar.BigInt(&scalarBigInt)
+	var scaled bls12381.G1Affine
+	scaled.ScalarMultiplication(&point, &scalarBigInt)
+
+	// 3. Serialization
+	//
+	return KZGCommitment(SerializeG1Point(scaled)), nil
+}
+
+// ComputePowers returns the first n powers of r, ie [1, r, r^2, ..., r^(n-1)], as used to fold commitments with
+// [ScaleCommitment] and [AddCommitments] by the powers of a single challenge. The powers are computed with a running
+// product, which takes n multiplications, and n <= 0 gives an empty slice.
+//
+// Returns [ErrNonCanonicalScalar] if r is not canonical.
+func ComputePowers(r Scalar, n int) ([]Scalar, error) {
+	// 1. Deserialization
+	//
+	x, err := DeserializeScalar(r)
+	if err != nil {
+		return nil, err
+	}
+	if n <= 0 {
+		return []Scalar{}, nil
+	}
+
+	// 2. Compute the powers, serializing each one as it is computed
+	powers := make([]Scalar, n)
+	power := fr.One()
+	for i := 0; i < n; i++ {
+		powers[i] = SerializeScalar(power)
+		power.Mul(&power, &x)
+	}
+	return powers, nil
+}
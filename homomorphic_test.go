@@ -0,0 +1,84 @@
+package gokzg4844_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddAndScaleCommitments(t *testing.T) {
+	blob1, blob2 := GetRandBlob(120), GetRandBlob(121)
+	c1, err := ctx.BlobToKZGCommitment(blob1, NumGoRoutines)
+	require.NoError(t, err)
+	c2, err := ctx.BlobToKZGCommitment(blob2, NumGoRoutines)
+	require.NoError(t, err)
+
+	// The blobs of f1 + f2 and s*f1, computed from the field elements of both blobs
+	s := gokzg4844.Scalar(GetRandFieldElement(122))
+	scalar, err := gokzg4844.DeserializeScalar(s)
+	require.NoError(t, err)
+	poly1, err := gokzg4844.DeserializeBlob(blob1)
+	require.NoError(t, err)
+	poly2, err := gokzg4844.DeserializeBlob(blob2)
+	require.NoError(t, err)
+	sum := make([]fr.Element, gokzg4844.ScalarsPerBlob)
+	scaled := make([]fr.Element, gokzg4844.ScalarsPerBlob)
+	for i := 0; i < gokzg4844.ScalarsPerBlob; i++ {
+		sum[i].Add(&poly1[i], &poly2[i])
+		scaled[i].Mul(&poly1[i], &scalar)
+	}
+
+	expectedSum, err := ctx.BlobToKZGCommitment(gokzg4844.SerializePoly(sum), NumGoRoutines)
+	require.NoError(t, err)
+	gotSum, err := gokzg4844.AddCommitments(c1, c2)
+	require.NoError(t, err)
+	require.Equal(t, expectedSum, gotSum)
+
+	expectedScaled, err := ctx.BlobToKZGCommitment(gokzg4844.SerializePoly(scaled), NumGoRoutines)
+	require.NoError(t, err)
+	gotScaled, err := gokzg4844.ScaleCommitment(c1, s)
+	require.NoError(t, err)
+	require.Equal(t, expectedScaled, gotScaled)
+
+	// Adding the point at infinity, or scaling by zero, are allowed
+	gotSum, err = gokzg4844.AddCommitments(c1, gokzg4844.PointAtInfinity)
+	require.NoError(t, err)
+	require.Equal(t, c1, gotSum)
+	gotScaled, err = gokzg4844.ScaleCommitment(c1, gokzg4844.Scalar{})
+	require.NoError(t, err)
+	require.Equal(t, gokzg4844.KZGCommitment(gokzg4844.PointAtInfinity), gotScaled)
+
+	notInSubgroup := gokzg4844.KZGCommitment(gokzg4844.SerializeG1Point(g1PointNotInSubgroup()))
+	_, err = gokzg4844.AddCommitments(c1, notInSubgroup)
+	require.ErrorIs(t, err, gokzg4844.ErrPointNotInSubgroup)
+	require.ErrorContains(t, err, "commitment 2")
+	_, err = gokzg4844.ScaleCommitment(notInSubgroup, s)
+	require.ErrorIs(t, err, gokzg4844.ErrPointNotInSubgroup)
+	_, err = gokzg4844.ScaleCommitment(c1, nonCanonicalScalar(122))
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+}
+
+func TestComputePowers(t *testing.T) {
+	r := GetRandFieldElement(123)
+	powers, err := gokzg4844.ComputePowers(r, 10)
+	require.NoError(t, err)
+	require.Len(t, powers, 10)
+
+	// Each power is computed independently as r^i mod the scalar field modulus
+	rBigInt := new(big.Int).SetBytes(r[:])
+	for i := range powers {
+		expected := new(big.Int).Exp(rBigInt, big.NewInt(int64(i)), fr.Modulus())
+		var expectedScalar gokzg4844.Scalar
+		expected.FillBytes(expectedScalar[:])
+		require.Equal(t, expectedScalar, powers[i])
+	}
+
+	powers, err = gokzg4844.ComputePowers(r, 0)
+	require.NoError(t, err)
+	require.Len(t, powers, 0)
+	_, err = gokzg4844.ComputePowers(nonCanonicalScalar(123), 10)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+}
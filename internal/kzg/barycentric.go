@@ -0,0 +1,83 @@
+package kzg
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// BarycentricWeights holds the weights of the barycentric formula for evaluating polynomials in evaluation form over a
+// domain at a fixed point z. They only depend on z and the domain, so computing them once with [PrecomputeBarycentric]
+// and then calling [EvaluateWithWeights] for each polynomial saves the batch inversion and the exponentiation that
+// [Domain.EvaluateLagrangePolynomial] does on every call.
+type BarycentricWeights struct {
+	// cardinality is the size of the domain that the weights were computed for.
+	cardinality uint64
+	// indexInDomain is the index of z in the domain, or -1 if z is not in the domain.
+	// For a domain with a single point, it is always 0, since the polynomials are constant.
+	indexInDomain int64
+	// weights[i] is root_i / (z - root_i) * (z^n - 1) / n, where n is the cardinality of the domain.
+	// It is nil if z is in the domain.
+	weights []fr.Element
+}
+
+// PrecomputeBarycentric computes the weights for evaluating polynomials in evaluation form over the domain at z, with
+// [EvaluateWithWeights]. The roots of the domain may be in either order, as long as the polynomials use the same one.
+func PrecomputeBarycentric(z fr.Element, domain *Domain) *BarycentricWeights {
+	w := &BarycentricWeights{cardinality: domain.Cardinality}
+
+	// Over a domain with a single point, the polynomial is constant
+	if domain.Cardinality == 1 {
+		w.indexInDomain = 0
+		return w
+	}
+
+	// If z is in the domain, the evaluation is the one at its index
+	w.indexInDomain = domain.findRootIndex(z)
+	if w.indexInDomain != -1 {
+		return w
+	}
+
+	denom := make([]fr.Element, domain.Cardinality)
+	for i := range denom {
+		denom[i].Sub(&z, &domain.Roots[i])
+	}
+	w.weights = fr.BatchInvert(denom)
+
+	// (z^n - 1) * 1/n
+	var factor fr.Element
+	factor.Exp(z, big.NewInt(0).SetUint64(domain.Cardinality))
+	one := fr.One()
+	factor.Sub(&factor, &one)
+	factor.Mul(&factor, &domain.CardinalityInv)
+
+	for i := range w.weights {
+		w.weights[i].Mul(&w.weights[i], &domain.Roots[i])
+		w.weights[i].Mul(&w.weights[i], &factor)
+	}
+	return w
+}
+
+// EvaluateWithWeights evaluates the polynomial, given in evaluation form, at the point that the weights were computed
+// for. The result is the same as [Domain.EvaluateLagrangePolynomial] returns for that point, at the cost of a single
+// multiplication per evaluation.
+//
+// Returns [ErrPolynomialMismatchedSizeDomain] if the number of evaluations is not the size of the domain that the
+// weights were computed for.
+func EvaluateWithWeights(poly Polynomial, weights *BarycentricWeights) (*fr.Element, error) {
+	if weights.cardinality != uint64(len(poly)) {
+		return nil, ErrPolynomialMismatchedSizeDomain
+	}
+
+	if weights.indexInDomain != -1 {
+		result := poly[weights.indexInDomain]
+		return &result, nil
+	}
+
+	var result, tmp fr.Element
+	for i := 0; i < len(poly); i++ {
+		tmp.Mul(&poly[i], &weights.weights[i])
+		result.Add(&result, &tmp)
+	}
+	return &result, nil
+}
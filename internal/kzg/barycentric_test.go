@@ -0,0 +1,62 @@
+package kzg
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateWithWeights(t *testing.T) {
+	for _, size := range []uint64{1, 2, 16} {
+		domain := NewDomain(size)
+		domain.ReverseRoots()
+
+		points := []fr.Element{*samplePointOutsideDomain(*domain), domain.Roots[size-1]}
+		for _, z := range points {
+			weights := PrecomputeBarycentric(z, domain)
+			for i := 0; i < 3; i++ {
+				poly := randPoly(t, *domain)
+				expected, err := domain.EvaluateLagrangePolynomial(poly, z)
+				require.NoError(t, err)
+				got, err := EvaluateWithWeights(poly, weights)
+				require.NoError(t, err)
+				require.True(t, expected.Equal(got))
+			}
+		}
+
+		weights := PrecomputeBarycentric(points[0], domain)
+		_, err := EvaluateWithWeights(make(Polynomial, size+1), weights)
+		require.ErrorIs(t, err, ErrPolynomialMismatchedSizeDomain)
+	}
+}
+
+func BenchmarkEvaluateWithWeights(b *testing.B) {
+	const numPolys = 100
+	domain := NewDomain(4096)
+	polys := make([]Polynomial, numPolys)
+	for i := range polys {
+		polys[i] = make(Polynomial, domain.Cardinality)
+		for j := range polys[i] {
+			polys[i][j].SetUint64(uint64(i*len(polys[i]) + j))
+		}
+	}
+	var z fr.Element
+	z.SetUint64(123456789)
+
+	b.Run("EvaluateLagrangePolynomial", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			for i := range polys {
+				_, _ = domain.EvaluateLagrangePolynomial(polys[i], z)
+			}
+		}
+	})
+	b.Run("EvaluateWithWeights", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			weights := PrecomputeBarycentric(z, domain)
+			for i := range polys {
+				_, _ = EvaluateWithWeights(polys[i], weights)
+			}
+		}
+	})
+}
@@ -0,0 +1,225 @@
+package kzg
+
+import (
+	"crypto/rand"
+	"io"
+	"math/big"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/crate-crypto/go-kzg-4844/internal/multiexp"
+	"github.com/crate-crypto/go-kzg-4844/internal/utils"
+)
+
+// CosetOpeningKey is the key used to verify opening proofs over cosets of the multiplicative subgroup H of order
+// CosetSize, as computed by [FK20.ComputeMultiProofs].
+type CosetOpeningKey struct {
+	// This is the degree-0 G_2 element in the trusted setup.
+	GenG2 bls12381.G2Affine
+	// This is the degree-CosetSize G_2 element in the trusted setup.
+	// It is used to commit to the vanishing polynomial of a coset.
+	SPowCosetSizeG2 bls12381.G2Affine
+	// These are the first CosetSize G_1 elements of the monomial SRS.
+	// They are used to commit to the polynomials interpolating each coset.
+	G1 []bls12381.G1Affine
+
+	// cosetDomain is the subgroup H.
+	cosetDomain *Domain
+	// backend computes the multi exponentiations needed to batch verify proofs.
+	// It is nil by default, which means that gnark-crypto is used.
+	backend multiexp.Backend
+	// numGoRoutines is the number of go-routines that the multi exponentiations needed to batch verify proofs use.
+	// It is 0 by default, which means the number of CPUs.
+	numGoRoutines int
+	// dedupCommitments is set if identical commitments of a batch are merged before they are folded.
+	dedupCommitments bool
+}
+
+// SetMultiExpBackend sets the backend used for the multi exponentiations needed to verify proofs.
+// A nil backend restores the default.
+func (openKey *CosetOpeningKey) SetMultiExpBackend(backend multiexp.Backend) {
+	openKey.backend = backend
+}
+
+// SetNumGoRoutines sets the number of go-routines used by the multi exponentiations needed to batch verify proofs. A
+// negative number or 0 restores the default, which is the number of CPUs.
+func (openKey *CosetOpeningKey) SetNumGoRoutines(numGoRoutines int) {
+	openKey.numGoRoutines = numGoRoutines
+}
+
+// SetDedupCommitments sets whether batch verification merges identical commitments before folding them. See
+// [OpeningKey.SetDedupCommitments].
+func (openKey *CosetOpeningKey) SetDedupCommitments(dedup bool) {
+	openKey.dedupCommitments = dedup
+}
+
+// CosetOpeningProof is a struct holding a (cryptographic) proof to the claim that a polynomial f(X) (represented by a
+// commitment to it) evaluates to the given values over the coset h*H.
+type CosetOpeningProof struct {
+	// Commitment to the quotient polynomial (f(X) - I(X))/(X^CosetSize - h^CosetSize),
+	// where I(X) interpolates f(X) over the coset.
+	QuotientCommitment bls12381.G1Affine
+
+	// CosetShift is the element `h` such that the coset is h*H.
+	CosetShift fr.Element
+
+	// CosetEvaluations are the purported values of f(X) over the coset. The i'th value is the evaluation at h*w^j,
+	// where w is the generator of H and j is the bit-reversal of i.
+	//
+	// This is the order in which the evaluations over a coset appear in the bit-reversed extended domain.
+	CosetEvaluations []fr.Element
+}
+
+// NewCosetOpeningKey creates the key for verifying opening proofs over cosets of size cosetSize, from the monomial SRS
+// in G1 and G2. The SRS in G2 must have more than cosetSize points.
+func NewCosetOpeningKey(srsMonomialG1 []bls12381.G1Affine, srsG2 []bls12381.G2Affine, cosetSize int) (*CosetOpeningKey, error) {
+	if !utils.IsPowerOfTwo(uint64(cosetSize)) {
+		return nil, ErrInvalidPolynomialSize
+	}
+	if len(srsMonomialG1) < cosetSize || len(srsG2) <= cosetSize {
+		return nil, ErrMinSRSSize
+	}
+
+	g1 := make([]bls12381.G1Affine, cosetSize)
+	copy(g1, srsMonomialG1)
+
+	return &CosetOpeningKey{
+		GenG2:           srsG2[0],
+		SPowCosetSizeG2: srsG2[cosetSize],
+		G1:              g1,
+		cosetDomain:     NewDomain(uint64(cosetSize)),
+	}, nil
+}
+
+// BatchVerifyCosetOpenings verifies multiple coset opening proofs in a batch, using a single pairing check.
+//
+// This is [BatchVerifyCosetOpeningsWithRand] with the randomness taken from crypto/rand.
+func BatchVerifyCosetOpenings(commitments []Commitment, proofs []CosetOpeningProof, openKey *CosetOpeningKey) error {
+	return BatchVerifyCosetOpeningsWithRand(commitments, proofs, openKey, rand.Reader)
+}
+
+// BatchVerifyCosetOpeningsWithRand verifies multiple coset opening proofs in a batch, deriving the random folding
+// factors from `randReader`.
+//
+// Each proof satisfies e(π, [s^n - h^n]G₂) = e(C - [I(s)]G₁, G₂), where n is the coset size. Folding these with powers
+// of a random r_i, this becomes the single check:
+//
+//	e(Σ r_i C_i - [Σ r_i I_i(s)]G₁ + Σ r_i h_i^n π_i, G₂) = e(Σ r_i π_i, [s^n]G₂)
+//
+// where the interpolation polynomials I_i(X) are summed before committing to them, so that only one multi
+// exponentiation over the first n points of the SRS is needed.
+func BatchVerifyCosetOpeningsWithRand(commitments []Commitment, proofs []CosetOpeningProof, openKey *CosetOpeningKey, randReader io.Reader) error {
+	if len(commitments) != len(proofs) {
+		return ErrInvalidNumDigests
+	}
+	batchSize := len(commitments)
+
+	// If there is nothing to verify, we return nil
+	// to signal that verification was true.
+	if batchSize == 0 {
+		return nil
+	}
+
+	cosetSize := int(openKey.cosetDomain.Cardinality)
+	for i := 0; i < batchSize; i++ {
+		if len(proofs[i].CosetEvaluations) != cosetSize {
+			return ErrPolynomialMismatchedSizeDomain
+		}
+	}
+
+	randomNumber, err := sampleScalar(randReader)
+	if err != nil {
+		return err
+	}
+	randomNumbers := utils.ComputePowers(randomNumber, uint(batchSize))
+
+	// Combine random_i*quotient_i
+	quotients := make([]bls12381.G1Affine, batchSize)
+	for i := 0; i < batchSize; i++ {
+		quotients[i].Set(&proofs[i].QuotientCommitment)
+	}
+	foldedQuotients, err := multiexp.MultiExpWithBackend(openKey.backend, randomNumbers, quotients, openKey.numGoRoutines)
+	if err != nil {
+		return err
+	}
+
+	// Combine random_i*commitment_i
+	commitmentFactors := randomNumbers
+	if openKey.dedupCommitments {
+		commitments, commitmentFactors = dedupCommitments(commitments, randomNumbers)
+	}
+	foldedCommitments, err := multiexp.MultiExpWithBackend(openKey.backend, commitmentFactors, commitments, openKey.numGoRoutines)
+	if err != nil {
+		return err
+	}
+
+	// Combine random_i*I_i(X) and the scaling factors random_i*h_i^n for the quotients
+	cosetSizeBI := big.NewInt(int64(cosetSize))
+	foldedInterpolationPoly := make(Polynomial, cosetSize)
+	scaledRandomNumbers := make([]fr.Element, batchSize)
+	for i := 0; i < batchSize; i++ {
+		interpolationPoly := openKey.interpolateCoset(proofs[i].CosetShift, proofs[i].CosetEvaluations)
+		for j := 0; j < cosetSize; j++ {
+			var tmp fr.Element
+			tmp.Mul(&interpolationPoly[j], &randomNumbers[i])
+			foldedInterpolationPoly[j].Add(&foldedInterpolationPoly[j], &tmp)
+		}
+
+		var shiftPow fr.Element
+		shiftPow.Exp(proofs[i].CosetShift, cosetSizeBI)
+		scaledRandomNumbers[i].Mul(&randomNumbers[i], &shiftPow)
+	}
+
+	// [Σ r_i I_i(s)]G₁
+	foldedInterpolationCommit, err := multiexp.MultiExpWithBackend(openKey.backend, foldedInterpolationPoly, openKey.G1, openKey.numGoRoutines)
+	if err != nil {
+		return err
+	}
+
+	// Combine random_i*h_i^n*quotient_i
+	foldedScaledQuotients, err := multiexp.MultiExpWithBackend(openKey.backend, scaledRandomNumbers, quotients, openKey.numGoRoutines)
+	if err != nil {
+		return err
+	}
+
+	// `lhs` first pairing
+	foldedCommitments.Sub(foldedCommitments, foldedInterpolationCommit)
+	foldedCommitments.Add(foldedCommitments, foldedScaledQuotients)
+
+	// `lhs` second pairing
+	foldedQuotients.Neg(foldedQuotients)
+
+	check, err := bls12381.PairingCheck(
+		[]bls12381.G1Affine{*foldedCommitments, *foldedQuotients},
+		[]bls12381.G2Affine{openKey.GenG2, openKey.SPowCosetSizeG2},
+	)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyOpeningProof
+	}
+
+	return nil
+}
+
+// interpolateCoset returns the coefficients of the polynomial I(X) of degree less than the coset size, which takes
+// the given values over the coset h*H. The values are in the order documented in [CosetOpeningProof].
+func (openKey *CosetOpeningKey) interpolateCoset(cosetShift fr.Element, cosetEvaluations []fr.Element) Polynomial {
+	// J(X) = I(h*X) takes the values over H, so its coefficients are the inverse FFT of the values in natural order.
+	evaluations := make([]fr.Element, len(cosetEvaluations))
+	copy(evaluations, cosetEvaluations)
+	bitReverse(evaluations)
+	coeffs := openKey.cosetDomain.IfftFr(evaluations)
+
+	// The coefficients of I(X) = J(X/h) are those of J(X), scaled by powers of 1/h
+	var cosetShiftInv fr.Element
+	cosetShiftInv.Inverse(&cosetShift)
+	shiftInvPow := fr.One()
+	for i := 0; i < len(coeffs); i++ {
+		coeffs[i].Mul(&coeffs[i], &shiftInvPow)
+		shiftInvPow.Mul(&shiftInvPow, &cosetShiftInv)
+	}
+
+	return coeffs
+}
@@ -0,0 +1,66 @@
+package kzg
+
+import (
+	"math/big"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchVerifyCosetOpenings(t *testing.T) {
+	const polySize, cosetSize = 16, 4
+	secret := big.NewInt(1234)
+	srs, err := newMonomialSRSInsecureUint64(polySize, secret)
+	require.NoError(t, err)
+
+	// G2 powers of the secret, up to the coset size
+	_, _, _, genG2 := bls12381.Generators()
+	srsG2 := make([]bls12381.G2Affine, cosetSize+1)
+	secretPow := big.NewInt(1)
+	for i := range srsG2 {
+		srsG2[i].ScalarMultiplication(&genG2, secretPow)
+		secretPow.Mul(secretPow, secret)
+	}
+
+	fk, err := NewFK20(srs.CommitKey.G1, polySize, cosetSize, 1)
+	require.NoError(t, err)
+	openKey, err := NewCosetOpeningKey(srs.CommitKey.G1, srsG2, cosetSize)
+	require.NoError(t, err)
+
+	polyCoeff := randPoly(t, *NewDomain(polySize))
+	commitment, err := Commit(polyCoeff, &srs.CommitKey, 0)
+	require.NoError(t, err)
+	quotients, err := fk.ComputeMultiProofs(polyCoeff, 0)
+	require.NoError(t, err)
+
+	extDomain := NewDomain(2 * polySize)
+	extEvaluations := extDomain.EvaluateMonomialBitReversed(polyCoeff)
+	extDomain.ReverseRoots()
+
+	commitments := make([]Commitment, len(quotients))
+	proofs := make([]CosetOpeningProof, len(quotients))
+	for k := range quotients {
+		commitments[k] = *commitment
+		proofs[k] = CosetOpeningProof{
+			QuotientCommitment: quotients[k],
+			CosetShift:         extDomain.Roots[k*cosetSize],
+			CosetEvaluations:   extEvaluations[k*cosetSize : (k+1)*cosetSize],
+		}
+	}
+	require.NoError(t, BatchVerifyCosetOpenings(commitments, proofs, openKey))
+	require.NoError(t, BatchVerifyCosetOpenings(commitments[3:4], proofs[3:4], openKey))
+	require.NoError(t, BatchVerifyCosetOpenings(nil, nil, openKey))
+
+	// Proofs must be checked against the right coset
+	proofs[1].CosetShift, proofs[2].CosetShift = proofs[2].CosetShift, proofs[1].CosetShift
+	require.ErrorIs(t, BatchVerifyCosetOpenings(commitments, proofs, openKey), ErrVerifyOpeningProof)
+	proofs[1].CosetShift, proofs[2].CosetShift = proofs[2].CosetShift, proofs[1].CosetShift
+
+	// Changing a single evaluation must be detected
+	proofs[5].CosetEvaluations = append(Polynomial(nil), proofs[5].CosetEvaluations...)
+	proofs[5].CosetEvaluations[2].SetUint64(42)
+	require.ErrorIs(t, BatchVerifyCosetOpenings(commitments, proofs, openKey), ErrVerifyOpeningProof)
+
+	require.ErrorIs(t, BatchVerifyCosetOpenings(commitments[1:], proofs, openKey), ErrInvalidNumDigests)
+}
@@ -0,0 +1,7 @@
+//go:build kzgdebug
+
+package kzg
+
+// debugAssertions enables checks of values which callers are trusted to compute correctly, such as the claimed value
+// passed to [OpenWithClaimedValue]. It is set by building with the `kzgdebug` tag.
+const debugAssertions = true
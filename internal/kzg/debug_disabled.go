@@ -0,0 +1,6 @@
+//go:build !kzgdebug
+
+package kzg
+
+// debugAssertions is false unless building with the `kzgdebug` tag. See debug.go.
+const debugAssertions = false
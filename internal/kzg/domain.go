@@ -97,6 +97,17 @@ func NewDomain(x uint64) *Domain {
 	return domain
 }
 
+// NewDomainForSRS returns a new domain with the given cardinality, to be used with an SRS of srsSize points.
+//
+// Unlike [NewDomain], the cardinality is validated instead of causing a panic: it must be a power of two which
+// divides srsSize, so that the SRS has enough points for the domain. Otherwise, [ErrInvalidDomainSize] is returned.
+func NewDomainForSRS(cardinality uint64, srsSize int) (*Domain, error) {
+	if !utils.IsPowerOfTwo(cardinality) || srsSize <= 0 || uint64(srsSize)%cardinality != 0 {
+		return nil, fmt.Errorf("%w: got %d points for an srs of size %d", ErrInvalidDomainSize, cardinality, srsSize)
+	}
+	return NewDomain(cardinality), nil
+}
+
 /*
 Taken from a chat with Dr Dankrad Feist:
 - Samples are going to be contiguous when we switch on full sharding.
@@ -144,6 +155,18 @@ func bitReverse[K interface{}](list []K) {
 	}
 }
 
+// BitReverse applies the bit-reversal permutation to the list in place, for example to a slice of [fr.Element]. Since
+// the permutation is its own inverse, it also converts a bit-reversed list back to natural order.
+//
+// Returns [ErrNotPowerOfTwo] if the length of the list is not a power of two.
+func BitReverse[K interface{}](list []K) error {
+	if !utils.IsPowerOfTwo(uint64(len(list))) {
+		return ErrNotPowerOfTwo
+	}
+	bitReverse(list)
+	return nil
+}
+
 // ReverseRoots applies the bit-reversal permutation to the list of precomputed roots of unity and their inverses in the domain.
 //
 // [bit_reversal_permutation]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#bit_reversal_permutation
@@ -152,11 +175,27 @@ func (domain *Domain) ReverseRoots() {
 	bitReverse(domain.PreComputedInverses)
 }
 
+// IsInDomain reports whether point is in the domain, meaning that point is a domain.Cardinality'th root of unity.
+//
+// Since the domain is the subgroup of order domain.Cardinality, this checks that point^domain.Cardinality == 1,
+// which takes log2(domain.Cardinality) squarings instead of a scan of the roots.
+func (domain *Domain) IsInDomain(point fr.Element) bool {
+	for i := uint64(1); i < domain.Cardinality; i <<= 1 {
+		point.Square(&point)
+	}
+	return point.IsOne()
+}
+
 // findRootIndex returns the index of the element in the domain or -1 if not found.
 //
 //   - If point is in the domain (meaning that point is a domain.Cardinality'th root of unity), returns the index of the point in the domain.
 //   - If point is not in the domain, returns -1.
+//
+// Points outside of the domain are rejected with [Domain.IsInDomain], without scanning the roots.
 func (domain *Domain) findRootIndex(point fr.Element) int64 {
+	if !domain.IsInDomain(point) {
+		return -1
+	}
 	for i := int64(0); i < int64(domain.Cardinality); i++ {
 		if point.Equal(&domain.Roots[i]) {
 			return i
@@ -193,6 +232,14 @@ func (domain *Domain) evaluateLagrangePolynomial(poly Polynomial, evalPoint fr.E
 		return nil, indexInDomain, ErrPolynomialMismatchedSizeDomain
 	}
 
+	// Over a domain with a single point, the polynomial is constant
+	if domain.Cardinality == 1 {
+		if evalPoint.Equal(&domain.Roots[0]) {
+			indexInDomain = 0
+		}
+		return &poly[0], indexInDomain, nil
+	}
+
 	// If the evaluation point is in the domain
 	// then evaluation of the polynomial in lagrange form
 	// is the same as indexing it with the position
@@ -202,11 +249,16 @@ func (domain *Domain) evaluateLagrangePolynomial(poly Polynomial, evalPoint fr.E
 		return &poly[indexInDomain], indexInDomain, nil
 	}
 
-	denom := make([]fr.Element, domain.Cardinality)
+	denomScratch := getScratch(int(domain.Cardinality))
+	defer putScratch(denomScratch)
+	denom := *denomScratch
 	for i := range denom {
 		denom[i].Sub(&evalPoint, &domain.Roots[i])
 	}
-	invDenom := fr.BatchInvert(denom)
+	invDenomScratch := getScratch(int(domain.Cardinality))
+	defer putScratch(invDenomScratch)
+	invDenom := *invDenomScratch
+	batchInvertInto(invDenom, denom)
 
 	var result fr.Element
 	for i := 0; i < int(domain.Cardinality); i++ {
@@ -67,6 +67,34 @@ func TestBitReversal(t *testing.T) {
 	}
 }
 
+func TestBitReverseIsInvolution(t *testing.T) {
+	for _, size := range []int{1, 2, 8, 4096} {
+		scalars := testScalars(size)
+		reversed := testScalars(size)
+
+		if err := BitReverse(reversed); err != nil {
+			t.Fatal(err)
+		}
+		if size >= 8 && reversed[1] != scalars[size/2] {
+			t.Error("bit reversal did not move the element at index size/2 to index 1")
+		}
+		if err := BitReverse(reversed); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < size; i++ {
+			if !reversed[i].Equal(&scalars[i]) {
+				t.Error("applying the bit reversal twice is not the identity")
+			}
+		}
+	}
+
+	for _, size := range []int{0, 3, 4095} {
+		if err := BitReverse(testScalars(size)); err != ErrNotPowerOfTwo {
+			t.Errorf("expected ErrNotPowerOfTwo for a list of size %d, got %v", size, err)
+		}
+	}
+}
+
 // This is simply another way to do the bit reversal,
 // if these were incorrect then integration tests would
 // fail.
@@ -156,6 +184,84 @@ func TestEvalPolynomialSmoke(t *testing.T) {
 	}
 }
 
+func TestEvaluateLagrangePolynomialAtEveryRoot(t *testing.T) {
+	domain := NewDomain(4096)
+	domain.ReverseRoots()
+	poly := randPoly(t, *domain)
+
+	// At a root, the barycentric formula would divide by zero, so the evaluation is read from the polynomial
+	for i, root := range domain.Roots {
+		value, err := domain.EvaluateLagrangePolynomial(poly, root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !value.Equal(&poly[i]) {
+			t.Fatalf("incorrect evaluation at root %d", i)
+		}
+
+		value, err = EvaluateWithWeights(poly, PrecomputeBarycentric(root, domain))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !value.Equal(&poly[i]) {
+			t.Fatalf("incorrect evaluation with barycentric weights at root %d", i)
+		}
+	}
+}
+
+func TestIsInDomain(t *testing.T) {
+	for _, size := range []uint64{1, 2, 16, 4096} {
+		domain := NewDomain(size)
+		domain.ReverseRoots()
+
+		for i, root := range domain.Roots {
+			if !domain.IsInDomain(root) {
+				t.Fatalf("root %d of the domain of size %d was not found in the domain", i, size)
+			}
+			if domain.findRootIndex(root) != int64(i) {
+				t.Fatalf("root %d of the domain of size %d was found at the wrong index", i, size)
+			}
+		}
+
+		// The roots of unity of twice the order are not in the domain
+		larger := NewDomain(2 * size)
+		if domain.IsInDomain(larger.Generator) {
+			t.Fatalf("generator of the domain of size %d is in the domain of size %d", 2*size, size)
+		}
+
+		var zero fr.Element
+		if domain.IsInDomain(zero) || domain.IsInDomain(*samplePointOutsideDomain(*domain)) {
+			t.Fatalf("point outside of the domain of size %d was found in the domain", size)
+		}
+	}
+}
+
+func TestIsInDomainMatchesScan(t *testing.T) {
+	inDomainByScan := func(domain *Domain, point fr.Element) bool {
+		for i := 0; i < len(domain.Roots); i++ {
+			if point.Equal(&domain.Roots[i]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	domain := NewDomain(256)
+	// Every root of unity of a larger order is either in the domain or not, depending on its order
+	points := append([]fr.Element{}, NewDomain(1024).Roots...)
+	for i := 0; i < 10; i++ {
+		points = append(points, *samplePointOutsideDomain(*domain))
+	}
+	var zero fr.Element
+	points = append(points, zero)
+
+	for i, point := range points {
+		if domain.IsInDomain(point) != inDomainByScan(domain, point) {
+			t.Fatalf("IsInDomain disagrees with a scan of the roots for point %d", i)
+		}
+	}
+}
+
 func samplePointOutsideDomain(domain Domain) *fr.Element {
 	var randElement fr.Element
 
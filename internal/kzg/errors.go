@@ -8,4 +8,13 @@ var (
 	ErrVerifyOpeningProof             = errors.New("can't verify opening proof")
 	ErrPolynomialMismatchedSizeDomain = errors.New("domain size does not equal the number of evaluations in the polynomial")
 	ErrMinSRSSize                     = errors.New("minimum srs size is 2")
+	ErrInconsistentEvaluations        = errors.New("evaluations do not belong to a polynomial of the expected degree")
+	ErrNotPowerOfTwo                  = errors.New("length is not a power of two")
+	ErrInvalidDomainSize              = errors.New("domain size must be a power of two which divides the srs size")
+	ErrInvalidOpeningProofLength      = errors.New("serialized opening proof does not have the expected length")
+	ErrNonCanonicalScalar             = errors.New("scalar is not canonical when interpreted as a big integer in big-endian")
+	ErrInvalidOpeningProof            = errors.New("the opening proof is malformed")
+	ErrInterpolationLengthMismatch    = errors.New("the number of points and values to interpolate must be the same")
+	ErrDuplicateInterpolationPoint    = errors.New("the points to interpolate must be distinct")
+	ErrPolynomialDoesNotVanish        = errors.New("the polynomial does not evaluate to zero at every point of the set")
 )
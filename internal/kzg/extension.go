@@ -0,0 +1,33 @@
+package kzg
+
+// The methods in this file convert between the lagrange form of a polynomial, with its evaluations in bit-reversed
+// order as it is stored in a blob, and its monomial form. They are the building blocks for extending a polynomial
+// to a larger domain, as is needed for data availability sampling.
+
+// LagrangeBitReversedToMonomial converts a polynomial in lagrange form, whose evaluations are in bit-reversed order, to
+// monomial form. The returned coefficients are in order, starting with the constant term.
+//
+// The length of p must be equal to the cardinality of the domain. Since only the generator of the domain is used, the
+// roots of the domain may be in either order.
+func (domain *Domain) LagrangeBitReversedToMonomial(p Polynomial) Polynomial {
+	evaluations := make(Polynomial, len(p))
+	copy(evaluations, p)
+	bitReverse(evaluations)
+
+	return domain.IfftFr(evaluations)
+}
+
+// EvaluateMonomialBitReversed evaluates a polynomial given in monomial form at all of the points in the domain and
+// returns the evaluations in bit-reversed order.
+//
+// The number of coefficients must not exceed the cardinality of the domain. Since only the generator of the domain is
+// used, the roots of the domain may be in either order.
+func (domain *Domain) EvaluateMonomialBitReversed(coeffs Polynomial) Polynomial {
+	paddedCoeffs := make(Polynomial, domain.Cardinality)
+	copy(paddedCoeffs, coeffs)
+
+	evaluations := domain.FftFr(paddedCoeffs)
+	bitReverse(evaluations)
+
+	return evaluations
+}
@@ -42,6 +42,62 @@ func (domain *Domain) IfftG1(values []bls12381.G1Affine) []bls12381.G1Affine {
 	return inverseFFT
 }
 
+// FftFr computes an FFT (Fast Fourier Transform) of the field elements.
+//
+// The elements are returned in order as opposed to being returned in
+// bit-reversed order.
+func (domain *Domain) FftFr(values []fr.Element) []fr.Element {
+	return fftFr(values, domain.Generator)
+}
+
+// IfftFr computes an IFFT(Inverse Fast Fourier Transform) of the field elements.
+//
+// The elements are returned in order as opposed to being returned in
+// bit-reversed order.
+func (domain *Domain) IfftFr(values []fr.Element) []fr.Element {
+	inverseFFT := fftFr(values, domain.GeneratorInv)
+
+	// scale by the inverse of the domain size
+	for i := 0; i < len(inverseFFT); i++ {
+		inverseFFT[i].Mul(&inverseFFT[i], &domain.CardinalityInv)
+	}
+
+	return inverseFFT
+}
+
+// fftFr computes an FFT (Fast Fourier Transform) of the field elements.
+//
+// This is the field element counterpart of [fftG1] and follows the same conventions.
+func fftFr(values []fr.Element, nthRootOfUnity fr.Element) []fr.Element {
+	n := len(values)
+	if n == 1 {
+		// Return a copy, since IfftFr scales the result in place
+		return []fr.Element{values[0]}
+	}
+
+	var generatorSquared fr.Element
+	generatorSquared.Square(&nthRootOfUnity) // generator with order n/2
+
+	even, odd := takeEvenOdd(values)
+
+	fftEven := fftFr(even, generatorSquared)
+	fftOdd := fftFr(odd, generatorSquared)
+
+	inputPoint := fr.One()
+	evaluations := make([]fr.Element, n)
+	for k := 0; k < n/2; k++ {
+		var tmp fr.Element
+		tmp.Mul(&inputPoint, &fftOdd[k])
+
+		evaluations[k].Add(&fftEven[k], &tmp)
+		evaluations[k+n/2].Sub(&fftEven[k], &tmp)
+
+		inputPoint.Mul(&inputPoint, &nthRootOfUnity)
+	}
+
+	return evaluations
+}
+
 // fftG1 computes an FFT (Fast Fourier Transform) of the G1 elements.
 //
 // This is the actual implementation of [FftG1] with the same convention.
@@ -50,7 +106,8 @@ func (domain *Domain) IfftG1(values []bls12381.G1Affine) []bls12381.G1Affine {
 func fftG1(values []bls12381.G1Affine, nthRootOfUnity fr.Element) []bls12381.G1Affine {
 	n := len(values)
 	if n == 1 {
-		return values
+		// Return a copy, since IfftG1 scales the result in place
+		return []bls12381.G1Affine{values[0]}
 	}
 
 	var generatorSquared fr.Element
@@ -26,3 +26,40 @@ func TestSRSConversion(t *testing.T) {
 		}
 	}
 }
+
+func TestFftFrRoundTrip(t *testing.T) {
+	domain := NewDomain(64)
+	poly := randPoly(t, *domain)
+
+	got := domain.IfftFr(domain.FftFr(poly))
+	for i := range poly {
+		if !got[i].Equal(&poly[i]) {
+			t.Fatalf("fft followed by ifft is not the identity")
+		}
+	}
+}
+
+func TestExtendPolynomial(t *testing.T) {
+	domain := NewDomain(32)
+	extDomain := NewDomain(64)
+
+	// The first half of the bit-reversed evaluations over the extended domain
+	// are the bit-reversed evaluations over the original domain.
+	evaluations := randPoly(t, *domain)
+	coeffs := domain.LagrangeBitReversedToMonomial(evaluations)
+	extEvaluations := extDomain.EvaluateMonomialBitReversed(coeffs)
+	for i := range evaluations {
+		if !extEvaluations[i].Equal(&evaluations[i]) {
+			t.Fatalf("extended evaluations do not contain the original evaluations")
+		}
+	}
+
+	// The second half are evaluations at the odd powers of the extended generator.
+	extDomain.ReverseRoots()
+	for i := len(evaluations); i < len(extEvaluations); i++ {
+		expected := evaluateMonomial(coeffs, extDomain.Roots[i])
+		if !extEvaluations[i].Equal(&expected) {
+			t.Fatalf("extended evaluation %d is incorrect", i)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package kzg
+
+import (
+	"runtime"
+	"sync"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/crate-crypto/go-kzg-4844/internal/multiexp"
+	"github.com/crate-crypto/go-kzg-4844/internal/utils"
+)
+
+// FK20 holds the precomputations needed to compute the opening proofs of a polynomial over every coset of an extended
+// domain at once, using the algorithm from [FK20].
+//
+// The polynomial f(X) has polySize coefficients, and the extended domain has twice as many points. It is split into
+// 2*polySize/cosetSize cosets h_k*H, where H is the subgroup of order cosetSize. The opening proof for the k'th coset
+// is a commitment to the quotient q_k(X) = (f(X) - I_k(X)) / (X^cosetSize - h_k^cosetSize), where I_k(X) is the
+// polynomial of degree less than cosetSize which interpolates f(X) over the coset.
+//
+// Writing f(X) = sum_m X^(cosetSize*m) g_m(X), with each g_m(X) having cosetSize coefficients, the quotient is
+// q_k(X) = sum_j c_k^j h_j(X), where c_k = h_k^cosetSize and h_j(X) = sum_{m>j} g_m(X) X^(cosetSize*(m-1-j)). Hence,
+// the proofs are the evaluations, at every c_k, of the polynomial whose coefficients are the commitments to h_j(X).
+// Those commitments form a Toeplitz matrix-vector product with the monomial SRS, which is computed using FFTs.
+//
+// [FK20]: https://github.com/khovratovich/Kate/blob/master/Kate_amortized.pdf
+type FK20 struct {
+	polySize  int
+	cosetSize int
+	numCosets int
+
+	// domain is the domain of order numCosets. It is used both for the convolutions
+	// and to evaluate the polynomial of commitments at every c_k.
+	domain *Domain
+
+	// srsFFT[k][r] is the k'th element of the FFT of the SRS points with index r modulo cosetSize.
+	// It is stored this way so that the points needed for each multi-exponentiation are contiguous.
+	srsFFT [][]bls12381.G1Affine
+
+	// backend computes the multi exponentiations of the proofs, if set.
+	backend multiexp.Backend
+}
+
+// SetMultiExpBackend sets the backend used by [FK20.ComputeMultiProofs]. A nil backend restores the default.
+func (fk *FK20) SetMultiExpBackend(backend multiexp.Backend) {
+	fk.backend = backend
+}
+
+// NewFK20 precomputes the data needed to compute opening proofs over the cosets of size cosetSize of the extended
+// domain, for polynomials with polySize coefficients.
+//
+// srsMonomial must hold the monomial SRS, with at least polySize points. Both polySize and cosetSize must be powers of
+// two, with cosetSize < polySize.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+func NewFK20(srsMonomial []bls12381.G1Affine, polySize, cosetSize int, numGoRoutines int) (*FK20, error) {
+	if !utils.IsPowerOfTwo(uint64(polySize)) || !utils.IsPowerOfTwo(uint64(cosetSize)) || cosetSize >= polySize {
+		return nil, ErrInvalidPolynomialSize
+	}
+	if len(srsMonomial) < polySize {
+		return nil, ErrMinSRSSize
+	}
+
+	numBlocks := polySize / cosetSize
+	numCosets := 2 * numBlocks
+	domain := NewDomain(uint64(numCosets))
+
+	// Split the SRS into cosetSize columns, where the r'th column holds the points
+	// whose index is r modulo cosetSize, and compute the FFT of each column.
+	//
+	// The last block is not needed, since h_j(X) only has numBlocks-1 blocks.
+	columnsFFT := make([][]bls12381.G1Affine, cosetSize)
+	computeColumnFFT := func(r int) {
+		column := make([]bls12381.G1Affine, numCosets)
+		for u := 0; u < numBlocks-1; u++ {
+			column[u] = srsMonomial[u*cosetSize+r]
+		}
+		columnsFFT[r] = domain.FftG1(column)
+	}
+	if numGoRoutines <= 0 {
+		numGoRoutines = runtime.NumCPU()
+	}
+	if numGoRoutines == 1 {
+		for r := 0; r < cosetSize; r++ {
+			computeColumnFFT(r)
+		}
+	} else {
+		var wg sync.WaitGroup
+		wg.Add(cosetSize)
+		limit := make(chan struct{}, numGoRoutines)
+		for r := 0; r < cosetSize; r++ {
+			go func(r int) {
+				defer wg.Done()
+				limit <- struct{}{}
+				defer func() { <-limit }()
+				computeColumnFFT(r)
+			}(r)
+		}
+		wg.Wait()
+	}
+
+	srsFFT := make([][]bls12381.G1Affine, numCosets)
+	for k := 0; k < numCosets; k++ {
+		srsFFT[k] = make([]bls12381.G1Affine, cosetSize)
+		for r := 0; r < cosetSize; r++ {
+			srsFFT[k][r] = columnsFFT[r][k]
+		}
+	}
+
+	return &FK20{
+		polySize:  polySize,
+		cosetSize: cosetSize,
+		numCosets: numCosets,
+		domain:    domain,
+		srsFFT:    srsFFT,
+	}, nil
+}
+
+// ComputeMultiProofs computes the opening proofs of the polynomial, given by its coefficients, over every coset of the
+// extended domain. The proofs are returned in bit-reversed order, which matches the order of the cosets when the
+// evaluations over the extended domain are bit-reversed and split into chunks of cosetSize.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+func (fk *FK20) ComputeMultiProofs(polyCoeff Polynomial, numGoRoutines int) ([]bls12381.G1Affine, error) {
+	if len(polyCoeff) > fk.polySize {
+		return nil, ErrInvalidPolynomialSize
+	}
+	numBlocks := fk.polySize / fk.cosetSize
+
+	// The commitment to h_j(X) is sum_r sum_u b_r[u+j] * srs[u*cosetSize+r], with b_r[v] = f[(v+1)*cosetSize+r].
+	// For each column r, this is the convolution of the SRS column with b_r in reverse, which we compute in the
+	// FFT domain.
+	columnsFFT := make([][]fr.Element, fk.cosetSize)
+	for r := 0; r < fk.cosetSize; r++ {
+		reversedColumn := make([]fr.Element, fk.numCosets)
+		for i := 0; i < numBlocks-1; i++ {
+			index := (numBlocks-1-i)*fk.cosetSize + r
+			if index < len(polyCoeff) {
+				reversedColumn[i] = polyCoeff[index]
+			}
+		}
+		columnsFFT[r] = fk.domain.FftFr(reversedColumn)
+	}
+
+	// Summing over all of the columns is a multi-exponentiation for each element of the FFT domain.
+	scalars := make([]fr.Element, fk.cosetSize)
+	hFFT := make([]bls12381.G1Affine, fk.numCosets)
+	for k := 0; k < fk.numCosets; k++ {
+		for r := 0; r < fk.cosetSize; r++ {
+			scalars[r] = columnsFFT[r][k]
+		}
+		res, err := multiexp.MultiExpWithBackend(fk.backend, scalars, fk.srsFFT[k], numGoRoutines)
+		if err != nil {
+			return nil, err
+		}
+		hFFT[k] = *res
+	}
+
+	// The commitment to h_j(X) is at index numBlocks-2-j of the convolution.
+	convolution := fk.domain.IfftG1(hFFT)
+	hCommitments := make([]bls12381.G1Affine, fk.numCosets)
+	for j := 0; j < numBlocks-1; j++ {
+		hCommitments[j] = convolution[numBlocks-2-j]
+	}
+
+	// The proof for the k'th coset is the evaluation at c_k, which is the (bit-reversed) k'th root of unity in the
+	// domain of order numCosets.
+	proofs := fk.domain.FftG1(hCommitments)
+	bitReverse(proofs)
+
+	return proofs, nil
+}
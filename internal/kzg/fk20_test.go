@@ -0,0 +1,62 @@
+package kzg
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFK20MatchesQuotientCommitments(t *testing.T) {
+	const polySize, cosetSize = 16, 4
+	srs, err := newMonomialSRSInsecureUint64(polySize, big.NewInt(1234))
+	require.NoError(t, err)
+
+	fk, err := NewFK20(srs.CommitKey.G1, polySize, cosetSize, 0)
+	require.NoError(t, err)
+
+	polyCoeff := randPoly(t, *NewDomain(polySize))
+	proofs, err := fk.ComputeMultiProofs(polyCoeff, 0)
+	require.NoError(t, err)
+	require.Len(t, proofs, 2*polySize/cosetSize)
+
+	extDomain := NewDomain(2 * polySize)
+	extDomain.ReverseRoots()
+	for k := range proofs {
+		// The k'th coset is h_k*H, where h_k is the first point of the k'th chunk of the bit-reversed domain
+		var c fr.Element
+		c.Exp(extDomain.Roots[k*cosetSize], big.NewInt(cosetSize))
+
+		quotient := divideByXPowMinusC(polyCoeff, cosetSize, c)
+		expected, err := Commit(quotient, &srs.CommitKey, 0)
+		require.NoError(t, err)
+		require.True(t, expected.Equal(&proofs[k]), "proof %d is incorrect", k)
+	}
+}
+
+func TestNewFK20InvalidSizes(t *testing.T) {
+	srs, err := newMonomialSRSInsecureUint64(16, big.NewInt(1234))
+	require.NoError(t, err)
+
+	_, err = NewFK20(srs.CommitKey.G1, 16, 16, 0)
+	require.ErrorIs(t, err, ErrInvalidPolynomialSize)
+	_, err = NewFK20(srs.CommitKey.G1, 12, 4, 0)
+	require.ErrorIs(t, err, ErrInvalidPolynomialSize)
+	_, err = NewFK20(srs.CommitKey.G1, 32, 4, 0)
+	require.ErrorIs(t, err, ErrMinSRSSize)
+}
+
+// divideByXPowMinusC returns the quotient of the division of the polynomial by X^n - c, discarding the remainder.
+func divideByXPowMinusC(polyCoeff Polynomial, n int, c fr.Element) Polynomial {
+	remainder := make(Polynomial, len(polyCoeff))
+	copy(remainder, polyCoeff)
+	quotient := make(Polynomial, len(polyCoeff)-n)
+	for i := len(polyCoeff) - 1; i >= n; i-- {
+		quotient[i-n] = remainder[i]
+		var tmp fr.Element
+		tmp.Mul(&remainder[i], &c)
+		remainder[i-n].Add(&remainder[i-n], &tmp)
+	}
+	return quotient
+}
@@ -1,18 +1,70 @@
 package kzg
 
 import (
+	"fmt"
+	"math/big"
+	"runtime"
+	"sync"
+
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 )
 
+// minParallelQuotientSize is the smallest polynomial size for which the pointwise loops of
+// [Domain.computeQuotientPolyOutsideDomain] are split across multiple go-routines. Below this size,
+// the cost of spawning go-routines outweighs the gains.
+const minParallelQuotientSize = 1024
+
 // Open verifies that a polynomial f(x) when evaluated at a point `z` is equal to `f(z)`
 //
+// As in [compute_kzg_proof_impl], `z` may be any field element, including a point of the domain. The quotient
+// q(X) = (f(X) - f(z)) / (X - z) is computed pointwise over the domain, which divides by zero at the root w_m = z: for
+// such a point, f(z) is the m'th evaluation, and q(w_m) is instead computed as minus the sum over i != m of
+// q(w_i) * w_i / w_m, which is its value by [compute_quotient_eval_within_domain]. The proof is the same as for any
+// other point, and verifies with [Verify].
+//
+// The polynomial must have exactly domain.Cardinality evaluations, otherwise the returned error wraps
+// [ErrInvalidPolynomialSize] and gives both sizes.
+//
 // numGoRoutines is used to configure the amount of concurrency needed. Setting this
 // value to a negative number or 0 will make it default to the number of CPUs.
 //
 // [compute_kzg_proof_impl]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#compute_kzg_proof_impl
+// [compute_quotient_eval_within_domain]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#compute_quotient_eval_within_domain
 func Open(domain *Domain, p Polynomial, evaluationPoint fr.Element, ck *CommitKey, numGoRoutines int) (OpeningProof, error) {
-	if len(p) == 0 || len(p) > len(ck.G1) {
-		return OpeningProof{}, ErrInvalidPolynomialSize
+	// The quotient is only committed to, so it is scratch space
+	quotientScratch := getScratch(len(p))
+	defer putScratch(quotientScratch)
+	quotient := *quotientScratch
+	return evaluateAndOpen(domain, p, evaluationPoint, quotient, ck, numGoRoutines)
+}
+
+// OpenWithQuotient is like [Open], but also returns the quotient polynomial q(X) = (f(X) - f(z)) / (X - z) that the
+// proof commits to. Like `p`, the quotient is in lagrange form over the same domain, so that it can, for example, be
+// combined with other quotients before committing to the result.
+//
+// The polynomial must have exactly domain.Cardinality evaluations, otherwise the returned error wraps
+// [ErrInvalidPolynomialSize] and gives both sizes.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+func OpenWithQuotient(domain *Domain, p Polynomial, evaluationPoint fr.Element, ck *CommitKey, numGoRoutines int) (OpeningProof, Polynomial, error) {
+	quotient := make(Polynomial, len(p))
+	proof, err := evaluateAndOpen(domain, p, evaluationPoint, quotient, ck, numGoRoutines)
+	if err != nil {
+		return OpeningProof{}, nil, err
+	}
+	return proof, quotient, nil
+}
+
+// evaluateAndOpen checks the size of `p`, evaluates it at `z` and computes the opening proof, writing the quotient
+// polynomial into `quotient`, which must have len(p) evaluations.
+func evaluateAndOpen(domain *Domain, p Polynomial, evaluationPoint fr.Element, quotient Polynomial, ck *CommitKey, numGoRoutines int) (OpeningProof, error) {
+	if err := CheckPolynomialSize(p, ck); err != nil {
+		return OpeningProof{}, err
+	}
+	// A polynomial which fits in the commit key may still be smaller or larger than the domain
+	if uint64(len(p)) != domain.Cardinality {
+		return OpeningProof{}, fmt.Errorf("%w: got %d evaluations, expected %d for the domain", ErrInvalidPolynomialSize, len(p), domain.Cardinality)
 	}
 
 	outputPoint, indexInDomain, err := domain.evaluateLagrangePolynomial(p, evaluationPoint)
@@ -20,21 +72,158 @@ func Open(domain *Domain, p Polynomial, evaluationPoint fr.Element, ck *CommitKe
 		return OpeningProof{}, err
 	}
 
+	return open(domain, p, evaluationPoint, *outputPoint, indexInDomain, quotient, ck, numGoRoutines)
+}
+
+// OpenWithClaimedValue is like [Open], except that it trusts the given f(z) instead of evaluating the polynomial at `z`.
+// This saves an evaluation when opening a polynomial at many points whose evaluations were already computed in bulk.
+//
+// The claimed value is not checked: if it is not the evaluation of the polynomial at `z`, the returned proof will
+// not verify. When built with the `kzgdebug` build tag, the claimed value is compared against the evaluation and this
+// method panics if they differ.
+//
+// The polynomial must have exactly domain.Cardinality evaluations, otherwise the returned error wraps
+// [ErrInvalidPolynomialSize] and gives both sizes.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+func OpenWithClaimedValue(domain *Domain, p Polynomial, evaluationPoint, claimedValue fr.Element, ck *CommitKey, numGoRoutines int) (OpeningProof, error) {
+	if err := CheckPolynomialSize(p, ck); err != nil {
+		return OpeningProof{}, err
+	}
+	if domain.Cardinality != uint64(len(p)) {
+		return OpeningProof{}, fmt.Errorf("%w: got %d evaluations, expected %d for the domain", ErrInvalidPolynomialSize, len(p), domain.Cardinality)
+	}
+
+	if debugAssertions {
+		outputPoint, err := domain.EvaluateLagrangePolynomial(p, evaluationPoint)
+		if err != nil {
+			return OpeningProof{}, err
+		}
+		if !outputPoint.Equal(&claimedValue) {
+			panic("claimed value passed to OpenWithClaimedValue is not the evaluation of the polynomial")
+		}
+	}
+
+	indexInDomain := domain.findRootIndex(evaluationPoint)
+	quotientScratch := getScratch(len(p))
+	defer putScratch(quotientScratch)
+	quotient := *quotientScratch
+	return open(domain, p, evaluationPoint, claimedValue, indexInDomain, quotient, ck, numGoRoutines)
+}
+
+// OpenMultiPoint computes the opening proofs of a polynomial at each of the given points, as [Open] would, so that
+// they can be verified with [BatchVerifyMultiPoints] against the same commitment.
+//
+// The denominators w - z, for every point w of the domain and every distinct point z outside of the domain, are
+// inverted with a single batch inversion, which is used both to evaluate the polynomial at z and to compute the
+// quotient. Points in the domain are opened as in [Open], and repeated points get the same proof.
+//
+// The polynomial must have exactly domain.Cardinality evaluations, otherwise the returned error wraps
+// [ErrInvalidPolynomialSize] and gives both sizes.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+func OpenMultiPoint(domain *Domain, p Polynomial, evaluationPoints []fr.Element, ck *CommitKey, numGoRoutines int) ([]OpeningProof, error) {
+	if err := CheckPolynomialSize(p, ck); err != nil {
+		return nil, err
+	}
+	if domain.Cardinality != uint64(len(p)) {
+		return nil, fmt.Errorf("%w: got %d evaluations, expected %d for the domain", ErrInvalidPolynomialSize, len(p), domain.Cardinality)
+	}
+	n := len(p)
+
+	proofs := make([]OpeningProof, len(evaluationPoints))
+	firstIndex := make(map[fr.Element]int, len(evaluationPoints))
+	var outsideDomain []int
+	quotientScratch := getScratch(n)
+	defer putScratch(quotientScratch)
+	quotient := *quotientScratch
+	for j, z := range evaluationPoints {
+		if _, ok := firstIndex[z]; ok {
+			continue
+		}
+		firstIndex[z] = j
+
+		indexInDomain := domain.findRootIndex(z)
+		if indexInDomain == -1 {
+			outsideDomain = append(outsideDomain, j)
+			continue
+		}
+		proof, err := open(domain, p, z, p[indexInDomain], indexInDomain, quotient, ck, numGoRoutines)
+		if err != nil {
+			return nil, err
+		}
+		proofs[j] = proof
+	}
+
+	// Compute 1/(w - z) for every point w of the domain and every point z outside of it
+	inverses := make([]fr.Element, len(outsideDomain)*n)
+	for k, j := range outsideDomain {
+		for i := 0; i < n; i++ {
+			inverses[k*n+i].Sub(&domain.Roots[i], &evaluationPoints[j])
+		}
+	}
+	inverses = fr.BatchInvert(inverses)
+
+	one := fr.One()
+	cardinality := new(big.Int).SetUint64(domain.Cardinality)
+	for k, j := range outsideDomain {
+		z := evaluationPoints[j]
+		quotientPoly := inverses[k*n : (k+1)*n]
+
+		// f(z) = (z^n - 1)/n * sum_i f_i * w_i / (z - w_i), as in evaluateLagrangePolynomial
+		var sum, fz fr.Element
+		for i := 0; i < n; i++ {
+			var term fr.Element
+			term.Mul(&p[i], &domain.Roots[i]).Mul(&term, &quotientPoly[i])
+			sum.Sub(&sum, &term)
+		}
+		fz.Exp(z, cardinality).Sub(&fz, &one).Mul(&fz, &domain.CardinalityInv).Mul(&fz, &sum)
+
+		// q_i = (f_i - f(z)) / (w_i - z), as in computeQuotientPolyOutsideDomain
+		for i := 0; i < n; i++ {
+			var numerator fr.Element
+			numerator.Sub(&p[i], &fz)
+			quotientPoly[i].Mul(&quotientPoly[i], &numerator)
+		}
+
+		quotientCommit, err := Commit(quotientPoly, ck, numGoRoutines)
+		if err != nil {
+			return nil, err
+		}
+		proofs[j] = OpeningProof{
+			QuotientCommitment: *quotientCommit,
+			InputPoint:         z,
+			ClaimedValue:       fz,
+		}
+	}
+
+	// Repeated points get the proof of their first occurrence
+	for j, z := range evaluationPoints {
+		proofs[j] = proofs[firstIndex[z]]
+	}
+
+	return proofs, nil
+}
+
+// open computes the opening proof of `p` at `z`, given f(z) and the index of `z` in the domain, or -1 if it is not
+// in the domain. The quotient polynomial is written into `quotient`, which must have len(p) evaluations.
+func open(domain *Domain, p Polynomial, evaluationPoint, outputPoint fr.Element, indexInDomain int64, quotient Polynomial, ck *CommitKey, numGoRoutines int) (OpeningProof, error) {
 	// Compute the quotient polynomial
-	quotientPoly, err := domain.computeQuotientPoly(p, indexInDomain, *outputPoint, evaluationPoint)
-	if err != nil {
+	if err := domain.computeQuotientPoly(p, indexInDomain, outputPoint, evaluationPoint, quotient, numGoRoutines); err != nil {
 		return OpeningProof{}, err
 	}
 
 	// Commit to Quotient polynomial
-	quotientCommit, err := Commit(quotientPoly, ck, numGoRoutines)
+	quotientCommit, err := Commit(quotient, ck, numGoRoutines)
 	if err != nil {
 		return OpeningProof{}, err
 	}
 
 	res := OpeningProof{
 		InputPoint:   evaluationPoint,
-		ClaimedValue: *outputPoint,
+		ClaimedValue: outputPoint,
 	}
 
 	res.QuotientCommitment.Set(quotientCommit)
@@ -42,7 +231,8 @@ func Open(domain *Domain, p Polynomial, evaluationPoint fr.Element, ck *CommitKe
 	return res, nil
 }
 
-// computeQuotientPoly computes q(X) = (f(X) - f(z)) / (X - z) in Lagrange form.
+// computeQuotientPoly computes q(X) = (f(X) - f(z)) / (X - z) in Lagrange form, and writes it into `quotient`, which
+// must have as many evaluations as f(X). Every evaluation of `quotient` is overwritten, so it does not need to be zero.
 //
 // We refer to the result q(X) as the quotient polynomial.
 //
@@ -56,70 +246,124 @@ func Open(domain *Domain, p Polynomial, evaluationPoint fr.Element, ck *CommitKe
 //
 // indexInDomain needs to be set to -1 to indicate that z is not in the domain and to the index in the domain if it is.
 //
+// numGoRoutines is used to configure the amount of concurrency needed when z is not in the domain. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+//
 // The matching code for this method is in `compute_kzg_proof_impl` where the quotient polynomial
 // is computed.
-func (domain *Domain) computeQuotientPoly(f Polynomial, indexInDomain int64, fz, z fr.Element) (Polynomial, error) {
+func (domain *Domain) computeQuotientPoly(f Polynomial, indexInDomain int64, fz, z fr.Element, quotient Polynomial, numGoRoutines int) error {
 	if domain.Cardinality != uint64(len(f)) {
-		return nil, ErrPolynomialMismatchedSizeDomain
+		return fmt.Errorf("%w: got %d evaluations, expected %d for the domain", ErrInvalidPolynomialSize, len(f), domain.Cardinality)
+	}
+
+	// Over a domain with a single point, f(X) is constant and so the quotient is zero
+	if domain.Cardinality == 1 {
+		quotient[0].SetZero()
+		return nil
 	}
 
 	if indexInDomain != -1 {
 		// Note: the uint64 conversion is both semantically correct and safer
 		// than accepting an `int``, since we know it shouldn't be negative
 		// and it should cause a panic, if not checked; uint64(-1) = 2^64 -1
-		return domain.computeQuotientPolyOnDomain(f, uint64(indexInDomain))
+		domain.computeQuotientPolyOnDomain(f, uint64(indexInDomain), quotient)
+		return nil
 	}
 
-	return domain.computeQuotientPolyOutsideDomain(f, fz, z)
+	domain.computeQuotientPolyOutsideDomain(f, fz, z, quotient, numGoRoutines)
+	return nil
 }
 
-// computeQuotientPolyOutsideDomain computes q(X) = (f(X) - f(z)) / (X - z) in lagrange form where `z` is not in the domain.
+// computeQuotientPolyOutsideDomain computes q(X) = (f(X) - f(z)) / (X - z) in lagrange form where `z` is not in the domain,
+// and writes it into `quotient`.
 //
 // This is the implementation of computeQuotientPoly for the case where z is not in the domain.
 // Since both input and output polynomials are given in evaluation form, this method just performs the desired operation pointwise.
-func (domain *Domain) computeQuotientPolyOutsideDomain(f Polynomial, fz, z fr.Element) (Polynomial, error) {
+//
+// The pointwise loops are split across numGoRoutines go-routines for polynomials of at least [minParallelQuotientSize]
+// evaluations. The batch inversion is always done serially, since it is a prefix-product.
+func (domain *Domain) computeQuotientPolyOutsideDomain(f Polynomial, fz, z fr.Element, quotient Polynomial, numGoRoutines int) {
 	// Compute the lagrange form the of the numerator f(X) - f(z)
 	// Since f(X) is already in lagrange form, we can compute f(X) - f(z)
 	// by shifting all elements in f(X) by f(z)
-	numerator := make(Polynomial, len(f))
-	for i := 0; i < len(f); i++ {
-		numerator[i].Sub(&f[i], &fz)
-	}
-
+	//
 	// Compute the lagrange form of the denominator X - z.
 	// This means that we need to compute w - z for all points w in the domain.
-	denominator := make(Polynomial, len(f))
-	for i := 0; i < len(f); i++ {
-		denominator[i].Sub(&domain.Roots[i], &z)
-	}
+	//
+	// Both are scratch space, since only the quotient is kept.
+	numeratorScratch := getScratch(len(f))
+	defer putScratch(numeratorScratch)
+	numerator := *numeratorScratch
+	denominatorScratch := getScratch(len(f))
+	defer putScratch(denominatorScratch)
+	denominator := *denominatorScratch
+	parallelizePointwise(len(f), numGoRoutines, func(start, end int) {
+		for i := start; i < end; i++ {
+			numerator[i].Sub(&f[i], &fz)
+			denominator[i].Sub(&domain.Roots[i], &z)
+		}
+	})
 
 	// To invert the denominator polynomial at each point of the domain, we perform a batch-inversion.
 	// Since `z` is not in the domain, we are sure that there are no zeroes in this inversion.
 	//
-	// Note: if there was a zero, the gnark-crypto library would skip
+	// Note: if there was a zero, batchInvertInto would skip
 	// it and not panic.
-	denominator = fr.BatchInvert(denominator)
+	batchInvertInto(quotient, denominator)
 
 	// Compute the quotient q(X)
-	for i := 0; i < len(f); i++ {
-		denominator[i].Mul(&denominator[i], &numerator[i])
+	parallelizePointwise(len(f), numGoRoutines, func(start, end int) {
+		for i := start; i < end; i++ {
+			quotient[i].Mul(&quotient[i], &numerator[i])
+		}
+	})
+}
+
+// parallelizePointwise splits the range [0, n) into contiguous chunks and calls `work` on each chunk.
+//
+// The chunks are processed on numGoRoutines go-routines, where a negative number or 0 defaults to the number of CPUs.
+// If n is less than [minParallelQuotientSize] or only a single go-routine is requested, `work` is called once, on the
+// current go-routine, with the whole range.
+func parallelizePointwise(n, numGoRoutines int, work func(start, end int)) {
+	if numGoRoutines <= 0 {
+		numGoRoutines = runtime.NumCPU()
+	}
+	if n < minParallelQuotientSize || numGoRoutines == 1 {
+		work(0, n)
+		return
 	}
 
-	return denominator, nil
+	chunkSize := (n + numGoRoutines - 1) / numGoRoutines
+	var wg sync.WaitGroup
+	for start := 0; start < n; start += chunkSize {
+		end := start + chunkSize
+		if end > n {
+			end = n
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			work(start, end)
+		}(start, end)
+	}
+	wg.Wait()
 }
 
-// computeQuotientPolyOnDomain computes (f(X) - f(z)) / (X - z) in Lagrange form where `z` is in the domain.
+// computeQuotientPolyOnDomain computes (f(X) - f(z)) / (X - z) in Lagrange form where `z` is in the domain, and
+// writes it into `quotientPoly`.
 //
 // This is the implementation of computeQuotientPoly for the case where the evaluation point is in the domain.
 //
 // [compute_quotient_eval_within_domain]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#compute_quotient_eval_within_domain
-func (domain *Domain) computeQuotientPolyOnDomain(f Polynomial, index uint64) (Polynomial, error) {
+func (domain *Domain) computeQuotientPolyOnDomain(f Polynomial, index uint64, quotientPoly Polynomial) {
 	fz := f[index]
 	z := domain.Roots[index]
 	invZ := domain.PreComputedInverses[index]
 
 	// Compute the evaluation of X - z at every point in the domain.
-	rootsMinusZ := make([]fr.Element, domain.Cardinality)
+	rootsMinusZScratch := getScratch(int(domain.Cardinality))
+	defer putScratch(rootsMinusZScratch)
+	rootsMinusZ := *rootsMinusZScratch
 	for i := 0; i < int(domain.Cardinality); i++ {
 		rootsMinusZ[i].Sub(&domain.Roots[i], &z)
 	}
@@ -127,14 +371,18 @@ func (domain *Domain) computeQuotientPolyOnDomain(f Polynomial, index uint64) (P
 	// Since we know that `z` is in the domain, rootsMinusZ[index] will be zero.
 	// We set this value to `1` instead to compute the batch inversion without having to special-case here.
 	// This way, the value of rootsMinusZ[index] will stay untouched.
-	// Note: The underlying gnark-crypto library will not panic if
+	// Note: batchInvertInto, like the gnark-crypto batch inversion, will not panic if
 	// one of the elements is zero, but this is not common across libraries so we just set it to one.
 	rootsMinusZ[index].SetOne()
 
 	// Evaluation of 1/(X-z) at every point of the domain, except for index.
-	invRootsMinusZ := fr.BatchInvert(rootsMinusZ)
+	invRootsMinusZScratch := getScratch(int(domain.Cardinality))
+	defer putScratch(invRootsMinusZScratch)
+	invRootsMinusZ := *invRootsMinusZScratch
+	batchInvertInto(invRootsMinusZ, rootsMinusZ)
 
-	quotientPoly := make(Polynomial, domain.Cardinality)
+	// q_m is accumulated below, while every other evaluation is set directly
+	quotientPoly[index].SetZero()
 	for j := 0; j < int(domain.Cardinality); j++ {
 		// Check if we are on the current root of unity
 		// Note: For notations below, we use `m` to denote `index`
@@ -169,6 +417,4 @@ func (domain *Domain) computeQuotientPolyOnDomain(f Polynomial, index uint64) (P
 
 		quotientPoly[index].Add(&quotientPoly[index], &q_m_j)
 	}
-
-	return quotientPoly, nil
 }
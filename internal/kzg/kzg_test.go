@@ -1,11 +1,17 @@
 package kzg
 
 import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
 	"math/big"
+	"math/rand"
+	"runtime"
 	"testing"
 
 	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/crate-crypto/go-kzg-4844/internal/utils"
 	"github.com/stretchr/testify/require"
 )
 
@@ -26,6 +32,259 @@ func TestProofVerifySmoke(t *testing.T) {
 	}
 }
 
+func TestProofVerifyOnDomain(t *testing.T) {
+	domain := NewDomain(16)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+
+	poly := randPoly(t, *domain)
+	comm, _ := Commit(poly, &srs.CommitKey, 0)
+
+	// Open at every root of unity; the claimed value must be the
+	// corresponding lagrange evaluation and the proof must verify.
+	for i := 0; i < int(domain.Cardinality); i++ {
+		proof, err := Open(domain, poly, domain.Roots[i], &srs.CommitKey, 0)
+		require.NoError(t, err)
+		require.True(t, proof.ClaimedValue.Equal(&poly[i]))
+
+		err = Verify(comm, &proof, &srs.OpeningKey)
+		require.NoError(t, err)
+	}
+}
+
+func TestOpenWithClaimedValue(t *testing.T) {
+	domain := NewDomain(16)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+
+	poly := randPoly(t, *domain)
+	comm, _ := Commit(poly, &srs.CommitKey, 0)
+
+	// The proofs match those of Open, both outside and inside of the domain
+	for _, point := range []fr.Element{*samplePointOutsideDomain(*domain), domain.Roots[3]} {
+		expected, err := Open(domain, poly, point, &srs.CommitKey, 0)
+		require.NoError(t, err)
+
+		proof, err := OpenWithClaimedValue(domain, poly, point, expected.ClaimedValue, &srs.CommitKey, 0)
+		require.NoError(t, err)
+		require.Equal(t, expected, proof)
+		require.NoError(t, Verify(comm, &proof, &srs.OpeningKey))
+	}
+
+	// A polynomial which does not match the domain is rejected like in Open
+	point := *samplePointOutsideDomain(*domain)
+	_, err := OpenWithClaimedValue(domain, poly[:8], point, fr.One(), &srs.CommitKey, 0)
+	require.ErrorIs(t, err, ErrInvalidPolynomialSize)
+	require.ErrorContains(t, err, "got 8 evaluations, expected 16")
+
+	// A wrong claimed value gives a proof that does not verify
+	wrongValue := fr.NewElement(42)
+	if debugAssertions {
+		require.Panics(t, func() {
+			_, _ = OpenWithClaimedValue(domain, poly, point, wrongValue, &srs.CommitKey, 0)
+		})
+		return
+	}
+	proof, err := OpenWithClaimedValue(domain, poly, point, wrongValue, &srs.CommitKey, 0)
+	require.NoError(t, err)
+	require.ErrorIs(t, Verify(comm, &proof, &srs.OpeningKey), ErrVerifyOpeningProof)
+}
+
+func TestOpenWithQuotient(t *testing.T) {
+	domain := NewDomain(16)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+	poly := randPoly(t, *domain)
+
+	for _, point := range []fr.Element{*samplePointOutsideDomain(*domain), domain.Roots[9]} {
+		proof, quotient, err := OpenWithQuotient(domain, poly, point, &srs.CommitKey, 0)
+		require.NoError(t, err)
+		require.Len(t, quotient, len(poly))
+
+		expected, err := Open(domain, poly, point, &srs.CommitKey, 0)
+		require.NoError(t, err)
+		require.Equal(t, expected, proof)
+
+		quotientCommit, err := Commit(quotient, &srs.CommitKey, 0)
+		require.NoError(t, err)
+		require.True(t, quotientCommit.Equal(&proof.QuotientCommitment))
+
+		// f(w) = q(w)(w - z) + f(z) at every point w of the domain other than z
+		for i := 0; i < len(poly); i++ {
+			if domain.Roots[i].Equal(&point) {
+				continue
+			}
+			var rhs fr.Element
+			rhs.Sub(&domain.Roots[i], &point).Mul(&rhs, &quotient[i]).Add(&rhs, &proof.ClaimedValue)
+			require.True(t, rhs.Equal(&poly[i]))
+		}
+	}
+}
+
+func TestOpenMultiPoint(t *testing.T) {
+	domain := NewDomain(16)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+
+	poly := randPoly(t, *domain)
+	comm, _ := Commit(poly, &srs.CommitKey, 0)
+
+	// Points outside of the domain, in the domain, and repeated
+	outside := *samplePointOutsideDomain(*domain)
+	points := []fr.Element{outside, domain.Roots[5], randomScalarNotInDomain(t, *domain), outside, domain.Roots[5]}
+	proofs, err := OpenMultiPoint(domain, poly, points, &srs.CommitKey, 0)
+	require.NoError(t, err)
+	require.Len(t, proofs, len(points))
+
+	commitments := make([]Commitment, len(points))
+	for i, point := range points {
+		expected, err := Open(domain, poly, point, &srs.CommitKey, 0)
+		require.NoError(t, err)
+		require.Equal(t, expected, proofs[i])
+		commitments[i] = *comm
+	}
+	require.NoError(t, BatchVerifyMultiPoints(commitments, proofs, &srs.OpeningKey))
+
+	proofs, err = OpenMultiPoint(domain, poly, nil, &srs.CommitKey, 0)
+	require.NoError(t, err)
+	require.Len(t, proofs, 0)
+
+	_, err = OpenMultiPoint(domain, poly[:8], points, &srs.CommitKey, 0)
+	require.ErrorIs(t, err, ErrInvalidPolynomialSize)
+	require.ErrorContains(t, err, "got 8 evaluations, expected 16")
+}
+
+func TestZeroPolynomial(t *testing.T) {
+	domain := NewDomain(16)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+	poly := make(Polynomial, domain.Cardinality)
+
+	// The commitment is the identity, for both multi exponentiation paths
+	comm, err := Commit(poly, &srs.CommitKey, 0)
+	require.NoError(t, err)
+	require.True(t, comm.IsInfinity())
+	precomputedKey := CommitKey{G1: srs.CommitKey.G1}
+	precomputedKey.Precompute(0)
+	precomputedComm, err := Commit(poly, &precomputedKey, 0)
+	require.NoError(t, err)
+	require.True(t, precomputedComm.IsInfinity())
+
+	// Opening anywhere claims zero, with a zero quotient, and verifies
+	points := []fr.Element{*samplePointOutsideDomain(*domain), domain.Roots[3]}
+	for _, point := range points {
+		proof, err := Open(domain, poly, point, &srs.CommitKey, 0)
+		require.NoError(t, err)
+		require.True(t, proof.ClaimedValue.IsZero())
+		require.True(t, proof.QuotientCommitment.IsInfinity())
+		require.NoError(t, Verify(comm, &proof, &srs.OpeningKey))
+	}
+	proofs, err := OpenMultiPoint(domain, poly, points, &srs.CommitKey, 0)
+	require.NoError(t, err)
+	require.NoError(t, BatchVerifyMultiPoints([]Commitment{*comm, *comm}, proofs, &srs.OpeningKey))
+
+	// A non-zero claimed value must be rejected
+	if !debugAssertions {
+		proof, err := OpenWithClaimedValue(domain, poly, points[0], fr.One(), &srs.CommitKey, 0)
+		require.NoError(t, err)
+		require.ErrorIs(t, Verify(comm, &proof, &srs.OpeningKey), ErrVerifyOpeningProof)
+	}
+
+	quotient, remainder := DividePolyByXminusAMonomial(poly, points[0])
+	require.True(t, remainder.IsZero())
+	for i := range quotient {
+		require.True(t, quotient[i].IsZero())
+	}
+}
+
+func TestOpeningProofSerialization(t *testing.T) {
+	domain := NewDomain(16)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+	poly := randPoly(t, *domain)
+	comm, _ := Commit(poly, &srs.CommitKey, 0)
+
+	proof, err := Open(domain, poly, *samplePointOutsideDomain(*domain), &srs.CommitKey, 0)
+	require.NoError(t, err)
+	serProof := proof.Bytes()
+	require.Len(t, serProof, OpeningProofSize)
+
+	parsed, err := ParseOpeningProof(serProof)
+	require.NoError(t, err)
+	require.Equal(t, proof, parsed)
+	require.NoError(t, Verify(comm, &parsed, &srs.OpeningKey))
+
+	_, err = ParseOpeningProof(serProof[:OpeningProofSize-1])
+	require.ErrorIs(t, err, ErrInvalidOpeningProofLength)
+	_, err = ParseOpeningProof(append(serProof, 0))
+	require.ErrorIs(t, err, ErrInvalidOpeningProofLength)
+
+	// The claimed value is set to the modulus, which is not canonical
+	nonCanonical := append([]byte(nil), serProof...)
+	modulus := fr.Modulus().FillBytes(make([]byte, fr.Bytes))
+	copy(nonCanonical[OpeningProofSize-fr.Bytes:], modulus)
+	_, err = ParseOpeningProof(nonCanonical)
+	require.ErrorIs(t, err, ErrNonCanonicalScalar)
+
+	// The quotient commitment has the infinity flag set, but is not all zeroes
+	invalidPoint := append([]byte(nil), serProof...)
+	invalidPoint[0] = invalidPoint[0]&0x1f | 0xc0
+	_, err = ParseOpeningProof(invalidPoint)
+	require.Error(t, err)
+}
+
+func TestDomainOfSizeOne(t *testing.T) {
+	domain := NewDomain(1)
+	// The Lagrange basis of a domain with a single point is the constant polynomial 1,
+	// so the commit key is the generator, which is the first point of the monomial SRS.
+	srs, err := newMonomialSRSInsecureUint64(2, big.NewInt(1234))
+	require.NoError(t, err)
+	srs.CommitKey.G1 = srs.CommitKey.G1[:1]
+
+	poly := Polynomial{fr.NewElement(5)}
+	commitment, err := Commit(poly, &srs.CommitKey, 0)
+	require.NoError(t, err)
+
+	inputPoint := randomScalarNotInDomain(t, *domain)
+	for _, z := range []fr.Element{domain.Roots[0], inputPoint} {
+		value, err := domain.EvaluateLagrangePolynomial(poly, z)
+		require.NoError(t, err)
+		require.True(t, value.Equal(&poly[0]))
+
+		proof, quotient, err := OpenWithQuotient(domain, poly, z, &srs.CommitKey, 0)
+		require.NoError(t, err)
+		require.True(t, proof.ClaimedValue.Equal(&poly[0]))
+		require.Len(t, quotient, 1)
+		require.True(t, quotient[0].IsZero())
+		require.NoError(t, Verify(commitment, &proof, &srs.OpeningKey))
+	}
+}
+
+func TestCommitOpenInvalidPolynomialSize(t *testing.T) {
+	domain := NewDomain(4)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+
+	for _, poly := range []Polynomial{{}, make(Polynomial, 5)} {
+		_, err := Commit(poly, &srs.CommitKey, 0)
+		require.ErrorIs(t, err, ErrInvalidPolynomialSize)
+
+		_, err = Open(domain, poly, *samplePointOutsideDomain(*domain), &srs.CommitKey, 0)
+		require.ErrorIs(t, err, ErrInvalidPolynomialSize)
+	}
+}
+
+func TestOpenPolynomialDomainSizeMismatch(t *testing.T) {
+	domain := NewDomain(4)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+
+	// Small enough for the commit key, but not the size of the domain
+	poly := Polynomial{fr.NewElement(2), fr.NewElement(3)}
+	_, err := Open(domain, poly, *samplePointOutsideDomain(*domain), &srs.CommitKey, 0)
+	require.ErrorIs(t, err, ErrInvalidPolynomialSize)
+	require.ErrorContains(t, err, "got 2 evaluations, expected 4")
+
+	// Larger than the domain, but still small enough for a larger commit key
+	largeSRS, err := newMonomialSRSInsecureUint64(8, big.NewInt(1234))
+	require.NoError(t, err)
+	_, err = Open(domain, make(Polynomial, 6), *samplePointOutsideDomain(*domain), &largeSRS.CommitKey, 0)
+	require.ErrorIs(t, err, ErrInvalidPolynomialSize)
+	require.ErrorContains(t, err, "got 6 evaluations, expected 4")
+}
+
 func TestBatchVerifySmoke(t *testing.T) {
 	domain := NewDomain(4)
 	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
@@ -51,6 +310,335 @@ func TestBatchVerifySmoke(t *testing.T) {
 	require.Error(t, err, "An invalid proof was added to the list, however verification returned true")
 }
 
+func TestVerifyTwoOpenings(t *testing.T) {
+	domain := NewDomain(4)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+
+	poly := randPoly(t, *domain)
+	commitment, err := Commit(poly, &srs.CommitKey, 0)
+	require.NoError(t, err)
+	proof1, err := Open(domain, poly, *samplePointOutsideDomain(*domain), &srs.CommitKey, 0)
+	require.NoError(t, err)
+	proof2, err := Open(domain, poly, *samplePointOutsideDomain(*domain), &srs.CommitKey, 0)
+	require.NoError(t, err)
+
+	require.True(t, ProofsAgree(proof1, proof1))
+	require.False(t, ProofsAgree(proof1, proof2))
+	require.NoError(t, VerifyTwoOpenings(commitment, &proof1, &proof2, &srs.OpeningKey))
+	require.NoError(t, VerifyTwoOpenings(commitment, &proof1, &proof1, &srs.OpeningKey))
+
+	// A wrong claimed value in either proof is caught
+	wrongProof := proof2
+	wrongProof.ClaimedValue.SetOne()
+	require.ErrorIs(t, VerifyTwoOpenings(commitment, &proof1, &wrongProof, &srs.OpeningKey), ErrVerifyOpeningProof)
+	require.ErrorIs(t, VerifyTwoOpenings(commitment, &wrongProof, &proof1, &srs.OpeningKey), ErrVerifyOpeningProof)
+
+	// Proofs at the same point which disagree cannot both be valid
+	wrongProof = proof1
+	wrongProof.QuotientCommitment = proof2.QuotientCommitment
+	require.False(t, ProofsAgree(proof1, wrongProof))
+	require.ErrorIs(t, VerifyTwoOpenings(commitment, &proof1, &wrongProof, &srs.OpeningKey), ErrVerifyOpeningProof)
+}
+
+func TestBatchVerifyWithRand(t *testing.T) {
+	domain := NewDomain(4)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+
+	numProofs := 5
+	commitments := make([]Commitment, 0, numProofs)
+	proofs := make([]OpeningProof, 0, numProofs)
+	for i := 0; i < numProofs; i++ {
+		proof, commitment := randValidOpeningProof(t, *domain, *srs)
+		commitments = append(commitments, commitment)
+		proofs = append(proofs, proof)
+	}
+
+	// A deterministic source of randomness should still verify valid proofs.
+	//
+	// We do not require crypto/rand in tests
+	randReader := rand.New(rand.NewSource(1234))
+	err := BatchVerifyMultiPointsWithRand(commitments, proofs, &srs.OpeningKey, randReader)
+	require.NoError(t, err)
+
+	// An error from the source of randomness should be surfaced
+	err = BatchVerifyMultiPointsWithRand(commitments, proofs, &srs.OpeningKey, bytes.NewReader(nil))
+	require.Error(t, err)
+}
+
+func TestBatchVerifyInvalidQuotient(t *testing.T) {
+	domain := NewDomain(4)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+
+	numProofs := 5
+	commitments := make([]Commitment, 0, numProofs)
+	proofs := make([]OpeningProof, 0, numProofs)
+	for i := 0; i < numProofs; i++ {
+		proof, commitment := randValidOpeningProof(t, *domain, *srs)
+		commitments = append(commitments, commitment)
+		proofs = append(proofs, proof)
+	}
+
+	// Replace one of the quotient commitments with a point which is not on the curve.
+	// The quotient commitments are folded with a multi-exponentiation, so a
+	// failure there must not be reported as a valid batch.
+	proofs[2].QuotientCommitment.X.SetOne()
+	proofs[2].QuotientCommitment.Y.SetOne()
+	require.False(t, proofs[2].QuotientCommitment.IsOnCurve())
+
+	err := BatchVerifyMultiPoints(commitments, proofs, &srs.OpeningKey)
+	require.Error(t, err, "an invalid quotient commitment was added to the batch, however verification returned true")
+}
+
+func TestFindFailingProofs(t *testing.T) {
+	domain := NewDomain(4)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+
+	numProofs := 11
+	commitments := make([]Commitment, 0, numProofs)
+	proofs := make([]OpeningProof, 0, numProofs)
+	for i := 0; i < numProofs; i++ {
+		proof, commitment := randValidOpeningProof(t, *domain, *srs)
+		commitments = append(commitments, commitment)
+		proofs = append(proofs, proof)
+	}
+
+	failing, err := FindFailingProofs(commitments, proofs, &srs.OpeningKey)
+	require.NoError(t, err)
+	require.Nil(t, failing)
+
+	// Break the claimed values of a few of the proofs, including the first and the last
+	for _, i := range []int{0, 5, 6, 10} {
+		var one fr.Element
+		one.SetOne()
+		proofs[i].ClaimedValue.Add(&proofs[i].ClaimedValue, &one)
+	}
+	failing, err = FindFailingProofs(commitments, proofs, &srs.OpeningKey)
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 5, 6, 10}, failing)
+
+	_, err = FindFailingProofs(commitments, proofs[1:], &srs.OpeningKey)
+	require.ErrorIs(t, err, ErrInvalidNumDigests)
+}
+
+func TestBatchVerifyInSubBatches(t *testing.T) {
+	domain := NewDomain(4)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+
+	// Enough proofs for several sub-batches
+	numProofs := 3*minSubBatchSize + 5
+	commitments := make([]Commitment, 0, numProofs)
+	proofs := make([]OpeningProof, 0, numProofs)
+	for i := 0; i < numProofs; i++ {
+		proof, commitment := randValidOpeningProof(t, *domain, *srs)
+		commitments = append(commitments, commitment)
+		proofs = append(proofs, proof)
+	}
+
+	for _, numGoRoutines := range []int{0, 1, 2, 3, 16} {
+		for _, batchSize := range []int{0, 1, 2, minSubBatchSize + 1, numProofs} {
+			err := BatchVerifyMultiPointsInSubBatches(commitments[:batchSize], proofs[:batchSize], &srs.OpeningKey, numGoRoutines)
+			require.NoError(t, err)
+		}
+	}
+
+	// An invalid proof in any of the sub-batches must fail the whole batch
+	for _, i := range []int{0, minSubBatchSize + 3, numProofs - 1} {
+		claimedValue := proofs[i].ClaimedValue
+		proofs[i].ClaimedValue.Double(&claimedValue)
+		err := BatchVerifyMultiPointsInSubBatches(commitments, proofs, &srs.OpeningKey, 3)
+		require.ErrorIs(t, err, ErrVerifyOpeningProof)
+		require.ErrorIs(t, BatchVerifyMultiPoints(commitments, proofs, &srs.OpeningKey), ErrVerifyOpeningProof)
+		proofs[i].ClaimedValue = claimedValue
+	}
+
+	require.ErrorIs(t, BatchVerifyMultiPointsInSubBatches(commitments[1:], proofs, &srs.OpeningKey, 0), ErrInvalidNumDigests)
+}
+
+func TestBatchVerifyFiatShamir(t *testing.T) {
+	domain := NewDomain(4)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+
+	numProofs := 5
+	commitments := make([]Commitment, 0, numProofs)
+	proofs := make([]OpeningProof, 0, numProofs)
+	for i := 0; i < numProofs; i++ {
+		proof, commitment := randValidOpeningProof(t, *domain, *srs)
+		commitments = append(commitments, commitment)
+		proofs = append(proofs, proof)
+	}
+
+	for _, batchSize := range []int{0, 1, 2, numProofs} {
+		require.NoError(t, BatchVerifyMultiPointsFiatShamir(commitments[:batchSize], proofs[:batchSize], &srs.OpeningKey))
+	}
+	require.ErrorIs(t, BatchVerifyMultiPointsFiatShamir(commitments[1:], proofs, &srs.OpeningKey), ErrInvalidNumDigests)
+
+	// The challenge only depends on the batch, and on every part of it
+	challenge := computeBatchChallenge(commitments, proofs)
+	require.Equal(t, challenge, computeBatchChallenge(commitments, proofs))
+	require.NotEqual(t, challenge, computeBatchChallenge(commitments[:numProofs-1], proofs[:numProofs-1]))
+
+	claimedValue := proofs[3].ClaimedValue
+	proofs[3].ClaimedValue.Double(&claimedValue)
+	require.NotEqual(t, challenge, computeBatchChallenge(commitments, proofs))
+	require.ErrorIs(t, BatchVerifyMultiPointsFiatShamir(commitments, proofs, &srs.OpeningKey), ErrVerifyOpeningProof)
+	proofs[3].ClaimedValue = claimedValue
+
+	commitments[0], commitments[1] = commitments[1], commitments[0]
+	require.NotEqual(t, challenge, computeBatchChallenge(commitments, proofs))
+	require.ErrorIs(t, BatchVerifyMultiPointsFiatShamir(commitments, proofs, &srs.OpeningKey), ErrVerifyOpeningProof)
+}
+
+func TestBatchChallengeMatchesSpec(t *testing.T) {
+	// The expected challenge is hash_to_bls_field of the transcript in verify_kzg_proof_batch, computed independently
+	// for the generator and the point at infinity
+	_, _, genG1, _ := bls12381.Generators()
+	var infinity bls12381.G1Affine
+	commitments := []Commitment{genG1, infinity}
+	proofs := []OpeningProof{
+		{QuotientCommitment: infinity, InputPoint: fr.NewElement(1), ClaimedValue: fr.NewElement(3)},
+		{QuotientCommitment: genG1, InputPoint: fr.NewElement(2), ClaimedValue: fr.NewElement(4)},
+	}
+
+	challenge := computeBatchChallenge(commitments, proofs)
+	serChallenge := challenge.Bytes()
+	require.Equal(t, "56ff2a674ee0eed5562156a7cc093055f460d71835fbd945edfde2041c875ed3", hex.EncodeToString(serChallenge[:]))
+}
+
+func TestVerifyRebasedSRS(t *testing.T) {
+	domain := NewDomain(16)
+	srs, err := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+	require.NoError(t, err)
+
+	// Scale every point of the setup, so that the generators are no longer the standard ones
+	rebasedSRS := *srs
+	rebasedSRS.CommitKey.G1 = make([]bls12381.G1Affine, len(srs.CommitKey.G1))
+	g1Factor, g2Factor := big.NewInt(7), big.NewInt(11)
+	for i := range srs.CommitKey.G1 {
+		rebasedSRS.CommitKey.G1[i].ScalarMultiplication(&srs.CommitKey.G1[i], g1Factor)
+	}
+	rebasedSRS.OpeningKey.GenG1.ScalarMultiplication(&srs.OpeningKey.GenG1, g1Factor)
+	rebasedSRS.OpeningKey.GenG2.ScalarMultiplication(&srs.OpeningKey.GenG2, g2Factor)
+	rebasedSRS.OpeningKey.AlphaG2.ScalarMultiplication(&srs.OpeningKey.AlphaG2, g2Factor)
+
+	numProofs := 4
+	commitments := make([]Commitment, 0, numProofs)
+	proofs := make([]OpeningProof, 0, numProofs)
+	for i := 0; i < numProofs; i++ {
+		proof, commitment := randValidOpeningProof(t, *domain, rebasedSRS)
+		require.NoError(t, Verify(&commitment, &proof, &rebasedSRS.OpeningKey))
+		commitments = append(commitments, commitment)
+		proofs = append(proofs, proof)
+	}
+	require.NoError(t, BatchVerifyMultiPoints(commitments, proofs, &rebasedSRS.OpeningKey))
+	require.NoError(t, BatchVerifyMultiPointsFiatShamir(commitments, proofs, &rebasedSRS.OpeningKey))
+	require.NoError(t, BatchVerifyMultiPointsInSubBatches(commitments, proofs, &rebasedSRS.OpeningKey, 0))
+
+	// The claimed values are only correct for the generator of the rebased setup
+	require.ErrorIs(t, Verify(&commitments[0], &proofs[0], &srs.OpeningKey), ErrVerifyOpeningProof)
+	require.ErrorIs(t, BatchVerifyMultiPoints(commitments, proofs, &srs.OpeningKey), ErrVerifyOpeningProof)
+
+	proofs[2].ClaimedValue.SetUint64(42)
+	require.ErrorIs(t, Verify(&commitments[2], &proofs[2], &rebasedSRS.OpeningKey), ErrVerifyOpeningProof)
+	require.ErrorIs(t, BatchVerifyMultiPoints(commitments, proofs, &rebasedSRS.OpeningKey), ErrVerifyOpeningProof)
+}
+
+func TestBatchVerifyDoesNotModifyInputs(t *testing.T) {
+	domain := NewDomain(4)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+
+	numProofs := 5
+	commitments := make([]Commitment, 0, numProofs)
+	proofs := make([]OpeningProof, 0, numProofs)
+	for i := 0; i < numProofs; i++ {
+		proof, commitment := randValidOpeningProof(t, *domain, *srs)
+		commitments = append(commitments, commitment)
+		proofs = append(proofs, proof)
+	}
+	expectedCommitments := append([]Commitment(nil), commitments...)
+	expectedProofs := append([]OpeningProof(nil), proofs...)
+
+	require.NoError(t, BatchVerifyMultiPoints(commitments, proofs, &srs.OpeningKey))
+	require.NoError(t, BatchVerifyMultiPointsInSubBatches(commitments, proofs, &srs.OpeningKey, 2))
+	require.Equal(t, expectedCommitments, commitments)
+	require.Equal(t, expectedProofs, proofs)
+
+	// The debug info shares its factors with the folding of the quotients, so they must still be the powers of the
+	// sampled random number after the batch has been checked
+	randomNumber, err := sampleScalar(rand.New(rand.NewSource(1234)))
+	require.NoError(t, err)
+	expectedFactors := utils.ComputePowers(randomNumber, uint(numProofs))
+	debugInfo, err := BatchVerifyMultiPointsDebug(commitments, proofs, &srs.OpeningKey, rand.New(rand.NewSource(1234)))
+	require.NoError(t, err)
+	require.Equal(t, expectedFactors, debugInfo.Factors)
+	require.Equal(t, expectedCommitments, commitments)
+	require.Equal(t, expectedProofs, proofs)
+}
+
+func TestBatchVerifyDebug(t *testing.T) {
+	domain := NewDomain(4)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+
+	numProofs := 5
+	commitments := make([]Commitment, 0, numProofs)
+	proofs := make([]OpeningProof, 0, numProofs)
+	evaluations := make([]fr.Element, 0, numProofs)
+	for i := 0; i < numProofs; i++ {
+		proof, commitment := randValidOpeningProof(t, *domain, *srs)
+		commitments = append(commitments, commitment)
+		proofs = append(proofs, proof)
+		evaluations = append(evaluations, proof.ClaimedValue)
+	}
+
+	debugInfo, err := BatchVerifyMultiPointsDebug(commitments, proofs, &srs.OpeningKey, rand.New(rand.NewSource(1234)))
+	require.NoError(t, err)
+	require.Equal(t, numProofs, len(debugInfo.Factors))
+
+	// The factors should be the powers of a single random number
+	expectedFactors := utils.ComputePowers(debugInfo.Factors[1], uint(numProofs))
+	for i := 0; i < numProofs; i++ {
+		require.True(t, expectedFactors[i].Equal(&debugInfo.Factors[i]))
+	}
+
+	// Folding with the returned factors should reproduce the verifier's folding
+	foldedCommitment, foldedEvaluation, err := FoldCommitments(commitments, evaluations, debugInfo.Factors, 0)
+	require.NoError(t, err)
+	require.True(t, foldedCommitment.Equal(&debugInfo.FoldedCommitment))
+	require.True(t, foldedEvaluation.Equal(&debugInfo.FoldedEvaluation))
+
+	// The same source of randomness should produce the same factors
+	debugInfoAgain, err := BatchVerifyMultiPointsDebug(commitments, proofs, &srs.OpeningKey, rand.New(rand.NewSource(1234)))
+	require.NoError(t, err)
+	require.Equal(t, debugInfo, debugInfoAgain)
+
+	// Debug information is still returned for a failing batch
+	proofs[0].ClaimedValue.SetOne()
+	debugInfo, err = BatchVerifyMultiPointsDebug(commitments, proofs, &srs.OpeningKey, rand.New(rand.NewSource(1234)))
+	require.ErrorIs(t, err, ErrVerifyOpeningProof)
+	require.Equal(t, numProofs, len(debugInfo.Factors))
+
+	_, _, err = FoldCommitments(commitments, evaluations[1:], debugInfo.Factors, 0)
+	require.ErrorIs(t, err, ErrInvalidNumDigests)
+}
+
+func TestSampleScalarDeterministic(t *testing.T) {
+	scalarA, err := sampleScalar(rand.New(rand.NewSource(42)))
+	require.NoError(t, err)
+	scalarB, err := sampleScalar(rand.New(rand.NewSource(42)))
+	require.NoError(t, err)
+	require.True(t, scalarA.Equal(&scalarB))
+
+	// A candidate which is larger than the modulus should be rejected
+	// and the next candidate should be used instead.
+	var stream bytes.Buffer
+	stream.Write(bytes.Repeat([]byte{0xff}, fr.Bytes))
+	expected := fr.NewElement(5)
+	expectedBytes := expected.Bytes()
+	stream.Write(expectedBytes[:])
+	got, err := sampleScalar(&stream)
+	require.NoError(t, err)
+	require.True(t, got.Equal(&expected))
+}
+
 func TestComputeQuotientPolySmoke(t *testing.T) {
 	numEvaluations := 128
 	domain := NewDomain(uint64(numEvaluations))
@@ -66,12 +654,11 @@ func TestComputeQuotientPolySmoke(t *testing.T) {
 		return true
 	}
 
-	// Compute quotient for all values on the domain
+	// Compute quotient for all values on the domain. The same buffer is used for every quotient, and starts out
+	// holding another polynomial, since every evaluation is overwritten.
+	computedQuotientLagrange := randPoly(t, *domain)
 	for i := 0; i < int(domain.Cardinality); i++ {
-		computedQuotientLagrange, err := domain.computeQuotientPolyOnDomain(polyLagrange, uint64(i))
-		if err != nil {
-			t.Error(err)
-		}
+		domain.computeQuotientPolyOnDomain(polyLagrange, uint64(i), computedQuotientLagrange)
 		expectedQuotientLagrange := computeQuotientPolySlow(*domain, polyLagrange, domain.Roots[i])
 		for i := 0; i < int(domain.Cardinality); i++ {
 			if !polyEqual(computedQuotientLagrange, expectedQuotientLagrange) {
@@ -86,10 +673,8 @@ func TestComputeQuotientPolySmoke(t *testing.T) {
 	for i := 0; i < numRandomEvaluations; i++ {
 		inputPoint := randomScalarNotInDomain(t, *domain)
 		claimedValue, _ := domain.EvaluateLagrangePolynomial(polyLagrange, inputPoint)
-		gotQuotientPoly, err := domain.computeQuotientPolyOutsideDomain(polyLagrange, *claimedValue, inputPoint)
-		if err != nil {
-			t.Error(err)
-		}
+		gotQuotientPoly := make(Polynomial, domain.Cardinality)
+		domain.computeQuotientPolyOutsideDomain(polyLagrange, *claimedValue, inputPoint, gotQuotientPoly, 0)
 		expectedQuotientPoly := computeQuotientPolySlow(*domain, polyLagrange, inputPoint)
 		if !polyEqual(gotQuotientPoly, expectedQuotientPoly) {
 			t.Errorf("computed lagrange polynomial differs from the expected polynomial")
@@ -97,6 +682,253 @@ func TestComputeQuotientPolySmoke(t *testing.T) {
 	}
 }
 
+func TestComputeQuotientPolyParallel(t *testing.T) {
+	domain := NewDomain(4096)
+	poly := randPoly(t, *domain)
+	inputPoint := randomScalarNotInDomain(t, *domain)
+	claimedValue, err := domain.EvaluateLagrangePolynomial(poly, inputPoint)
+	require.NoError(t, err)
+
+	expected := make(Polynomial, domain.Cardinality)
+	domain.computeQuotientPolyOutsideDomain(poly, *claimedValue, inputPoint, expected, 1)
+
+	for _, numGoRoutines := range []int{0, 2, 3, 7, 16} {
+		got := make(Polynomial, domain.Cardinality)
+		domain.computeQuotientPolyOutsideDomain(poly, *claimedValue, inputPoint, got, numGoRoutines)
+		require.Equal(t, expected, got)
+	}
+}
+
+func TestBatchInvertInto(t *testing.T) {
+	values := make([]fr.Element, 17)
+	for i := 0; i < len(values); i++ {
+		values[i].SetUint64(uint64(i * 3))
+	}
+	values[9].SetZero()
+
+	// Scratch polynomials are zeroed before being reused
+	resScratch := getScratch(len(values))
+	for i := 0; i < len(*resScratch); i++ {
+		(*resScratch)[i].SetOne()
+	}
+	putScratch(resScratch)
+	resScratch = getScratch(len(values))
+	defer putScratch(resScratch)
+	res := *resScratch
+	for i := 0; i < len(res); i++ {
+		require.True(t, res[i].IsZero())
+	}
+
+	batchInvertInto(res, values)
+	require.Equal(t, fr.BatchInvert(values), []fr.Element(res))
+}
+
+func TestDedupCommitments(t *testing.T) {
+	domain := NewDomain(4)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+
+	// Open each of two polynomials at several points, so that the batch repeats their commitments
+	var commitments []Commitment
+	var proofs []OpeningProof
+	for i := 0; i < 2; i++ {
+		poly := randPoly(t, *domain)
+		commitment, err := Commit(poly, &srs.CommitKey, 0)
+		require.NoError(t, err)
+		for j := 0; j < 3; j++ {
+			proof, err := Open(domain, poly, *samplePointOutsideDomain(*domain), &srs.CommitKey, 0)
+			require.NoError(t, err)
+			commitments = append(commitments, *commitment)
+			proofs = append(proofs, proof)
+		}
+	}
+	factors := make([]fr.Element, len(commitments))
+	for i := range factors {
+		factors[i].SetUint64(uint64(i + 1))
+	}
+
+	uniqueCommitments, summedFactors := dedupCommitments(commitments, factors)
+	require.Equal(t, []Commitment{commitments[0], commitments[3]}, uniqueCommitments)
+	var expected fr.Element
+	expected.SetUint64(1 + 2 + 3)
+	require.True(t, summedFactors[0].Equal(&expected))
+	expected.SetUint64(4 + 5 + 6)
+	require.True(t, summedFactors[1].Equal(&expected))
+
+	folded, _, err := fold(nil, 0, false, commitments, factors, factors)
+	require.NoError(t, err)
+	dedupFolded, _, err := fold(nil, 0, true, commitments, factors, factors)
+	require.NoError(t, err)
+	require.True(t, folded.Equal(&dedupFolded))
+
+	openKey := srs.OpeningKey
+	openKey.SetDedupCommitments(true)
+	require.NoError(t, BatchVerifyMultiPoints(commitments, proofs, &openKey))
+	require.NoError(t, BatchVerifyMultiPointsFiatShamir(commitments, proofs, &openKey))
+
+	// A proof which is wrong is still caught when its commitment is merged with others
+	var one fr.Element
+	one.SetOne()
+	proofs[4].ClaimedValue.Add(&proofs[4].ClaimedValue, &one)
+	require.ErrorIs(t, BatchVerifyMultiPoints(commitments, proofs, &openKey), ErrVerifyOpeningProof)
+}
+
+func BenchmarkFoldDuplicateCommitments(b *testing.B) {
+	const batchSize = 128
+	_, _, genG1, _ := bls12381.Generators()
+	factors := make([]fr.Element, batchSize)
+	for i := range factors {
+		if _, err := factors[i].SetRandom(); err != nil {
+			b.Fatal(err)
+		}
+	}
+	points := bls12381.BatchScalarMultiplicationG1(&genG1, factors)
+
+	for _, numDistinct := range []int{batchSize, batchSize / 4, batchSize / 16, 1} {
+		commitments := make([]Commitment, batchSize)
+		for i := range commitments {
+			commitments[i] = points[i%numDistinct]
+		}
+		for _, dedup := range []bool{false, true} {
+			b.Run(fmt.Sprintf("distinct=%d/dedup=%v", numDistinct, dedup), func(b *testing.B) {
+				for n := 0; n < b.N; n++ {
+					_, _, _ = fold(nil, 0, dedup, commitments, factors, factors)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkComputeQuotientPolyOutsideDomain(b *testing.B) {
+	domain := NewDomain(4096)
+	poly := make(Polynomial, domain.Cardinality)
+	for i := 0; i < len(poly); i++ {
+		poly[i].SetUint64(uint64(i))
+	}
+	var inputPoint fr.Element
+	inputPoint.SetUint64(123456789)
+	claimedValue, err := domain.EvaluateLagrangePolynomial(poly, inputPoint)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	quotient := make(Polynomial, domain.Cardinality)
+	for _, numGoRoutines := range []int{1, runtime.GOMAXPROCS(0)} {
+		b.Run(fmt.Sprintf("numGoRoutines=%d", numGoRoutines), func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				domain.computeQuotientPolyOutsideDomain(poly, *claimedValue, inputPoint, quotient, numGoRoutines)
+			}
+		})
+	}
+}
+
+func BenchmarkOpen(b *testing.B) {
+	domain := NewDomain(4096)
+	srs, err := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+	if err != nil {
+		b.Fatal(err)
+	}
+	poly := make(Polynomial, domain.Cardinality)
+	for i := 0; i < len(poly); i++ {
+		poly[i].SetUint64(uint64(i))
+	}
+	var outsideDomain fr.Element
+	outsideDomain.SetUint64(123456789)
+
+	points := map[string]fr.Element{"outside domain": outsideDomain, "in domain": domain.Roots[7]}
+	for _, name := range []string{"outside domain", "in domain"} {
+		point := points[name]
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				_, _ = Open(domain, poly, point, &srs.CommitKey, 1)
+			}
+		})
+	}
+}
+
+// BenchmarkOpenAllocs measures the memory used by Open itself, by committing with a backend which does no work, since
+// the allocations of a real multi exponentiation would otherwise dominate. The quotient and its temporary
+// polynomials come from [scratchPool], so they are best measured over many sequential calls:
+//
+//	go test -run - -bench BenchmarkOpenAllocs -benchtime 10000x ./internal/kzg
+func BenchmarkOpenAllocs(b *testing.B) {
+	domain := NewDomain(4096)
+	srs, err := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+	if err != nil {
+		b.Fatal(err)
+	}
+	ck := CommitKey{G1: srs.CommitKey.G1}
+	ck.SetMultiExpBackend(nopBackend{})
+	poly := make(Polynomial, domain.Cardinality)
+	for i := 0; i < len(poly); i++ {
+		poly[i].SetUint64(uint64(i))
+	}
+	var outsideDomain fr.Element
+	outsideDomain.SetUint64(123456789)
+
+	points := map[string]fr.Element{"outside domain": outsideDomain, "in domain": domain.Roots[7]}
+	for _, name := range []string{"outside domain", "in domain"} {
+		point := points[name]
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				_, _ = Open(domain, poly, point, &ck, 1)
+			}
+		})
+	}
+}
+
+// nopBackend is a multi exponentiation backend which does no work, and always returns the point at infinity.
+type nopBackend struct{}
+
+func (nopBackend) MultiExp(points []bls12381.G1Affine, scalars []fr.Element, numGoRoutines int) (*bls12381.G1Affine, error) {
+	return new(bls12381.G1Affine), nil
+}
+
+func BenchmarkBatchVerifyMultiPoints(b *testing.B) {
+	domain := NewDomain(4)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+
+	const maxBatchSize = 4096
+	commitments := make([]Commitment, maxBatchSize)
+	proofs := make([]OpeningProof, maxBatchSize)
+	poly := make(Polynomial, domain.Cardinality)
+	for i := 0; i < maxBatchSize; i++ {
+		for j := 0; j < len(poly); j++ {
+			poly[j].SetUint64(uint64(i*len(poly) + j))
+		}
+		commitment, err := Commit(poly, &srs.CommitKey, 0)
+		if err != nil {
+			b.Fatal(err)
+		}
+		var point fr.Element
+		point.SetUint64(uint64(123456789 + i))
+		proof, err := Open(domain, poly, point, &srs.CommitKey, 0)
+		if err != nil {
+			b.Fatal(err)
+		}
+		commitments[i] = *commitment
+		proofs[i] = proof
+	}
+
+	for _, batchSize := range []int{64, 512, maxBatchSize} {
+		b.Run(fmt.Sprintf("batchSize=%d/single", batchSize), func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				if err := BatchVerifyMultiPoints(commitments[:batchSize], proofs[:batchSize], &srs.OpeningKey); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("batchSize=%d/sub-batched", batchSize), func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				if err := BatchVerifyMultiPointsInSubBatches(commitments[:batchSize], proofs[:batchSize], &srs.OpeningKey, 0); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
 // This is the way it is done in the consensus-specs
 func computeQuotientPolySlow(domain Domain, f Polynomial, z fr.Element) Polynomial {
 	quotient := make([]fr.Element, len(f))
@@ -1,11 +1,18 @@
 package kzg
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
 	"math/big"
+	"runtime"
+	"sync"
 
-	"github.com/consensys/gnark-crypto/ecc"
 	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/crate-crypto/go-kzg-4844/internal/multiexp"
 	"github.com/crate-crypto/go-kzg-4844/internal/utils"
 )
 
@@ -22,9 +29,70 @@ type OpeningProof struct {
 	ClaimedValue fr.Element
 }
 
+// DomSepBatchVerify is the domain separator for the challenge of [BatchVerifyMultiPointsFiatShamir].
+//
+// It matches [RANDOM_CHALLENGE_KZG_BATCH_DOMAIN] in the spec.
+//
+// [RANDOM_CHALLENGE_KZG_BATCH_DOMAIN]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#blob
+const DomSepBatchVerify = "RCKZGBATCH___V1_"
+
+// scalarsPerBlob is FIELD_ELEMENTS_PER_BLOB in the spec, which is hashed into the challenge of
+// [BatchVerifyMultiPointsFiatShamir] so that it matches [verify_kzg_proof_batch].
+//
+// [verify_kzg_proof_batch]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_kzg_proof_batch
+const scalarsPerBlob = 4096
+
+// OpeningProofSize is the number of bytes of an [OpeningProof] serialized with [OpeningProof.Bytes].
+const OpeningProofSize = bls12381.SizeOfG1AffineCompressed + 2*fr.Bytes
+
+// Bytes serializes the proof as the compressed quotient commitment, followed by the input point and the claimed
+// value as 32-byte big-endian integers. This is [OpeningProofSize] bytes.
+func (proof *OpeningProof) Bytes() []byte {
+	quotientCommitment := proof.QuotientCommitment.Bytes()
+	inputPoint := proof.InputPoint.Bytes()
+	claimedValue := proof.ClaimedValue.Bytes()
+
+	res := make([]byte, 0, OpeningProofSize)
+	res = append(res, quotientCommitment[:]...)
+	res = append(res, inputPoint[:]...)
+	return append(res, claimedValue[:]...)
+}
+
+// ParseOpeningProof deserializes a proof serialized with [OpeningProof.Bytes].
+//
+// Returns [ErrInvalidOpeningProofLength] if the input is not [OpeningProofSize] bytes, an error if the quotient
+// commitment is not a point in the prime-order subgroup, and [ErrNonCanonicalScalar] if either scalar is not
+// canonical.
+func ParseOpeningProof(serProof []byte) (OpeningProof, error) {
+	if len(serProof) != OpeningProofSize {
+		return OpeningProof{}, ErrInvalidOpeningProofLength
+	}
+
+	var proof OpeningProof
+	offset := bls12381.SizeOfG1AffineCompressed
+	if _, err := proof.QuotientCommitment.SetBytes(serProof[:offset]); err != nil {
+		return OpeningProof{}, err
+	}
+
+	var err error
+	proof.InputPoint, err = utils.ReduceCanonicalBigEndian(serProof[offset : offset+fr.Bytes])
+	if err != nil {
+		return OpeningProof{}, ErrNonCanonicalScalar
+	}
+	proof.ClaimedValue, err = utils.ReduceCanonicalBigEndian(serProof[offset+fr.Bytes:])
+	if err != nil {
+		return OpeningProof{}, ErrNonCanonicalScalar
+	}
+
+	return proof, nil
+}
+
 // Verify a single KZG proof. See [verify_kzg_proof_impl]. Returns `nil` if verification was successful, an error
 // otherwise. If verification failed due to the pairings check it will return [ErrVerifyOpeningProof].
 //
+// The proof is assumed to be well-formed, as it is when it comes from [ParseOpeningProof] or [Open]. Callers which
+// build proofs from untrusted bytes must check the quotient commitment and the scalars when deserializing them.
+//
 // Note: We could make this method faster by storing pre-computations for the generators in G1 and G2
 // as we only do scalar multiplications with those in this method.
 //
@@ -97,19 +165,136 @@ func Verify(commitment *Commitment, proof *OpeningProof, openKey *OpeningKey) er
 	return nil
 }
 
+// ProofsAgree reports whether two opening proofs are the same: they open at the same input point, to the same claimed
+// value, with the same quotient commitment.
+//
+// For a given commitment, input point and claimed value, the quotient polynomial is unique, so there is only one valid
+// quotient commitment. Hence, if two parties opened the same commitment at the same point and their proofs do not
+// agree, at most one of them is valid. Agreeing proofs are not checked against any commitment, so they may still both
+// be invalid.
+func ProofsAgree(p1, p2 OpeningProof) bool {
+	return p1.InputPoint.Equal(&p2.InputPoint) &&
+		p1.ClaimedValue.Equal(&p2.ClaimedValue) &&
+		p1.QuotientCommitment.Equal(&p2.QuotientCommitment)
+}
+
+// VerifyTwoOpenings verifies two opening proofs for the same commitment with a single pairing check, by calling
+// [BatchVerifyMultiPoints] with the commitment repeated. It returns nil only if both proofs are valid, and is cheaper
+// than calling [Verify] for each of them.
+//
+// The proofs are meant to be at different points. If they are at the same point, they must agree as in [ProofsAgree],
+// in which case the proof is verified once; otherwise at most one of them can be valid and [ErrVerifyOpeningProof] is
+// returned.
+func VerifyTwoOpenings(commitment *Commitment, proof1, proof2 *OpeningProof, openKey *OpeningKey) error {
+	if proof1.InputPoint.Equal(&proof2.InputPoint) {
+		if !ProofsAgree(*proof1, *proof2) {
+			return ErrVerifyOpeningProof
+		}
+		return Verify(commitment, proof1, openKey)
+	}
+
+	return BatchVerifyMultiPoints(
+		[]Commitment{*commitment, *commitment},
+		[]OpeningProof{*proof1, *proof2},
+		openKey,
+	)
+}
+
 // BatchVerifyMultiPoints verifies multiple KZG proofs in a batch. See [verify_kzg_proof_batch].
 //
 //   - This method is more efficient than calling [Verify] multiple times.
 //   - Randomness is used to combine multiple proofs into one.
+//   - The commitments and proofs are only read, so they may be shared with other go-routines.
 //
-// Modified from [gnark-crypto].
+// This is [BatchVerifyMultiPointsWithRand] with the randomness taken from crypto/rand.
 //
 // [verify_kzg_proof_batch]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_kzg_proof_batch
-// [gnark-crypto]: https://github.com/ConsenSys/gnark-crypto/blob/8f7ca09273c24ed9465043566906cbecf5dcee91/ecc/bls12-381/fr/kzg/kzg.go#L367)
 func BatchVerifyMultiPoints(commitments []Commitment, proofs []OpeningProof, openKey *OpeningKey) error {
+	return BatchVerifyMultiPointsWithRand(commitments, proofs, openKey, rand.Reader)
+}
+
+// BatchVerifyMultiPointsWithRand verifies multiple KZG proofs in a batch, deriving the random folding factors from
+// `randReader`. See [BatchVerifyMultiPoints].
+//
+// The soundness of batch verification relies on the folding factors being unpredictable to whoever produced the
+// proofs. Callers should therefore only supply a deterministic reader for testing purposes.
+func BatchVerifyMultiPointsWithRand(commitments []Commitment, proofs []OpeningProof, openKey *OpeningKey, randReader io.Reader) error {
+	_, err := batchVerifyMultiPoints(commitments, proofs, openKey, randReader)
+	return err
+}
+
+// FindFailingProofs returns the indices, in increasing order, of the proofs which do not verify against their
+// commitments, or nil if all of them verify.
+//
+// The whole batch is checked first with [BatchVerifyMultiPoints], so a valid batch costs the same as that. Only if it
+// fails is the batch split in halves, which are checked in the same way down to single proofs. A batch of n proofs of
+// which f are invalid thus costs about 2*f*log2(n) batch checks. The right half is not checked when the left half has
+// no invalid proofs, since then the invalid proofs must be in the right half.
+func FindFailingProofs(commitments []Commitment, proofs []OpeningProof, openKey *OpeningKey) ([]int, error) {
+	if len(commitments) != len(proofs) {
+		return nil, ErrInvalidNumDigests
+	}
+	return findFailingProofs(commitments, proofs, openKey, 0, false, nil)
+}
+
+// findFailingProofs appends to failing the indices of the proofs which do not verify, offset by the index of the first
+// proof in the original batch. The batch check is skipped if the batch is already known to fail.
+func findFailingProofs(commitments []Commitment, proofs []OpeningProof, openKey *OpeningKey, offset int, knownToFail bool, failing []int) ([]int, error) {
+	if len(commitments) == 0 {
+		return failing, nil
+	}
+	if !knownToFail {
+		err := BatchVerifyMultiPoints(commitments, proofs, openKey)
+		if err == nil {
+			return failing, nil
+		}
+		if !errors.Is(err, ErrVerifyOpeningProof) {
+			return nil, err
+		}
+	}
+	if len(commitments) == 1 {
+		return append(failing, offset), nil
+	}
+
+	mid := len(commitments) / 2
+	numFailing := len(failing)
+	failing, err := findFailingProofs(commitments[:mid], proofs[:mid], openKey, offset, false, failing)
+	if err != nil {
+		return nil, err
+	}
+	leftVerified := len(failing) == numFailing
+	return findFailingProofs(commitments[mid:], proofs[mid:], openKey, offset+mid, leftVerified, failing)
+}
+
+// BatchVerifyDebugInfo holds the intermediate values that were computed while folding a batch of proofs in
+// [BatchVerifyMultiPointsDebug]. These can be used to reproduce the exact linear combination that the verifier checked.
+type BatchVerifyDebugInfo struct {
+	// Factors are the random folding factors. The i'th proof in the batch was scaled by Factors[i].
+	Factors []fr.Element
+	// FoldedCommitment is the linear combination of the commitments using Factors.
+	FoldedCommitment Commitment
+	// FoldedEvaluation is the linear combination of the claimed values using Factors.
+	FoldedEvaluation fr.Element
+}
+
+// BatchVerifyMultiPointsDebug behaves like [BatchVerifyMultiPointsWithRand], but additionally returns the folding
+// factors and the folded commitment and evaluation that were computed along the way.
+//
+// The debug information is returned regardless of whether the batch verified. It is left empty if the batch has
+// fewer than two proofs, since no folding takes place in that case, or if an error occurred before folding.
+func BatchVerifyMultiPointsDebug(commitments []Commitment, proofs []OpeningProof, openKey *OpeningKey, randReader io.Reader) (BatchVerifyDebugInfo, error) {
+	return batchVerifyMultiPoints(commitments, proofs, openKey, randReader)
+}
+
+// batchVerifyMultiPoints is the implementation of [BatchVerifyMultiPointsWithRand] and [BatchVerifyMultiPointsDebug].
+//
+// Modified from [gnark-crypto].
+//
+// [gnark-crypto]: https://github.com/ConsenSys/gnark-crypto/blob/8f7ca09273c24ed9465043566906cbecf5dcee91/ecc/bls12-381/fr/kzg/kzg.go#L367)
+func batchVerifyMultiPoints(commitments []Commitment, proofs []OpeningProof, openKey *OpeningKey, randReader io.Reader) (BatchVerifyDebugInfo, error) {
 	// Check consistency number of proofs is equal to the number of commitments.
 	if len(commitments) != len(proofs) {
-		return ErrInvalidNumDigests
+		return BatchVerifyDebugInfo{}, ErrInvalidNumDigests
 	}
 	batchSize := len(commitments)
 
@@ -117,12 +302,12 @@ func BatchVerifyMultiPoints(commitments []Commitment, proofs []OpeningProof, ope
 	// to signal that verification was true.
 	//
 	if batchSize == 0 {
-		return nil
+		return BatchVerifyDebugInfo{}, nil
 	}
 
 	// If batch size is `1`, call Verify
 	if batchSize == 1 {
-		return Verify(&commitments[0], &proofs[0], openKey)
+		return BatchVerifyDebugInfo{}, Verify(&commitments[0], &proofs[0], openKey)
 	}
 
 	// Sample random numbers for sampling.
@@ -131,23 +316,78 @@ func BatchVerifyMultiPoints(commitments []Commitment, proofs []OpeningProof, ope
 	// compute powers of that random number. This works
 	// since powers will produce a vandermonde matrix
 	// which is linearly independent.
-	var randomNumber fr.Element
-	_, err := randomNumber.SetRandom()
+	randomNumber, err := sampleScalar(randReader)
 	if err != nil {
-		return err
+		return BatchVerifyDebugInfo{}, err
+	}
+	return batchVerifyWithFactor(commitments, proofs, openKey, randomNumber)
+}
+
+// BatchVerifyMultiPointsFiatShamir verifies multiple KZG proofs in a batch, like [BatchVerifyMultiPoints], but derives
+// the folding factors from a hash of the batch instead of sampling them, so that verifying the same batch always
+// computes the same linear combination.
+//
+// As in [verify_kzg_proof_batch], the factors are the powers of a challenge r, which is the SHA-256 hash of
+// [DomSepBatchVerify], the number of field elements in a blob and the size of the batch, both as 8 big endian bytes,
+// and then for every proof its commitment, input point, claimed value and quotient commitment, with the scalars as
+// 32 big endian bytes. This is the same challenge as in the spec, byte for byte. Every part of the proofs is hashed,
+// so that the factors cannot be predicted before the proofs are fixed.
+//
+// [verify_kzg_proof_batch]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_kzg_proof_batch
+func BatchVerifyMultiPointsFiatShamir(commitments []Commitment, proofs []OpeningProof, openKey *OpeningKey) error {
+	if len(commitments) != len(proofs) {
+		return ErrInvalidNumDigests
+	}
+	switch len(commitments) {
+	case 0:
+		return nil
+	case 1:
+		return Verify(&commitments[0], &proofs[0], openKey)
 	}
+
+	_, err := batchVerifyWithFactor(commitments, proofs, openKey, computeBatchChallenge(commitments, proofs))
+	return err
+}
+
+// computeBatchChallenge derives the challenge whose powers [BatchVerifyMultiPointsFiatShamir] folds the batch with.
+func computeBatchChallenge(commitments []Commitment, proofs []OpeningProof) fr.Element {
+	h := sha256.New()
+	h.Write([]byte(DomSepBatchVerify))
+	var size [8]byte
+	binary.BigEndian.PutUint64(size[:], scalarsPerBlob)
+	h.Write(size[:])
+	binary.BigEndian.PutUint64(size[:], uint64(len(commitments)))
+	h.Write(size[:])
+	for i := 0; i < len(commitments); i++ {
+		serCommitment := commitments[i].Bytes()
+		inputPoint := proofs[i].InputPoint.Bytes()
+		claimedValue := proofs[i].ClaimedValue.Bytes()
+		serQuotient := proofs[i].QuotientCommitment.Bytes()
+		h.Write(serCommitment[:])
+		h.Write(inputPoint[:])
+		h.Write(claimedValue[:])
+		h.Write(serQuotient[:])
+	}
+
+	var challenge fr.Element
+	challenge.SetBytes(h.Sum(nil))
+	return challenge
+}
+
+// batchVerifyWithFactor folds a batch of at least two proofs with the powers of randomNumber and checks the result
+// with a single pairing check.
+func batchVerifyWithFactor(commitments []Commitment, proofs []OpeningProof, openKey *OpeningKey, randomNumber fr.Element) (BatchVerifyDebugInfo, error) {
+	batchSize := len(commitments)
 	randomNumbers := utils.ComputePowers(randomNumber, uint(batchSize))
 
 	// Combine random_i*quotient_i
-	var foldedQuotients bls12381.G1Affine
 	quotients := make([]bls12381.G1Affine, len(proofs))
 	for i := 0; i < batchSize; i++ {
 		quotients[i].Set(&proofs[i].QuotientCommitment)
 	}
-	config := ecc.MultiExpConfig{}
-	_, err = foldedQuotients.MultiExp(quotients, randomNumbers, config)
+	foldedQuotients, err := multiexp.MultiExpWithBackend(openKey.backend, randomNumbers, quotients, openKey.numGoRoutines)
 	if err != nil {
-		return err
+		return BatchVerifyDebugInfo{}, err
 	}
 
 	// Fold commitments and evaluations using randomness
@@ -155,9 +395,15 @@ func BatchVerifyMultiPoints(commitments []Commitment, proofs []OpeningProof, ope
 	for i := 0; i < len(randomNumbers); i++ {
 		evaluations[i].Set(&proofs[i].ClaimedValue)
 	}
-	foldedCommitments, foldedEvaluations, err := fold(commitments, evaluations, randomNumbers)
+	foldedCommitments, foldedEvaluations, err := fold(openKey.backend, openKey.numGoRoutines, openKey.dedupCommitments, commitments, evaluations, randomNumbers)
 	if err != nil {
-		return err
+		return BatchVerifyDebugInfo{}, err
+	}
+
+	debugInfo := BatchVerifyDebugInfo{
+		Factors:          randomNumbers,
+		FoldedCommitment: foldedCommitments,
+		FoldedEvaluation: foldedEvaluations,
 	}
 
 	// Compute commitment to folded Eval
@@ -170,23 +416,157 @@ func BatchVerifyMultiPoints(commitments []Commitment, proofs []OpeningProof, ope
 	foldedCommitments.Sub(&foldedCommitments, &foldedEvaluationsCommit)
 
 	// Combine random_i*(point_i*quotient_i)
-	var foldedPointsQuotients bls12381.G1Affine
+	//
+	// The coefficients go into a separate slice, so that the random factors are left intact
+	scaledRandomNumbers := make([]fr.Element, batchSize)
 	for i := 0; i < batchSize; i++ {
-		randomNumbers[i].Mul(&randomNumbers[i], &proofs[i].InputPoint)
+		scaledRandomNumbers[i].Mul(&randomNumbers[i], &proofs[i].InputPoint)
 	}
-	_, err = foldedPointsQuotients.MultiExp(quotients, randomNumbers, config)
+	foldedPointsQuotients, err := multiexp.MultiExpWithBackend(openKey.backend, scaledRandomNumbers, quotients, openKey.numGoRoutines)
 	if err != nil {
-		return err
+		return debugInfo, err
 	}
 
 	// `lhs` first pairing
-	foldedCommitments.Add(&foldedCommitments, &foldedPointsQuotients)
+	foldedCommitments.Add(&foldedCommitments, foldedPointsQuotients)
 
 	// `lhs` second pairing
-	foldedQuotients.Neg(&foldedQuotients)
+	foldedQuotients.Neg(foldedQuotients)
 
 	check, err := bls12381.PairingCheck(
-		[]bls12381.G1Affine{foldedCommitments, foldedQuotients},
+		[]bls12381.G1Affine{foldedCommitments, *foldedQuotients},
+		[]bls12381.G2Affine{openKey.GenG2, openKey.AlphaG2},
+	)
+	if err != nil {
+		return debugInfo, err
+	}
+	if !check {
+		return debugInfo, ErrVerifyOpeningProof
+	}
+
+	return debugInfo, nil
+}
+
+// minSubBatchSize is the smallest number of proofs that [BatchVerifyMultiPointsInSubBatches] folds in one sub-batch.
+// Below this size, the multi exponentiations are too small for the go-routines to pay off.
+const minSubBatchSize = 64
+
+// BatchVerifyMultiPointsInSubBatches verifies multiple KZG proofs in a batch, like [BatchVerifyMultiPoints], but splits
+// the batch into sub-batches which are folded concurrently on numGoRoutines go-routines, each with single-threaded
+// multi exponentiations. This is faster than a single fold for batches of thousands of proofs, whose multi
+// exponentiations do not parallelize as well.
+//
+// Every proof is scaled by a power of the same random number, as in [BatchVerifyMultiPoints], so the soundness is the
+// same. Since all sub-batches pair with the same G₂ points, their pairing inputs in G₁ are summed before a single
+// pairing check, rather than adding two pairings per sub-batch.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+func BatchVerifyMultiPointsInSubBatches(commitments []Commitment, proofs []OpeningProof, openKey *OpeningKey, numGoRoutines int) error {
+	return batchVerifyMultiPointsInSubBatches(commitments, proofs, openKey, numGoRoutines, rand.Reader)
+}
+
+func batchVerifyMultiPointsInSubBatches(commitments []Commitment, proofs []OpeningProof, openKey *OpeningKey, numGoRoutines int, randReader io.Reader) error {
+	if len(commitments) != len(proofs) {
+		return ErrInvalidNumDigests
+	}
+	batchSize := len(commitments)
+	if batchSize == 0 {
+		return nil
+	}
+	if batchSize == 1 {
+		return Verify(&commitments[0], &proofs[0], openKey)
+	}
+
+	if numGoRoutines <= 0 {
+		numGoRoutines = runtime.NumCPU()
+	}
+	numSubBatches := (batchSize + minSubBatchSize - 1) / minSubBatchSize
+	if numSubBatches > numGoRoutines {
+		numSubBatches = numGoRoutines
+	}
+
+	randomNumber, err := sampleScalar(randReader)
+	if err != nil {
+		return err
+	}
+	randomNumbers := utils.ComputePowers(randomNumber, uint(batchSize))
+
+	// Each sub-batch computes its share of sum r_i*(C_i + z_i*π_i), sum r_i*π_i and sum r_i*y_i
+	foldedLhs := make([]bls12381.G1Jac, numSubBatches)
+	foldedQuotients := make([]bls12381.G1Jac, numSubBatches)
+	foldedEvaluations := make([]fr.Element, numSubBatches)
+	errs := make([]error, numSubBatches)
+	var wg sync.WaitGroup
+	wg.Add(numSubBatches)
+	for k := 0; k < numSubBatches; k++ {
+		go func(k int) {
+			defer wg.Done()
+			start, end := k*batchSize/numSubBatches, (k+1)*batchSize/numSubBatches
+			n := end - start
+
+			quotients := make([]bls12381.G1Affine, n)
+			scaledRandomNumbers := make([]fr.Element, n)
+			for i := 0; i < n; i++ {
+				proof := &proofs[start+i]
+				quotients[i] = proof.QuotientCommitment
+				scaledRandomNumbers[i].Mul(&randomNumbers[start+i], &proof.InputPoint)
+
+				var tmp fr.Element
+				tmp.Mul(&randomNumbers[start+i], &proof.ClaimedValue)
+				foldedEvaluations[k].Add(&foldedEvaluations[k], &tmp)
+			}
+
+			foldedCommitment, err := multiexp.MultiExpWithBackend(openKey.backend, randomNumbers[start:end], commitments[start:end], 1)
+			if err != nil {
+				errs[k] = err
+				return
+			}
+			foldedPointsQuotients, err := multiexp.MultiExpWithBackend(openKey.backend, scaledRandomNumbers, quotients, 1)
+			if err != nil {
+				errs[k] = err
+				return
+			}
+			foldedQuotient, err := multiexp.MultiExpWithBackend(openKey.backend, randomNumbers[start:end], quotients, 1)
+			if err != nil {
+				errs[k] = err
+				return
+			}
+
+			foldedLhs[k].FromAffine(foldedCommitment)
+			foldedLhs[k].AddMixed(foldedPointsQuotients)
+			foldedQuotients[k].FromAffine(foldedQuotient)
+		}(k)
+	}
+	wg.Wait()
+
+	// Sum the sub-batches
+	var lhsJac, quotientsJac bls12381.G1Jac
+	var foldedEvaluation fr.Element
+	for k := 0; k < numSubBatches; k++ {
+		if errs[k] != nil {
+			return errs[k]
+		}
+		lhsJac.AddAssign(&foldedLhs[k])
+		quotientsJac.AddAssign(&foldedQuotients[k])
+		foldedEvaluation.Add(&foldedEvaluation, &foldedEvaluations[k])
+	}
+
+	// `lhs` first pairing: sum r_i*(C_i + z_i*π_i) - [sum r_i*y_i]G₁
+	var foldedEvaluationBigInt big.Int
+	foldedEvaluation.BigInt(&foldedEvaluationBigInt)
+	var foldedEvaluationCommit bls12381.G1Jac
+	foldedEvaluationCommit.ScalarMultiplicationAffine(&openKey.GenG1, &foldedEvaluationBigInt)
+	lhsJac.SubAssign(&foldedEvaluationCommit)
+
+	// `lhs` second pairing
+	quotientsJac.Neg(&quotientsJac)
+
+	var lhs, negQuotients bls12381.G1Affine
+	lhs.FromJacobian(&lhsJac)
+	negQuotients.FromJacobian(&quotientsJac)
+	check, err := bls12381.PairingCheck(
+		[]bls12381.G1Affine{lhs, negQuotients},
 		[]bls12381.G2Affine{openKey.GenG2, openKey.AlphaG2},
 	)
 	if err != nil {
@@ -199,15 +579,55 @@ func BatchVerifyMultiPoints(commitments []Commitment, proofs []OpeningProof, ope
 	return nil
 }
 
+// sampleScalar samples a uniformly random field element from the bytes read from `randReader`.
+//
+// This uses rejection sampling: We read 32 bytes and clear the most significant bit, so that the candidate
+// is less than 2^255. The candidate is accepted if it is less than the modulus and rejected otherwise. Since the
+// modulus is roughly 0.9 * 2^255, about one in ten candidates is expected to be rejected.
+func sampleScalar(randReader io.Reader) (fr.Element, error) {
+	var buf [fr.Bytes]byte
+	for {
+		_, err := io.ReadFull(randReader, buf[:])
+		if err != nil {
+			return fr.Element{}, err
+		}
+		buf[0] &= 0x7f
+
+		var scalar fr.Element
+		err = scalar.SetBytesCanonical(buf[:])
+		if err == nil {
+			return scalar, nil
+		}
+	}
+}
+
+// FoldCommitments computes the linear combinations of `commitments` and `evaluations` using `factors`. This exposes the
+// folding step used by [BatchVerifyMultiPoints], so that a batch can be checked step by step; see [BatchVerifyDebugInfo].
+//
+// Returns [ErrInvalidNumDigests] if the three slices do not have the same length.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+func FoldCommitments(commitments []Commitment, evaluations, factors []fr.Element, numGoRoutines int) (Commitment, fr.Element, error) {
+	if len(commitments) != len(evaluations) || len(commitments) != len(factors) {
+		return Commitment{}, fr.Element{}, ErrInvalidNumDigests
+	}
+
+	return fold(nil, numGoRoutines, false, commitments, evaluations, factors)
+}
+
 // fold computes two inner products with the same factors:
 //
 //   - Between commitments and factors; This is a multi-exponentiation.
 //   - Between evaluations and factors; This is a dot product.
 //
+// The multi-exponentiation is computed with the given backend, or with gnark-crypto if it is nil, on numGoRoutines
+// go-routines. If dedup is set, identical commitments are merged first with [dedupCommitments].
+//
 // Modified slightly from [gnark-crypto].
 //
 // [gnark-crypto]: https://github.com/ConsenSys/gnark-crypto/blob/8f7ca09273c24ed9465043566906cbecf5dcee91/ecc/bls12-381/fr/kzg/kzg.go#L464
-func fold(commitments []Commitment, evaluations, factors []fr.Element) (Commitment, fr.Element, error) {
+func fold(backend multiexp.Backend, numGoRoutines int, dedup bool, commitments []Commitment, evaluations, factors []fr.Element) (Commitment, fr.Element, error) {
 	// Length inconsistency between commitments and evaluations should have been done before calling this function
 	batchSize := len(commitments)
 
@@ -219,11 +639,33 @@ func fold(commitments []Commitment, evaluations, factors []fr.Element) (Commitme
 	}
 
 	// Fold the commitments
-	var foldedCommitments Commitment
-	_, err := foldedCommitments.MultiExp(commitments, factors, ecc.MultiExpConfig{})
+	if dedup {
+		commitments, factors = dedupCommitments(commitments, factors)
+	}
+	foldedCommitments, err := multiexp.MultiExpWithBackend(backend, factors, commitments, numGoRoutines)
 	if err != nil {
-		return foldedCommitments, foldedEvaluations, err
+		return Commitment{}, foldedEvaluations, err
 	}
 
-	return foldedCommitments, foldedEvaluations, nil
+	return *foldedCommitments, foldedEvaluations, nil
+}
+
+// dedupCommitments returns the distinct commitments, in the order in which they first appear, along with the sum of
+// the factors of each of their occurrences. The multi exponentiation of the result is thus the same as that of the
+// input, with one base point per distinct commitment. The inputs are not modified.
+func dedupCommitments(commitments []Commitment, factors []fr.Element) ([]Commitment, []fr.Element) {
+	indices := make(map[Commitment]int, len(commitments))
+	uniqueCommitments := make([]Commitment, 0, len(commitments))
+	summedFactors := make([]fr.Element, 0, len(commitments))
+	for i := 0; i < len(commitments); i++ {
+		j, ok := indices[commitments[i]]
+		if !ok {
+			indices[commitments[i]] = len(uniqueCommitments)
+			uniqueCommitments = append(uniqueCommitments, commitments[i])
+			summedFactors = append(summedFactors, factors[i])
+			continue
+		}
+		summedFactors[j].Add(&summedFactors[j], &factors[i])
+	}
+	return uniqueCommitments, summedFactors
 }
@@ -0,0 +1,132 @@
+package kzg
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/crate-crypto/go-kzg-4844/internal/multiexp"
+	"golang.org/x/sync/errgroup"
+)
+
+// lazyChunkSize is the number of points that a [LazyCommitKey] decompresses at once.
+const lazyChunkSize = 1024
+
+// LazyCommitKey is a commit key whose G1 points are held compressed, and only decompressed when a commitment first
+// needs them. This is meant for setups that are much larger than the polynomials which are committed to, where
+// decompressing every point upfront would be too slow.
+//
+// The points are decompressed in chunks of [lazyChunkSize], each exactly once, and then cached. A LazyCommitKey is
+// safe for concurrent use by multiple go-routines.
+type LazyCommitKey struct {
+	// compressed holds the compressed points, one after the other. It is only read.
+	compressed []byte
+	// points[i] is only set once the chunk holding it has been decompressed without error.
+	points []bls12381.G1Affine
+	chunks []lazyChunk
+
+	// backend computes the multi exponentiations, if set.
+	backend multiexp.Backend
+}
+
+// lazyChunk records the decompression of a chunk of the points of a [LazyCommitKey].
+type lazyChunk struct {
+	once sync.Once
+	err  error
+}
+
+// NewLazyCommitKey returns a [LazyCommitKey] for the compressed G1 points in `compressed`, 48 bytes each, which must
+// not be modified afterwards. The points are not read until they are needed, so an invalid point is only reported by
+// the first commitment which uses it.
+//
+// Returns an error wrapping [ErrInvalidPolynomialSize] if the length of `compressed` is not a positive multiple of 48.
+func NewLazyCommitKey(compressed []byte) (*LazyCommitKey, error) {
+	if len(compressed) == 0 || len(compressed)%bls12381.SizeOfG1AffineCompressed != 0 {
+		return nil, fmt.Errorf("%w: got %d bytes of compressed points", ErrInvalidPolynomialSize, len(compressed))
+	}
+
+	numPoints := len(compressed) / bls12381.SizeOfG1AffineCompressed
+	return &LazyCommitKey{
+		compressed: compressed,
+		points:     make([]bls12381.G1Affine, numPoints),
+		chunks:     make([]lazyChunk, (numPoints+lazyChunkSize-1)/lazyChunkSize),
+	}, nil
+}
+
+// SetMultiExpBackend sets the backend used by [CommitLazy]. A nil backend restores the default.
+func (k *LazyCommitKey) SetMultiExpBackend(backend multiexp.Backend) {
+	k.backend = backend
+}
+
+// Len returns the number of points of the commit key.
+func (k *LazyCommitKey) Len() int {
+	return len(k.points)
+}
+
+// Points returns the first n points of the commit key, decompressing the ones that were not needed before. Each point
+// is checked to be on the curve and in the prime-order subgroup when it is decompressed; if it is not, the returned
+// error contains its index, and so does every later call which needs it.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+func (k *LazyCommitKey) Points(n, numGoRoutines int) ([]bls12381.G1Affine, error) {
+	if n < 0 || n > len(k.points) {
+		return nil, fmt.Errorf("%w: got %d points, expected at most %d", ErrInvalidPolynomialSize, n, len(k.points))
+	}
+
+	if numGoRoutines <= 0 {
+		numGoRoutines = runtime.NumCPU()
+	}
+	var errG errgroup.Group
+	errG.SetLimit(numGoRoutines)
+	numChunks := (n + lazyChunkSize - 1) / lazyChunkSize
+	for c := 0; c < numChunks; c++ {
+		c := c // Capture the value of the loop variable
+		errG.Go(func() error {
+			chunk := &k.chunks[c]
+			chunk.once.Do(func() { chunk.err = k.decompressChunk(c) })
+			return chunk.err
+		})
+	}
+	if err := errG.Wait(); err != nil {
+		return nil, err
+	}
+	return k.points[:n], nil
+}
+
+// decompressChunk decompresses the points of chunk c into k.points.
+func (k *LazyCommitKey) decompressChunk(c int) error {
+	start := c * lazyChunkSize
+	end := start + lazyChunkSize
+	if end > len(k.points) {
+		end = len(k.points)
+	}
+
+	points := make([]bls12381.G1Affine, end-start)
+	for i := range points {
+		offset := (start + i) * bls12381.SizeOfG1AffineCompressed
+		// SetBytes checks that the point is in the subgroup
+		if _, err := points[i].SetBytes(k.compressed[offset : offset+bls12381.SizeOfG1AffineCompressed]); err != nil {
+			return fmt.Errorf("point %d: %w", start+i, err)
+		}
+	}
+	copy(k.points[start:end], points)
+	return nil
+}
+
+// CommitLazy commits to a polynomial as [Commit] would with the points of `k`, after decompressing the first len(p)
+// of them with [LazyCommitKey.Points].
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+func CommitLazy(p Polynomial, k *LazyCommitKey, numGoRoutines int) (*Commitment, error) {
+	if len(p) == 0 || len(p) > k.Len() {
+		return nil, fmt.Errorf("%w: got %d evaluations, expected between 1 and %d", ErrInvalidPolynomialSize, len(p), k.Len())
+	}
+	points, err := k.Points(len(p), numGoRoutines)
+	if err != nil {
+		return nil, err
+	}
+	return multiexp.MultiExpWithBackend(k.backend, p, points, numGoRoutines)
+}
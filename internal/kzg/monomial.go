@@ -0,0 +1,122 @@
+package kzg
+
+import "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+
+// DividePolyByXminusAMonomial divides the polynomial f(X), given by its coefficients starting with the constant term,
+// by X - a using synthetic division. It returns the coefficients of the quotient q(X), which has one coefficient less
+// than f(X), and the remainder, such that f(X) = q(X)(X - a) + remainder. The remainder is f(a).
+//
+// Unlike the division done when opening a polynomial in lagrange form, this does not need a domain, and `a` may be
+// any field element.
+func DividePolyByXminusAMonomial(coeffs []fr.Element, a fr.Element) ([]fr.Element, fr.Element) {
+	if len(coeffs) == 0 {
+		return nil, fr.Element{}
+	}
+
+	quotient := make([]fr.Element, len(coeffs)-1)
+	remainder := dividePolyByXminusAMonomialInto(quotient, coeffs, a)
+	return quotient, remainder
+}
+
+// dividePolyByXminusAMonomialInto is [DividePolyByXminusAMonomial], writing the quotient into the first
+// len(coeffs)-1 elements of `quotient` instead of allocating it. `quotient` may be `coeffs` itself, in which case the
+// division is done in place. There must be at least one coefficient.
+func dividePolyByXminusAMonomialInto(quotient, coeffs []fr.Element, a fr.Element) fr.Element {
+	// Horner's method, where the intermediate values are the coefficients of the quotient:
+	// q_{n-2} = f_{n-1}, q_{i-1} = f_i + a*q_i, and the remainder is f_0 + a*q_0
+	remainder := coeffs[len(coeffs)-1]
+	for i := len(coeffs) - 2; i >= 0; i-- {
+		// f_i is read before q_i is written, since they are the same element when dividing in place
+		coeff := coeffs[i]
+		quotient[i] = remainder
+		remainder.Mul(&remainder, &a).Add(&remainder, &coeff)
+	}
+	return remainder
+}
+
+// CommitQuotientMonomial divides the polynomial f(X), given by its coefficients starting with the constant term, by
+// X - a with [DividePolyByXminusAMonomial], and commits to the quotient with ck, which must hold the monomial SRS. It
+// returns the commitment to the quotient and f(a), which together are the opening proof of f(X) at a, checked by
+// [Verify] with an [OpeningKey] whose AlphaG2 is the degree-1 G2 point of the same SRS. Unlike [Open], the polynomial
+// does not need to be converted to lagrange form, and `a` may be any field element.
+//
+// Returns an error wrapping [ErrInvalidPolynomialSize] if there are no coefficients or more than len(ck.G1). The
+// quotient of a constant polynomial is zero, and so its commitment is the point at infinity.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+func CommitQuotientMonomial(coeffs []fr.Element, a fr.Element, ck *CommitKey, numGoRoutines int) (Commitment, fr.Element, error) {
+	if err := CheckPolynomialSize(coeffs, ck); err != nil {
+		return Commitment{}, fr.Element{}, err
+	}
+
+	// The quotient is only committed to, so it is scratch space
+	quotientScratch := getScratch(len(coeffs) - 1)
+	defer putScratch(quotientScratch)
+	quotient := *quotientScratch
+	remainder := dividePolyByXminusAMonomialInto(quotient, coeffs, a)
+	if len(quotient) == 0 {
+		return Commitment{}, remainder, nil
+	}
+	commitment, err := Commit(quotient, ck, numGoRoutines)
+	if err != nil {
+		return Commitment{}, fr.Element{}, err
+	}
+	return *commitment, remainder, nil
+}
+
+// InterpolateMonomial returns the coefficients, starting with the constant term, of the unique polynomial f(X) of
+// degree less than len(xs) with f(xs[i]) = ys[i], using Lagrange interpolation:
+//
+//	f(X) = sum_i ys[i] * Z(X) / ((X - xs[i]) * Z'(xs[i]))
+//
+// where Z(X) is the polynomial vanishing at all of the xs. This takes O(n^2) field operations, and so is meant for
+// small sets of points, or points which are not a domain.
+//
+// Returns [ErrInterpolationLengthMismatch] if the number of points and values differ, and an error wrapping
+// [ErrDuplicateInterpolationPoint] if two of the points are equal. No points give the zero polynomial, with no
+// coefficients.
+func InterpolateMonomial(xs, ys []fr.Element) (Polynomial, error) {
+	if len(xs) != len(ys) {
+		return nil, ErrInterpolationLengthMismatch
+	}
+	if err := checkDistinctPoints(xs); err != nil {
+		return nil, err
+	}
+	if len(xs) == 0 {
+		return Polynomial{}, nil
+	}
+
+	// Z(X) / (X - xs[i]) evaluated at xs[i] is Z'(xs[i]) = prod_{j != i} (xs[i] - xs[j]), which is non-zero since the
+	// points are distinct
+	vanishingPoly := vanishingPolyCoeff(xs)
+	numerators := make([]Polynomial, len(xs))
+	denominators := make([]fr.Element, len(xs))
+	for i := 0; i < len(xs); i++ {
+		numerators[i], _ = DividePolyByXminusAMonomial(vanishingPoly, xs[i])
+		denominators[i] = evaluateMonomial(numerators[i], xs[i])
+	}
+	denominators = fr.BatchInvert(denominators)
+
+	poly := make(Polynomial, len(xs))
+	for i := 0; i < len(xs); i++ {
+		var scale fr.Element
+		scale.Mul(&ys[i], &denominators[i])
+		for k := 0; k < len(poly); k++ {
+			var tmp fr.Element
+			tmp.Mul(&numerators[i][k], &scale)
+			poly[k].Add(&poly[k], &tmp)
+		}
+	}
+	return poly, nil
+}
+
+// evaluateMonomial evaluates the polynomial, given by its coefficients starting with the constant term, at x using
+// Horner's method.
+func evaluateMonomial(coeffs []fr.Element, x fr.Element) fr.Element {
+	var result fr.Element
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		result.Mul(&result, &x).Add(&result, &coeffs[i])
+	}
+	return result
+}
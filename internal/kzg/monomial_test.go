@@ -0,0 +1,116 @@
+package kzg
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDividePolyByXminusAMonomial(t *testing.T) {
+	for _, size := range []int{1, 2, 17, 4096} {
+		coeffs := make([]fr.Element, size)
+		for i := range coeffs {
+			_, _ = coeffs[i].SetRandom()
+		}
+		var a, x fr.Element
+		_, _ = a.SetRandom()
+		_, _ = x.SetRandom()
+
+		quotient, remainder := DividePolyByXminusAMonomial(coeffs, a)
+		require.Len(t, quotient, size-1)
+
+		// The remainder is f(a)
+		fa := evaluateMonomial(coeffs, a)
+		require.True(t, remainder.Equal(&fa))
+
+		// f(x) = q(x)(x - a) + f(a) at some other point x
+		var rhs, xMinusA fr.Element
+		qx := evaluateMonomial(quotient, x)
+		xMinusA.Sub(&x, &a)
+		rhs.Mul(&qx, &xMinusA).Add(&rhs, &remainder)
+		fx := evaluateMonomial(coeffs, x)
+		require.True(t, fx.Equal(&rhs))
+	}
+
+	// Dividing a polynomial which vanishes at a leaves no remainder: X^2 - 4 = (X + 2)(X - 2)
+	var minusFour fr.Element
+	minusFour.SetInt64(-4)
+	quotient, remainder := DividePolyByXminusAMonomial([]fr.Element{minusFour, {}, fr.One()}, fr.NewElement(2))
+	require.True(t, remainder.IsZero())
+	require.Equal(t, []fr.Element{fr.NewElement(2), fr.One()}, quotient)
+
+	quotient, remainder = DividePolyByXminusAMonomial(nil, fr.One())
+	require.Len(t, quotient, 0)
+	require.True(t, remainder.IsZero())
+}
+
+func TestInterpolateMonomial(t *testing.T) {
+	for _, size := range []int{1, 2, 17, 64} {
+		xs := make([]fr.Element, size)
+		ys := make([]fr.Element, size)
+		for i := 0; i < size; i++ {
+			_, _ = xs[i].SetRandom()
+			_, _ = ys[i].SetRandom()
+		}
+
+		poly, err := InterpolateMonomial(xs, ys)
+		require.NoError(t, err)
+		require.Len(t, poly, size)
+		for i := 0; i < size; i++ {
+			y := evaluateMonomial(poly, xs[i])
+			require.True(t, y.Equal(&ys[i]))
+		}
+	}
+
+	// The points 1, 2, 3 with values 2, 5, 10 are on X^2 + 1
+	poly, err := InterpolateMonomial(
+		[]fr.Element{fr.NewElement(1), fr.NewElement(2), fr.NewElement(3)},
+		[]fr.Element{fr.NewElement(2), fr.NewElement(5), fr.NewElement(10)},
+	)
+	require.NoError(t, err)
+	require.Equal(t, Polynomial{fr.One(), {}, fr.One()}, poly)
+
+	poly, err = InterpolateMonomial(nil, nil)
+	require.NoError(t, err)
+	require.Len(t, poly, 0)
+
+	_, err = InterpolateMonomial([]fr.Element{fr.One()}, nil)
+	require.ErrorIs(t, err, ErrInterpolationLengthMismatch)
+	_, err = InterpolateMonomial([]fr.Element{fr.One(), fr.NewElement(2), fr.One()}, make([]fr.Element, 3))
+	require.ErrorIs(t, err, ErrDuplicateInterpolationPoint)
+}
+
+func TestCommitQuotientMonomial(t *testing.T) {
+	srs, err := newMonomialSRSInsecureUint64(64, big.NewInt(1234))
+	require.NoError(t, err)
+
+	for _, size := range []int{1, 2, 64} {
+		coeffs := make([]fr.Element, size)
+		for i := range coeffs {
+			_, _ = coeffs[i].SetRandom()
+		}
+		commitment, err := Commit(coeffs, &srs.CommitKey, 0)
+		require.NoError(t, err)
+
+		var a fr.Element
+		_, _ = a.SetRandom()
+		quotientCommitment, claimedValue, err := CommitQuotientMonomial(coeffs, a, &srs.CommitKey, 0)
+		require.NoError(t, err)
+		fa := evaluateMonomial(coeffs, a)
+		require.True(t, claimedValue.Equal(&fa))
+
+		// e(C - [f(a)]G1, G2) = e(π, [s - a]G2)
+		proof := OpeningProof{QuotientCommitment: quotientCommitment, InputPoint: a, ClaimedValue: claimedValue}
+		require.NoError(t, Verify(commitment, &proof, &srs.OpeningKey))
+		one := fr.One()
+		proof.ClaimedValue.Add(&proof.ClaimedValue, &one)
+		require.ErrorIs(t, Verify(commitment, &proof, &srs.OpeningKey), ErrVerifyOpeningProof)
+	}
+
+	_, _, err = CommitQuotientMonomial(nil, fr.One(), &srs.CommitKey, 0)
+	require.ErrorIs(t, err, ErrInvalidPolynomialSize)
+	_, _, err = CommitQuotientMonomial(make([]fr.Element, 65), fr.One(), &srs.CommitKey, 0)
+	require.ErrorIs(t, err, ErrInvalidPolynomialSize)
+}
@@ -0,0 +1,143 @@
+package kzg
+
+import (
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// cosetGenerator is used to shift the domain, so that the vanishing polynomial of the missing evaluations can be
+// divided out without hitting any of its roots. It is the multiplicative generator of the scalar field, so the
+// shifted domain does not intersect the domain.
+const cosetGenerator = 7
+
+// RecoverPolynomialCoeffs recovers the coefficients of a polynomial with at most domain.Cardinality/2 coefficients,
+// from its evaluations over some of the cosets of the subgroup of order cosetSize in the domain.
+//
+// The evaluations over the domain are considered in bit-reversed order and split into chunks of cosetSize, each chunk
+// holding the evaluations over one coset. cosetIndices[i] is the index of the chunk that cosetEvaluations[i] holds.
+// The caller must check that the indices are distinct, in range, and that at least half of the chunks are given.
+//
+// Since the polynomial has at most half as many coefficients as there are points in the domain, this is erasure
+// decoding of a Reed-Solomon code of rate 1/2. Writing E(X) for the polynomial which matches the known evaluations and
+// is zero elsewhere, and Z(X) for the polynomial vanishing over the missing cosets, we have E(X)Z(X) = f(X)Z(X) over
+// the whole domain, so f(X) is recovered by dividing E(X)Z(X) by Z(X) over a shifted domain where Z(X) has no roots.
+//
+// Returns [ErrInconsistentEvaluations] if the evaluations do not belong to a polynomial with at most
+// domain.Cardinality/2 coefficients.
+//
+// The roots of the domain may be in either order, since only its generator is used.
+func (domain *Domain) RecoverPolynomialCoeffs(cosetIndices []uint64, cosetEvaluations [][]fr.Element, cosetSize int) (Polynomial, error) {
+	if len(cosetIndices) != len(cosetEvaluations) {
+		return nil, ErrInvalidNumDigests
+	}
+	numPoints := int(domain.Cardinality)
+	numCosets := numPoints / cosetSize
+
+	// Compute E(X) in evaluation form, in natural order.
+	extEvaluations := make([]fr.Element, numPoints)
+	isKnown := make([]bool, numCosets)
+	for i, cosetIndex := range cosetIndices {
+		if len(cosetEvaluations[i]) != cosetSize {
+			return nil, ErrPolynomialMismatchedSizeDomain
+		}
+		copy(extEvaluations[int(cosetIndex)*cosetSize:], cosetEvaluations[i])
+		isKnown[cosetIndex] = true
+	}
+	bitReverse(extEvaluations)
+
+	// Compute Z(X) in monomial form.
+	//
+	// The coset with index k is h_k*H, where h_k^cosetSize is the bit-reversed k'th root of unity of order numCosets.
+	// So Z(X) = S(X^cosetSize), where S(Y) is the polynomial vanishing over those roots of unity.
+	var rootOfUnity fr.Element
+	rootOfUnity.Exp(domain.Generator, big.NewInt(int64(cosetSize)))
+	shiftCorrection := 64 - bits.TrailingZeros64(uint64(numCosets))
+	var missingRoots []fr.Element
+	for k := 0; k < numCosets; k++ {
+		if !isKnown[k] {
+			var root fr.Element
+			root.Exp(rootOfUnity, big.NewInt(int64(bits.Reverse64(uint64(k))>>shiftCorrection)))
+			missingRoots = append(missingRoots, root)
+		}
+	}
+	shortVanishingPoly := vanishingPolyCoeff(missingRoots)
+	vanishingPoly := make(Polynomial, numPoints)
+	for i := range shortVanishingPoly {
+		vanishingPoly[i*cosetSize] = shortVanishingPoly[i]
+	}
+
+	// Compute E(X)Z(X) in monomial form
+	vanishingPolyEvals := domain.FftFr(vanishingPoly)
+	for i := 0; i < numPoints; i++ {
+		extEvaluations[i].Mul(&extEvaluations[i], &vanishingPolyEvals[i])
+	}
+	extTimesVanishingCoeff := domain.IfftFr(extEvaluations)
+
+	// Divide E(X)Z(X) by Z(X) over the shifted domain
+	var shift fr.Element
+	shift.SetUint64(cosetGenerator)
+	extTimesVanishingCoset := domain.cosetFftFr(extTimesVanishingCoeff, shift)
+	vanishingPolyCoset := domain.cosetFftFr(vanishingPoly, shift)
+	vanishingPolyCoset = fr.BatchInvert(vanishingPolyCoset)
+	for i := 0; i < numPoints; i++ {
+		extTimesVanishingCoset[i].Mul(&extTimesVanishingCoset[i], &vanishingPolyCoset[i])
+	}
+	polyCoeff := domain.cosetIfftFr(extTimesVanishingCoset, shift)
+
+	// The upper half of the coefficients must be zero
+	for i := numPoints / 2; i < numPoints; i++ {
+		if !polyCoeff[i].IsZero() {
+			return nil, ErrInconsistentEvaluations
+		}
+	}
+
+	return polyCoeff[:numPoints/2], nil
+}
+
+// vanishingPolyCoeff returns the coefficients of the polynomial with leading coefficient one, which vanishes
+// exactly at the given roots.
+func vanishingPolyCoeff(roots []fr.Element) Polynomial {
+	poly := make(Polynomial, 1, len(roots)+1)
+	poly[0].SetOne()
+
+	// Multiply by (X - root) for each root
+	for _, root := range roots {
+		poly = append(poly, fr.Element{})
+		for i := len(poly) - 1; i > 0; i-- {
+			var tmp fr.Element
+			tmp.Mul(&poly[i], &root)
+			poly[i].Sub(&poly[i-1], &tmp)
+		}
+		poly[0].Mul(&poly[0], &root)
+		poly[0].Neg(&poly[0])
+	}
+
+	return poly
+}
+
+// cosetFftFr evaluates the polynomial, given by its coefficients, over the domain shifted by `shift`.
+func (domain *Domain) cosetFftFr(polyCoeff Polynomial, shift fr.Element) []fr.Element {
+	scaled := make([]fr.Element, len(polyCoeff))
+	shiftPow := fr.One()
+	for i := 0; i < len(polyCoeff); i++ {
+		scaled[i].Mul(&polyCoeff[i], &shiftPow)
+		shiftPow.Mul(&shiftPow, &shift)
+	}
+	return domain.FftFr(scaled)
+}
+
+// cosetIfftFr is the inverse of cosetFftFr.
+func (domain *Domain) cosetIfftFr(evaluations []fr.Element, shift fr.Element) Polynomial {
+	polyCoeff := domain.IfftFr(evaluations)
+
+	var shiftInv fr.Element
+	shiftInv.Inverse(&shift)
+	shiftInvPow := fr.One()
+	for i := 0; i < len(polyCoeff); i++ {
+		polyCoeff[i].Mul(&polyCoeff[i], &shiftInvPow)
+		shiftInvPow.Mul(&shiftInvPow, &shiftInv)
+	}
+	return polyCoeff
+}
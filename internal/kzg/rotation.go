@@ -0,0 +1,138 @@
+package kzg
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// DomSepRotation is the domain separator for the challenge of a [RotationProof].
+const DomSepRotation = "KZGROTATION_V1__"
+
+// RotationProof proves that two commitments are to polynomials f(X) and g(X) = f(w^k * X), where w is the generator of
+// the domain. In evaluation form, g is f with its evaluations cyclically shifted by k positions.
+//
+// Both polynomials are opened at a challenge z derived from the commitments, and the proof holds when
+// f(w^k * z) = g(z). By the Schwartz-Zippel lemma, this implies that g(X) = f(w^k * X) with overwhelming probability.
+type RotationProof struct {
+	// Opening of f(X) at w^k * z.
+	Proof OpeningProof
+	// Opening of g(X) at z.
+	RotatedProof OpeningProof
+}
+
+// RotatePolynomial returns the polynomial g(X) = f(w^k * X) in evaluation form, where w is the generator of the domain
+// and f is given in evaluation form. This is a permutation of the evaluations, since the evaluation of g at a root of
+// unity r is the evaluation of f at w^k * r, which is also in the domain.
+//
+// The roots of the domain may be in either order. Returns [ErrPolynomialMismatchedSizeDomain] if the number of
+// evaluations is not the cardinality of the domain.
+func (domain *Domain) RotatePolynomial(p Polynomial, k uint64) (Polynomial, error) {
+	if uint64(len(p)) != domain.Cardinality {
+		return nil, ErrPolynomialMismatchedSizeDomain
+	}
+
+	rootIndices := make(map[fr.Element]int, len(domain.Roots))
+	for i, root := range domain.Roots {
+		rootIndices[root] = i
+	}
+
+	var shift fr.Element
+	shift.Exp(domain.Generator, new(big.Int).SetUint64(k%domain.Cardinality))
+
+	rotated := make(Polynomial, len(p))
+	for i := 0; i < len(p); i++ {
+		var shiftedRoot fr.Element
+		shiftedRoot.Mul(&domain.Roots[i], &shift)
+		rotated[i] = p[rootIndices[shiftedRoot]]
+	}
+	return rotated, nil
+}
+
+// ProveRotation rotates the polynomial p by k positions with [Domain.RotatePolynomial], commits to the rotated
+// polynomial, and proves that the commitment is to the rotation of p.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+func ProveRotation(domain *Domain, p Polynomial, k uint64, ck *CommitKey, numGoRoutines int) (Polynomial, *Commitment, RotationProof, error) {
+	rotated, err := domain.RotatePolynomial(p, k)
+	if err != nil {
+		return nil, nil, RotationProof{}, err
+	}
+
+	commitment, err := Commit(p, ck, numGoRoutines)
+	if err != nil {
+		return nil, nil, RotationProof{}, err
+	}
+	rotatedCommitment, err := Commit(rotated, ck, numGoRoutines)
+	if err != nil {
+		return nil, nil, RotationProof{}, err
+	}
+
+	z := computeRotationChallenge(commitment, rotatedCommitment, k, domain.Cardinality)
+	shiftedZ := shiftedRotationPoint(domain, z, k)
+
+	proof, err := Open(domain, p, shiftedZ, ck, numGoRoutines)
+	if err != nil {
+		return nil, nil, RotationProof{}, err
+	}
+	rotatedProof, err := Open(domain, rotated, z, ck, numGoRoutines)
+	if err != nil {
+		return nil, nil, RotationProof{}, err
+	}
+
+	return rotated, rotatedCommitment, RotationProof{Proof: proof, RotatedProof: rotatedProof}, nil
+}
+
+// VerifyRotation verifies a proof, computed by [ProveRotation], that rotatedCommitment is a commitment to the rotation
+// by k positions of the polynomial that commitment is to. The domain must be the one that the proof was computed
+// with, though the order of its roots does not matter.
+//
+// The two openings are checked in a batch, with a single pairing check.
+func VerifyRotation(domain *Domain, commitment, rotatedCommitment *Commitment, k uint64, proof *RotationProof, openKey *OpeningKey) error {
+	z := computeRotationChallenge(commitment, rotatedCommitment, k, domain.Cardinality)
+	shiftedZ := shiftedRotationPoint(domain, z, k)
+
+	// The openings must be at the challenge and agree on the evaluation
+	if !proof.RotatedProof.InputPoint.Equal(&z) || !proof.Proof.InputPoint.Equal(&shiftedZ) {
+		return ErrVerifyOpeningProof
+	}
+	if !proof.Proof.ClaimedValue.Equal(&proof.RotatedProof.ClaimedValue) {
+		return ErrVerifyOpeningProof
+	}
+
+	return BatchVerifyMultiPoints(
+		[]Commitment{*commitment, *rotatedCommitment},
+		[]OpeningProof{proof.Proof, proof.RotatedProof},
+		openKey,
+	)
+}
+
+// computeRotationChallenge derives the evaluation point of a [RotationProof] by hashing the commitments, the rotation
+// and the size of the domain.
+func computeRotationChallenge(commitment, rotatedCommitment *Commitment, k, domainSize uint64) fr.Element {
+	h := sha256.New()
+	h.Write([]byte(DomSepRotation))
+	var sizes [16]byte
+	binary.BigEndian.PutUint64(sizes[:8], domainSize)
+	binary.BigEndian.PutUint64(sizes[8:], k%domainSize)
+	h.Write(sizes[:])
+	serCommitment := commitment.Bytes()
+	h.Write(serCommitment[:])
+	serRotatedCommitment := rotatedCommitment.Bytes()
+	h.Write(serRotatedCommitment[:])
+
+	var challenge fr.Element
+	challenge.SetBytes(h.Sum(nil))
+	return challenge
+}
+
+// shiftedRotationPoint returns w^k * z, where w is the generator of the domain.
+func shiftedRotationPoint(domain *Domain, z fr.Element, k uint64) fr.Element {
+	var shiftedZ fr.Element
+	shiftedZ.Exp(domain.Generator, new(big.Int).SetUint64(k%domain.Cardinality))
+	shiftedZ.Mul(&shiftedZ, &z)
+	return shiftedZ
+}
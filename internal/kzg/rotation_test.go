@@ -0,0 +1,91 @@
+package kzg
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatePolynomial(t *testing.T) {
+	domain := NewDomain(16)
+	p := make(Polynomial, domain.Cardinality)
+	for i := range p {
+		p[i].SetUint64(uint64(i))
+	}
+
+	// In natural order, rotating shifts the evaluations
+	rotated, err := domain.RotatePolynomial(p, 3)
+	require.NoError(t, err)
+	for i := range rotated {
+		require.Equal(t, uint64((i+3)%len(p)), rotated[i].Uint64())
+	}
+
+	// g(X) = f(w^k * X) holds outside of the domain as well, in either order of the roots
+	domain.ReverseRoots()
+	for _, k := range []uint64{0, 1, 5, 16, 21} {
+		rotated, err := domain.RotatePolynomial(p, k)
+		require.NoError(t, err)
+
+		z := randomScalarNotInDomain(t, *domain)
+		var shiftedZ fr.Element
+		shiftedZ.Exp(domain.Generator, new(big.Int).SetUint64(k))
+		shiftedZ.Mul(&shiftedZ, &z)
+		expected, err := domain.EvaluateLagrangePolynomial(p, shiftedZ)
+		require.NoError(t, err)
+		got, err := domain.EvaluateLagrangePolynomial(rotated, z)
+		require.NoError(t, err)
+		require.True(t, expected.Equal(got))
+	}
+
+	_, err = domain.RotatePolynomial(p[1:], 1)
+	require.ErrorIs(t, err, ErrPolynomialMismatchedSizeDomain)
+}
+
+func TestProveVerifyRotation(t *testing.T) {
+	domain := NewDomain(16)
+	domain.ReverseRoots()
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+	srs.CommitKey.ReversePoints()
+
+	p := randPoly(t, *domain)
+	commitment, err := Commit(p, &srs.CommitKey, 0)
+	require.NoError(t, err)
+
+	rotated, rotatedCommitment, proof, err := ProveRotation(domain, p, 5, &srs.CommitKey, 0)
+	require.NoError(t, err)
+	expectedRotated, err := domain.RotatePolynomial(p, 5)
+	require.NoError(t, err)
+	require.Equal(t, expectedRotated, rotated)
+	expectedCommitment, err := Commit(rotated, &srs.CommitKey, 0)
+	require.NoError(t, err)
+	require.True(t, expectedCommitment.Equal(rotatedCommitment))
+
+	require.NoError(t, VerifyRotation(domain, commitment, rotatedCommitment, 5, &proof, &srs.OpeningKey))
+
+	// The proof is for a specific rotation
+	err = VerifyRotation(domain, commitment, rotatedCommitment, 4, &proof, &srs.OpeningKey)
+	require.ErrorIs(t, err, ErrVerifyOpeningProof)
+
+	// A commitment to another polynomial is not a rotation, even with valid openings
+	otherRotated, err := domain.RotatePolynomial(p, 4)
+	require.NoError(t, err)
+	otherCommitment, err := Commit(otherRotated, &srs.CommitKey, 0)
+	require.NoError(t, err)
+	z := computeRotationChallenge(commitment, otherCommitment, 5, domain.Cardinality)
+	forgedProof := proof
+	forgedProof.Proof, err = Open(domain, p, shiftedRotationPoint(domain, z, 5), &srs.CommitKey, 0)
+	require.NoError(t, err)
+	forgedProof.RotatedProof, err = Open(domain, otherRotated, z, &srs.CommitKey, 0)
+	require.NoError(t, err)
+	err = VerifyRotation(domain, commitment, otherCommitment, 5, &forgedProof, &srs.OpeningKey)
+	require.ErrorIs(t, err, ErrVerifyOpeningProof)
+
+	// Proofs with a wrong claimed value fail the pairing check
+	forgedProof = proof
+	forgedProof.Proof.ClaimedValue.SetOne()
+	forgedProof.RotatedProof.ClaimedValue.SetOne()
+	err = VerifyRotation(domain, commitment, rotatedCommitment, 5, &forgedProof, &srs.OpeningKey)
+	require.ErrorIs(t, err, ErrVerifyOpeningProof)
+}
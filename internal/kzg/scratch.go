@@ -0,0 +1,55 @@
+package kzg
+
+import (
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// scratchPool holds the polynomials which are only needed while evaluating a polynomial or computing a quotient, so
+// that they are reused across calls instead of being allocated each time.
+var scratchPool sync.Pool
+
+// getScratch returns a polynomial of n evaluations, all zero, from [scratchPool]. It must be given back with
+// putScratch once it is no longer used, and must not be referenced afterwards. A pointer is returned so that giving
+// it back does not allocate.
+func getScratch(n int) *Polynomial {
+	if p, ok := scratchPool.Get().(*Polynomial); ok && cap(*p) >= n {
+		*p = (*p)[:n]
+		for i := 0; i < n; i++ {
+			(*p)[i].SetZero()
+		}
+		return p
+	}
+	p := make(Polynomial, n)
+	return &p
+}
+
+// putScratch gives a polynomial obtained with getScratch back to [scratchPool].
+func putScratch(p *Polynomial) {
+	scratchPool.Put(p)
+}
+
+// batchInvertInto is [fr.BatchInvert], writing the inverses of a into res instead of allocating them. As with
+// [fr.BatchInvert], the inverse of zero is zero. res must have the same length as a, and must not overlap with it.
+func batchInvertInto(res, a []fr.Element) {
+	accumulator := fr.One()
+	for i := 0; i < len(a); i++ {
+		if a[i].IsZero() {
+			res[i].SetZero()
+			continue
+		}
+		res[i] = accumulator
+		accumulator.Mul(&accumulator, &a[i])
+	}
+
+	accumulator.Inverse(&accumulator)
+
+	for i := len(a) - 1; i >= 0; i-- {
+		if a[i].IsZero() {
+			continue
+		}
+		res[i].Mul(&res[i], &accumulator)
+		accumulator.Mul(&accumulator, &a[i])
+	}
+}
@@ -88,3 +88,31 @@ func newMonomialSRSInsecureUint64(size uint64, bAlpha *big.Int) (*SRS, error) {
 		OpeningKey: openKey,
 	}, nil
 }
+
+// NewMonomialPointsInsecure returns the first `numG1Points` powers of the secret `bAlpha` in G1 and the first
+// `numG2Points` powers in G2, ie the points of a trusted setup in monomial basis, starting with the generators.
+//
+// This method should not be used in production because as the secret is supplied as input.
+func NewMonomialPointsInsecure(numG1Points, numG2Points uint64, bAlpha *big.Int) ([]bls12381.G1Affine, []bls12381.G2Affine, error) {
+	if numG2Points < 2 {
+		return nil, nil, ErrMinSRSSize
+	}
+	srs, err := newMonomialSRSInsecureUint64(numG1Points, bAlpha)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var alpha fr.Element
+	alpha.SetBigInt(bAlpha)
+
+	alphas := make([]fr.Element, numG2Points-1)
+	alphas[0] = alpha
+	for i := 1; i < len(alphas); i++ {
+		alphas[i].Mul(&alphas[i-1], &alpha)
+	}
+	g2Points := make([]bls12381.G2Affine, numG2Points)
+	g2Points[0] = srs.OpeningKey.GenG2
+	copy(g2Points[1:], bls12381.BatchScalarMultiplicationG2(&srs.OpeningKey.GenG2, alphas))
+
+	return srs.CommitKey.G1, g2Points, nil
+}
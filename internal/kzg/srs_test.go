@@ -1,11 +1,15 @@
 package kzg
 
 import (
+	"context"
 	"encoding/hex"
+	"fmt"
 	"math/big"
 	"testing"
 
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/crate-crypto/go-kzg-4844/internal/multiexp"
 	"github.com/stretchr/testify/require"
 )
 
@@ -43,3 +47,123 @@ func TestCommitRegression(t *testing.T) {
 	expectedCommitment := "85bdf872da5b8561d23055d32db3fc86c672b0be7543b8c1e48634af07231bf7ab6385b765750921017cbcdbcd14f8e0"
 	require.Equal(t, expectedCommitment, gotCommitment)
 }
+
+func TestCommitBatch(t *testing.T) {
+	domain := NewDomain(16)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(100))
+
+	// Small batches commit with the key as-is, and large ones with temporary tables
+	for _, batchSize := range []int{0, 1, 3, minBatchSizeForTables} {
+		polys := make([]Polynomial, batchSize)
+		for i := range polys {
+			polys[i] = randPoly(t, *domain)
+			if i%2 == 1 {
+				polys[i] = polys[i][:5]
+			}
+		}
+
+		for _, numGoRoutines := range []int{0, 1, 4} {
+			commitments, err := CommitBatch(polys, &srs.CommitKey, numGoRoutines)
+			require.NoError(t, err)
+			require.Len(t, commitments, batchSize)
+			for i := range polys {
+				expected, err := Commit(polys[i], &srs.CommitKey, 0)
+				require.NoError(t, err)
+				require.True(t, expected.Equal(&commitments[i]))
+			}
+		}
+	}
+	require.Nil(t, srs.CommitKey.precomputed)
+
+	polys := []Polynomial{randPoly(t, *domain), make(Polynomial, 17)}
+	_, err := CommitBatch(polys, &srs.CommitKey, 0)
+	require.ErrorIs(t, err, ErrInvalidPolynomialSize)
+	require.ErrorContains(t, err, "polynomial 1")
+}
+
+func TestCommitBatchContext(t *testing.T) {
+	domain := NewDomain(16)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(100))
+	polys := make([]Polynomial, 5)
+	for i := range polys {
+		polys[i] = randPoly(t, *domain)
+	}
+
+	// The backend cancels the context during the first multi exponentiation, so no other one is started
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	backend := &cancellingBackend{cancel: cancel}
+	ck := CommitKey{G1: srs.CommitKey.G1}
+	ck.SetMultiExpBackend(backend)
+	_, err := CommitBatchContext(ctx, polys, &ck, 1)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 1, backend.numCalls)
+
+	_, err = CommitBatchContext(ctx, polys, &srs.CommitKey, 4)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+// cancellingBackend computes multi exponentiations with [multiexp.MultiExp], and cancels a context on the first one.
+type cancellingBackend struct {
+	cancel   context.CancelFunc
+	numCalls int
+}
+
+func (b *cancellingBackend) MultiExp(points []bls12381.G1Affine, scalars []fr.Element, numGoRoutines int) (*bls12381.G1Affine, error) {
+	b.numCalls++
+	b.cancel()
+	return multiexp.MultiExp(scalars, points, numGoRoutines)
+}
+
+func TestCommitLarge(t *testing.T) {
+	domain := NewDomain(64)
+	srs, _ := newLagrangeSRSInsecure(*domain, big.NewInt(100))
+
+	// A vector of several chunks, the last of which is shorter
+	p := randPoly(t, *domain)[:50]
+	expected, err := Commit(p, &srs.CommitKey, 0)
+	require.NoError(t, err)
+	for _, chunkSize := range []int{0, 1, 7, 16, 50, 64} {
+		for _, numGoRoutines := range []int{0, 1, 4} {
+			commitment, err := CommitLarge(p, &srs.CommitKey, chunkSize, numGoRoutines)
+			require.NoError(t, err)
+			require.True(t, expected.Equal(commitment))
+		}
+	}
+
+	_, err = CommitLarge(make(Polynomial, 65), &srs.CommitKey, 16, 0)
+	require.ErrorIs(t, err, ErrInvalidPolynomialSize)
+	_, err = CommitLarge(nil, &srs.CommitKey, 16, 0)
+	require.ErrorIs(t, err, ErrInvalidPolynomialSize)
+}
+
+func BenchmarkCommitBatch(b *testing.B) {
+	domain := NewDomain(4096)
+	srs, err := newLagrangeSRSInsecure(*domain, big.NewInt(1234))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, batchSize := range []int{8, 64} {
+		polys := make([]Polynomial, batchSize)
+		for i := range polys {
+			polys[i] = make(Polynomial, domain.Cardinality)
+			for j := range polys[i] {
+				_, _ = polys[i][j].SetRandom()
+			}
+		}
+
+		b.Run(fmt.Sprintf("loop/%d", batchSize), func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				for i := range polys {
+					_, _ = Commit(polys[i], &srs.CommitKey, 0)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("CommitBatch/%d", batchSize), func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				_, _ = CommitBatch(polys, &srs.CommitKey, 0)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package kzg
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// ProveVanishingOnSet proves that the polynomial f(X), given by its coefficients starting with the constant term,
+// evaluates to zero at every one of `points`. The proof is the commitment, with ck which must hold the monomial SRS,
+// to the quotient q(X) = f(X) / Z_S(X), where Z_S(X) is the polynomial vanishing at all of the points. It is checked
+// by [VerifyVanishingOnSet].
+//
+// This generalizes the opening proof at a single point with a claimed value of zero to a set of points. An empty set
+// of points gives the commitment to f(X) itself, and the zero polynomial vanishes everywhere, so its proof is the
+// point at infinity.
+//
+// Returns an error wrapping [ErrInvalidPolynomialSize] if there are no coefficients or more than len(ck.G1), an error
+// wrapping [ErrDuplicateInterpolationPoint] if two of the points are equal, and an error wrapping
+// [ErrPolynomialDoesNotVanish] with the index of the first point at which f(X) is not zero.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+func ProveVanishingOnSet(coeffs []fr.Element, points []fr.Element, ck *CommitKey, numGoRoutines int) (Commitment, error) {
+	if err := CheckPolynomialSize(coeffs, ck); err != nil {
+		return Commitment{}, err
+	}
+	if err := checkDistinctPoints(points); err != nil {
+		return Commitment{}, err
+	}
+
+	// Dividing by each X - s in turn divides by Z_S(X), and each remainder is the evaluation of the previous quotient
+	// at s, which is zero for every s exactly when f(X) vanishes on the set since the points are distinct. The
+	// divisions are done in place, in a copy of the coefficients which is only committed to.
+	quotientScratch := getScratch(len(coeffs))
+	defer putScratch(quotientScratch)
+	quotient := *quotientScratch
+	copy(quotient, coeffs)
+	for i := 0; i < len(points) && len(quotient) > 0; i++ {
+		remainder := dividePolyByXminusAMonomialInto(quotient, quotient, points[i])
+		if !remainder.IsZero() {
+			return Commitment{}, fmt.Errorf("%w: point %d", ErrPolynomialDoesNotVanish, i)
+		}
+		quotient = quotient[:len(quotient)-1]
+	}
+	if len(quotient) == 0 {
+		return Commitment{}, nil
+	}
+
+	commitment, err := Commit(quotient, ck, numGoRoutines)
+	if err != nil {
+		return Commitment{}, err
+	}
+	return *commitment, nil
+}
+
+// VerifyVanishingOnSet checks a proof from [ProveVanishingOnSet] that the polynomial committed to by `commitment`
+// evaluates to zero at every one of `points`, with the pairing check:
+//
+//	e(C, G2) = e(Q, [Z_S(s)]G2)
+//
+// [Z_S(s)]G2 is computed from srsG2, the G2 points of the monomial SRS starting with the generator, and so there must
+// be more of them than points; otherwise an error wrapping [ErrMinSRSSize] is returned. Returns an error wrapping
+// [ErrDuplicateInterpolationPoint] if two of the points are equal, and [ErrVerifyOpeningProof] if the proof is
+// incorrect.
+func VerifyVanishingOnSet(commitment, proof *Commitment, points []fr.Element, srsG2 []bls12381.G2Affine) error {
+	if len(srsG2) <= len(points) {
+		return fmt.Errorf("%w: got %d G2 points for %d points", ErrMinSRSSize, len(srsG2), len(points))
+	}
+	if err := checkDistinctPoints(points); err != nil {
+		return err
+	}
+
+	// 1. Commit to the vanishing polynomial in G2
+	//
+	vanishingPoly := vanishingPolyCoeff(points)
+	var vanishingG2 bls12381.G2Affine
+	if _, err := vanishingG2.MultiExp(srsG2[:len(vanishingPoly)], vanishingPoly, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+
+	// 2. Check e(C, G2) * e(-Q, [Z_S(s)]G2) = 1
+	//
+	var negProof bls12381.G1Affine
+	negProof.Neg(proof)
+	check, err := bls12381.PairingCheck(
+		[]bls12381.G1Affine{*commitment, negProof},
+		[]bls12381.G2Affine{srsG2[0], vanishingG2},
+	)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// checkDistinctPoints returns an error wrapping [ErrDuplicateInterpolationPoint] if two of the points are equal.
+func checkDistinctPoints(points []fr.Element) error {
+	seen := make(map[fr.Element]int, len(points))
+	for i := 0; i < len(points); i++ {
+		if j, ok := seen[points[i]]; ok {
+			return fmt.Errorf("%w: points %d and %d", ErrDuplicateInterpolationPoint, j, i)
+		}
+		seen[points[i]] = i
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package kzg
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProveVanishingOnSet(t *testing.T) {
+	const numG1Points, numG2Points = 64, 9
+	g1Points, g2Points, err := NewMonomialPointsInsecure(numG1Points, numG2Points, big.NewInt(1234))
+	require.NoError(t, err)
+	ck := &CommitKey{G1: g1Points}
+
+	for _, numPoints := range []int{0, 1, 5, numG2Points - 1} {
+		points := make([]fr.Element, numPoints)
+		for i := range points {
+			_, _ = points[i].SetRandom()
+		}
+
+		// f(X) = Z_S(X) * g(X) vanishes on the points
+		g := make([]fr.Element, 20)
+		for i := range g {
+			_, _ = g[i].SetRandom()
+		}
+		coeffs := multiplyMonomial(vanishingPolyCoeff(points), g)
+		commitment, err := Commit(coeffs, ck, 0)
+		require.NoError(t, err)
+
+		proof, err := ProveVanishingOnSet(coeffs, points, ck, 0)
+		require.NoError(t, err)
+		expected, err := Commit(g, ck, 0)
+		require.NoError(t, err)
+		require.Equal(t, *expected, proof)
+		require.NoError(t, VerifyVanishingOnSet(commitment, &proof, points, g2Points))
+
+		// The proof is for this set of points, and this commitment
+		if numPoints > 0 {
+			otherPoints := append([]fr.Element(nil), points...)
+			otherPoints[0].SetUint64(42)
+			require.ErrorIs(t, VerifyVanishingOnSet(commitment, &proof, otherPoints, g2Points), ErrVerifyOpeningProof)
+			require.ErrorIs(t, VerifyVanishingOnSet(commitment, &proof, points[1:], g2Points), ErrVerifyOpeningProof)
+			require.ErrorIs(t, VerifyVanishingOnSet(commitment, commitment, points, g2Points), ErrVerifyOpeningProof)
+		}
+	}
+
+	// A polynomial which is not zero at one of the points cannot be proven
+	points := []fr.Element{fr.NewElement(1), fr.NewElement(2), fr.NewElement(3)}
+	coeffs := multiplyMonomial(vanishingPolyCoeff(points[:2]), []fr.Element{fr.NewElement(5), fr.NewElement(7)})
+	_, err = ProveVanishingOnSet(coeffs, points, ck, 0)
+	require.ErrorIs(t, err, ErrPolynomialDoesNotVanish)
+	require.ErrorContains(t, err, "point 2")
+
+	// The zero polynomial vanishes everywhere
+	proof, err := ProveVanishingOnSet(make([]fr.Element, 2), points, ck, 0)
+	require.NoError(t, err)
+	require.True(t, proof.IsInfinity())
+
+	_, err = ProveVanishingOnSet(coeffs, []fr.Element{points[0], points[0]}, ck, 0)
+	require.ErrorIs(t, err, ErrDuplicateInterpolationPoint)
+	_, err = ProveVanishingOnSet(nil, points, ck, 0)
+	require.ErrorIs(t, err, ErrInvalidPolynomialSize)
+	commitment, err := Commit(coeffs, ck, 0)
+	require.NoError(t, err)
+	require.ErrorIs(t, VerifyVanishingOnSet(commitment, commitment, make([]fr.Element, numG2Points), g2Points), ErrMinSRSSize)
+}
+
+// multiplyMonomial returns the coefficients of the product of the polynomials, given by their coefficients starting
+// with the constant term.
+func multiplyMonomial(a, b []fr.Element) []fr.Element {
+	product := make([]fr.Element, len(a)+len(b)-1)
+	for i := range a {
+		for j := range b {
+			var tmp fr.Element
+			tmp.Mul(&a[i], &b[j])
+			product[i+j].Add(&product[i+j], &tmp)
+		}
+	}
+	return product
+}
@@ -2,4 +2,8 @@ package multiexp
 
 import "errors"
 
-var ErrTooManyGoRoutines = errors.New("cannot configure more than 1024 go routines")
+var (
+	ErrTooManyGoRoutines = errors.New("cannot configure more than 1024 go routines")
+	ErrTooManyScalars    = errors.New("there are more scalars than points in the precomputed table")
+	ErrMismatchedLengths = errors.New("the number of points and scalars must be the same")
+)
@@ -25,6 +25,25 @@ func MultiExp(scalars []fr.Element, points []bls12381.G1Affine, numGoRoutines in
 	return new(bls12381.G1Affine).MultiExp(points, scalars, ecc.MultiExpConfig{NbTasks: numGoRoutines})
 }
 
+// Backend computes multi exponentiations in G1, so that an implementation other than the one from gnark-crypto, such
+// as one running on a GPU, can be used.
+//
+// MultiExp must return scalars[0]*points[0] + ... + scalars[n-1]*points[n-1] and return an error if the slices differ
+// in length. numGoRoutines is the amount of concurrency requested by the caller, where a negative number or 0 means
+// the number of CPUs; implementations which do not run on the CPU may ignore it.
+type Backend interface {
+	MultiExp(points []bls12381.G1Affine, scalars []fr.Element, numGoRoutines int) (*bls12381.G1Affine, error)
+}
+
+// MultiExpWithBackend computes the multi exponentiation using the given backend, or using [MultiExp] if the backend
+// is nil.
+func MultiExpWithBackend(backend Backend, scalars []fr.Element, points []bls12381.G1Affine, numGoRoutines int) (*bls12381.G1Affine, error) {
+	if backend == nil {
+		return MultiExp(scalars, points, numGoRoutines)
+	}
+	return backend.MultiExp(points, scalars, numGoRoutines)
+}
+
 // isValidNumGoRoutines will return an error if the number
 // of go routines to be used is not Valid.
 //
@@ -2,6 +2,7 @@ package multiexp
 
 import (
 	"errors"
+	"fmt"
 	"math/big"
 	"testing"
 
@@ -131,3 +132,67 @@ func genG1Points(n uint) []bls12381.G1Affine {
 	}
 	return points
 }
+
+func TestSequentialMultiExp(t *testing.T) {
+	var base fr.Element
+	base.SetInt64(7654321)
+
+	for _, instanceSize := range []uint{0, 1, 2, 5, 64, 300} {
+		powers := utils.ComputePowers(base, instanceSize)
+		points := genG1Points(instanceSize)
+
+		got, err := Sequential{}.MultiExp(points, powers, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, err := MultiExp(powers, points, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(expected) {
+			t.Errorf("inconsistent multi-exp result for %d points", instanceSize)
+		}
+	}
+
+	// Scalars with all of their bits set in the top window
+	scalars := make([]fr.Element, 3)
+	for i := range scalars {
+		scalars[i].SetInt64(-int64(i + 1))
+	}
+	points := genG1Points(3)
+	got, err := Sequential{}.MultiExp(points, scalars, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := slowMultiExp(scalars, points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(expected) {
+		t.Error("inconsistent multi-exp result")
+	}
+
+	_, err = Sequential{}.MultiExp(points, scalars[1:], 0)
+	if !errors.Is(err, ErrMismatchedLengths) {
+		t.Error("number of points != number of scalars. Should produce an error")
+	}
+}
+
+func BenchmarkMultiExpNumGoRoutines(b *testing.B) {
+	const numPoints = 4096
+	points := genG1Points(numPoints)
+	scalars := make([]fr.Element, numPoints)
+	for i := 0; i < numPoints; i++ {
+		_, _ = scalars[i].SetRandom()
+	}
+
+	// numGoRoutines is passed to gnark-crypto as NbTasks, so on a machine with several CPUs the time should decrease
+	// as it increases, up to the number of CPUs
+	for _, numGoRoutines := range []int{1, 2, 4, 8, 0} {
+		b.Run(fmt.Sprintf("numGoRoutines=%d", numGoRoutines), func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				_, _ = MultiExp(scalars, points, numGoRoutines)
+			}
+		})
+	}
+}
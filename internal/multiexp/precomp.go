@@ -0,0 +1,340 @@
+package multiexp
+
+import (
+	"runtime"
+	"sync"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// DefaultWindowSize is the window size used for precomputed tables of 4096 points.
+//
+// Each point is expanded into ceil(256/DefaultWindowSize) affine points, which is 22 points or 2112 bytes per point.
+const DefaultWindowSize = 12
+
+// PrecomputedTable holds multiples of a fixed set of points, which allows multi exponentiations against those points
+// to skip all of the doublings of the Pippenger algorithm.
+//
+// Each scalar is split into numWindows signed digits of windowSize bits, so that
+// s*P = sum_j d_j * (2^(j*windowSize) * P). Since the points 2^(j*windowSize) * P are precomputed, the multi
+// exponentiation becomes a single bucket accumulation over numPoints*numWindows points.
+type PrecomputedTable struct {
+	windowSize int
+	numWindows int
+	numPoints  int
+
+	// points[i*numWindows+j] is 2^(j*windowSize) times the i'th point.
+	points []bls12381.G1Affine
+}
+
+// NewPrecomputedTable computes the table for the given points and window size. The window size must be between 1 and
+// 16.
+//
+// The table holds len(points)*ceil(256/windowSize) affine points, which take 96 bytes each.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+func NewPrecomputedTable(points []bls12381.G1Affine, windowSize int, numGoRoutines int) *PrecomputedTable {
+	if windowSize < 1 || windowSize > 16 {
+		// This is a library bug and so we panic.
+		panic("window size must be between 1 and 16")
+	}
+	// One more bit than the scalar field, so that the carry of the last signed digit always fits
+	numWindows := (fr.Bits + windowSize) / windowSize
+	numPoints := len(points)
+
+	if numGoRoutines <= 0 {
+		numGoRoutines = runtime.NumCPU()
+	}
+	table := make([]bls12381.G1Jac, numPoints*numWindows)
+	parallelize(numPoints, numGoRoutines, func(_, start, end int) {
+		for i := start; i < end; i++ {
+			row := table[i*numWindows : (i+1)*numWindows]
+			row[0].FromAffine(&points[i])
+			for j := 1; j < numWindows; j++ {
+				row[j].Set(&row[j-1])
+				for k := 0; k < windowSize; k++ {
+					row[j].DoubleAssign()
+				}
+			}
+		}
+	})
+
+	return &PrecomputedTable{
+		windowSize: windowSize,
+		numWindows: numWindows,
+		numPoints:  numPoints,
+		points:     bls12381.BatchJacobianToAffineG1(table),
+	}
+}
+
+// NumPoints returns the number of points that the table was computed for.
+func (t *PrecomputedTable) NumPoints() int {
+	return t.numPoints
+}
+
+// MultiExp computes scalars[0]*points[0] + ... + scalars[n-1]*points[n-1], where n is the number of scalars and
+// points are those that the table was computed for. There must not be more scalars than points.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+//
+// Returns an error if the numGoRoutines exceeds 1024.
+func (t *PrecomputedTable) MultiExp(scalars []fr.Element, numGoRoutines int) (*bls12381.G1Affine, error) {
+	if err := isValidNumGoRoutines(numGoRoutines); err != nil {
+		return nil, err
+	}
+	if len(scalars) > t.numPoints {
+		return nil, ErrTooManyScalars
+	}
+	if numGoRoutines <= 0 {
+		numGoRoutines = runtime.NumCPU()
+	}
+
+	// Each go-routine accumulates a chunk of the scalars in its own buckets
+	partialResults := make([]bls12381.G1Jac, numGoRoutines)
+	parallelize(len(scalars), numGoRoutines, func(chunk, start, end int) {
+		partialResults[chunk] = t.accumulate(scalars[start:end], start)
+	})
+
+	var result bls12381.G1Jac
+	for i := 0; i < numGoRoutines; i++ {
+		result.AddAssign(&partialResults[i])
+	}
+	return new(bls12381.G1Affine).FromJacobian(&result), nil
+}
+
+// accumulate computes the multi exponentiation of the scalars against the points of the table starting at
+// firstPoint.
+func (t *PrecomputedTable) accumulate(scalars []fr.Element, firstPoint int) bls12381.G1Jac {
+	// The bucket with index d-1 holds the sum of the points with digit d,
+	// and of the negated points with digit -d.
+	buckets := newBucketAccumulator(1 << (t.windowSize - 1))
+	mask := uint64(1)<<t.windowSize - 1
+	half := uint64(1) << (t.windowSize - 1)
+
+	for i := 0; i < len(scalars); i++ {
+		scalar := scalars[i].Bits()
+		row := t.points[(firstPoint+i)*t.numWindows : (firstPoint+i+1)*t.numWindows]
+
+		carry := uint64(0)
+		for j := 0; j < t.numWindows; j++ {
+			// Extract the j'th window of the scalar, which may straddle two limbs
+			bitOffset := j * t.windowSize
+			limb, shift := bitOffset/64, uint(bitOffset%64)
+			var digit uint64
+			if limb < len(scalar) {
+				digit = scalar[limb] >> shift
+				if shift+uint(t.windowSize) > 64 && limb+1 < len(scalar) {
+					digit |= scalar[limb+1] << (64 - shift)
+				}
+			}
+			digit = (digit & mask) + carry
+
+			// Use signed digits in [-2^(windowSize-1), 2^(windowSize-1)], which halves the number of buckets
+			carry = 0
+			if digit > half {
+				carry = 1
+				digit = (mask + 1) - digit
+				if digit != 0 {
+					var neg bls12381.G1Affine
+					neg.Neg(&row[j])
+					buckets.add(int(digit-1), &neg)
+				}
+			} else if digit != 0 {
+				buckets.add(int(digit-1), &row[j])
+			}
+		}
+	}
+
+	// sum_d d*bucket[d-1] is computed with running sums
+	buckets.finish()
+	var runningSum, result bls12381.G1Jac
+	for d := len(buckets.points) - 1; d >= 0; d-- {
+		if buckets.filled[d] {
+			runningSum.AddMixed(&buckets.points[d])
+		}
+		runningSum.AddAssign(&buckets.overflow[d])
+		result.AddAssign(&runningSum)
+	}
+	return result
+}
+
+// maxBucketBatchSize is the maximum number of additions into distinct buckets which are done together, sharing one
+// field inversion.
+const maxBucketBatchSize = 256
+
+// bucketAccumulator adds points into buckets using affine additions. The additions are batched so that the inversions
+// they need can be done at once, which makes them about twice as fast as additions in Jacobian coordinates.
+//
+// Additions which conflict with the batch are retried in the next one. Since the scalars may be chosen so that most
+// digits are equal, the number of retries is bounded, and the additions beyond that are done in Jacobian coordinates
+// into overflow buckets instead.
+type bucketAccumulator struct {
+	points   []bls12381.G1Affine
+	filled   []bool
+	overflow []bls12381.G1Jac
+
+	batchSize int
+
+	// batch holds the additions which will be done in the next flush, for distinct buckets
+	batch   []bucketAddition
+	inBatch []bool
+	// pending holds the additions into buckets which were already in the batch
+	pending []bucketAddition
+	spare   []bucketAddition
+
+	// scratch space for the batch inversion
+	denominators []fp.Element
+	prefixes     []fp.Element
+}
+
+type bucketAddition struct {
+	bucket int
+	point  bls12381.G1Affine
+}
+
+func newBucketAccumulator(numBuckets int) *bucketAccumulator {
+	// With too few buckets, the batch would rarely fill up with distinct buckets
+	batchSize := numBuckets / 4
+	if batchSize > maxBucketBatchSize {
+		batchSize = maxBucketBatchSize
+	}
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	return &bucketAccumulator{
+		points:    make([]bls12381.G1Affine, numBuckets),
+		filled:    make([]bool, numBuckets),
+		overflow:  make([]bls12381.G1Jac, numBuckets),
+		batchSize: batchSize,
+		inBatch:   make([]bool, numBuckets),
+	}
+}
+
+// add adds the point p into the given bucket.
+func (acc *bucketAccumulator) add(bucket int, p *bls12381.G1Affine) {
+	acc.schedule(bucket, p)
+	for len(acc.batch) >= acc.batchSize {
+		acc.flush()
+		acc.reschedulePending()
+	}
+}
+
+// finish does all of the scheduled additions.
+func (acc *bucketAccumulator) finish() {
+	for len(acc.batch) != 0 {
+		acc.flush()
+		acc.reschedulePending()
+	}
+}
+
+func (acc *bucketAccumulator) schedule(bucket int, p *bls12381.G1Affine) {
+	switch {
+	case !acc.filled[bucket]:
+		acc.points[bucket] = *p
+		acc.filled[bucket] = true
+	case acc.inBatch[bucket] && len(acc.pending) < acc.batchSize:
+		acc.pending = append(acc.pending, bucketAddition{bucket, *p})
+	case acc.inBatch[bucket]:
+		acc.overflow[bucket].AddMixed(p)
+	default:
+		acc.batch = append(acc.batch, bucketAddition{bucket, *p})
+		acc.inBatch[bucket] = true
+	}
+}
+
+// reschedulePending moves the pending additions into the (empty) batch, where they may conflict again.
+func (acc *bucketAccumulator) reschedulePending() {
+	pending := acc.pending
+	acc.pending = acc.spare[:0]
+	for i := 0; i < len(pending); i++ {
+		acc.schedule(pending[i].bucket, &pending[i].point)
+	}
+	acc.spare = pending[:0]
+}
+
+// flush does all of the additions in the batch.
+func (acc *bucketAccumulator) flush() {
+	n := len(acc.batch)
+	if cap(acc.denominators) < n {
+		acc.denominators = make([]fp.Element, n)
+		acc.prefixes = make([]fp.Element, n)
+	}
+	denominators, prefixes := acc.denominators[:n], acc.prefixes[:n]
+
+	// The slope of the line through P and B needs 1/(x_P - x_B). When x_P = x_B, the
+	// points are equal or opposite, and the addition is done in Jacobian coordinates.
+	isExceptional := func(i int) bool {
+		return acc.batch[i].point.X.Equal(&acc.points[acc.batch[i].bucket].X)
+	}
+
+	// Invert all of the denominators with a single inversion
+	accumulator := fp.One()
+	for i := 0; i < n; i++ {
+		addition := &acc.batch[i]
+		denominators[i].Sub(&addition.point.X, &acc.points[addition.bucket].X)
+		if denominators[i].IsZero() {
+			denominators[i].SetOne()
+		}
+		prefixes[i] = accumulator
+		accumulator.Mul(&accumulator, &denominators[i])
+	}
+	accumulator.Inverse(&accumulator)
+	for i := n - 1; i >= 0; i-- {
+		var inverse fp.Element
+		inverse.Mul(&accumulator, &prefixes[i])
+		accumulator.Mul(&accumulator, &denominators[i])
+		denominators[i] = inverse
+	}
+
+	for i := 0; i < n; i++ {
+		addition := &acc.batch[i]
+		bucket := &acc.points[addition.bucket]
+		acc.inBatch[addition.bucket] = false
+
+		if isExceptional(i) {
+			var sum bls12381.G1Jac
+			sum.FromAffine(bucket)
+			sum.AddMixed(&addition.point)
+			bucket.FromJacobian(&sum)
+			acc.filled[addition.bucket] = !bucket.IsInfinity()
+			continue
+		}
+
+		// lambda = (y_P - y_B) / (x_P - x_B)
+		// x = lambda^2 - x_B - x_P
+		// y = lambda * (x_B - x) - y_B
+		var lambda, x, y fp.Element
+		lambda.Sub(&addition.point.Y, &bucket.Y).Mul(&lambda, &denominators[i])
+		x.Square(&lambda).Sub(&x, &bucket.X).Sub(&x, &addition.point.X)
+		y.Sub(&bucket.X, &x).Mul(&y, &lambda).Sub(&y, &bucket.Y)
+		bucket.X = x
+		bucket.Y = y
+	}
+	acc.batch = acc.batch[:0]
+}
+
+// parallelize splits [0, n) into at most numGoRoutines contiguous chunks and calls work on each of them concurrently,
+// along with the index of the chunk.
+func parallelize(n, numGoRoutines int, work func(chunk, start, end int)) {
+	if n == 0 {
+		return
+	}
+	if numGoRoutines > n {
+		numGoRoutines = n
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(numGoRoutines)
+	for i := 0; i < numGoRoutines; i++ {
+		go func(i int) {
+			defer wg.Done()
+			work(i, i*n/numGoRoutines, (i+1)*n/numGoRoutines)
+		}(i)
+	}
+	wg.Wait()
+}
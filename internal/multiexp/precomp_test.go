@@ -0,0 +1,84 @@
+package multiexp
+
+import (
+	"fmt"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+func TestPrecomputedTableMultiExp(t *testing.T) {
+	points := genG1Points(100)
+	randomScalars := make([]fr.Element, len(points))
+	for i := 0; i < len(randomScalars); i++ {
+		_, _ = randomScalars[i].SetRandom()
+	}
+	// Scalars whose signed digits all carry
+	randomScalars[0].SetInt64(-1)
+	randomScalars[1].SetZero()
+
+	// Equal scalars put all of the points of a window into the same bucket
+	equalScalars := make([]fr.Element, len(points))
+	for i := 0; i < len(equalScalars); i++ {
+		equalScalars[i].SetUint64(0x0123456789abcdef)
+	}
+
+	for _, scalars := range [][]fr.Element{randomScalars, equalScalars} {
+		testPrecomputedTableMultiExp(t, points, scalars)
+	}
+}
+
+func testPrecomputedTableMultiExp(t *testing.T, points []bls12381.G1Affine, scalars []fr.Element) {
+	t.Helper()
+
+	for _, windowSize := range []int{1, 5, 8, DefaultWindowSize, 16} {
+		table := NewPrecomputedTable(points, windowSize, 0)
+		for _, n := range []int{0, 1, 37, len(points)} {
+			for _, numGoRoutines := range []int{-1, 1, 3} {
+				expected, err := slowMultiExp(scalars[:n], points[:n])
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, err := table.MultiExp(scalars[:n], numGoRoutines)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !got.Equal(expected) {
+					t.Fatalf("inconsistent multi-exp result for window size %d and %d scalars", windowSize, n)
+				}
+			}
+		}
+	}
+}
+
+func TestPrecomputedTableTooManyScalars(t *testing.T) {
+	table := NewPrecomputedTable(genG1Points(4), DefaultWindowSize, 0)
+	_, err := table.MultiExp(make([]fr.Element, 5), 0)
+	if err != ErrTooManyScalars {
+		t.Fatalf("expected ErrTooManyScalars, got %v", err)
+	}
+}
+
+func BenchmarkPrecomputedTableMultiExp(b *testing.B) {
+	const numPoints = 4096
+	points := genG1Points(numPoints)
+	scalars := make([]fr.Element, numPoints)
+	for i := 0; i < numPoints; i++ {
+		_, _ = scalars[i].SetRandom()
+	}
+
+	b.Run("gnark", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			_, _ = MultiExp(scalars, points, 0)
+		}
+	})
+	for _, windowSize := range []int{10, DefaultWindowSize, 14} {
+		table := NewPrecomputedTable(points, windowSize, 0)
+		b.Run(fmt.Sprintf("precomputed/window=%d", windowSize), func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				_, _ = table.MultiExp(scalars, 0)
+			}
+		})
+	}
+}
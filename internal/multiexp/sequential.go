@@ -0,0 +1,74 @@
+package multiexp
+
+import (
+	"math/bits"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// Sequential is a [Backend] which computes multi exponentiations on the calling go-routine, without spawning any
+// others, whatever the requested amount of concurrency. This is for environments where go-routines are undesirable,
+// such as WebAssembly in a browser. It uses the bucket method, and is slower than the gnark-crypto implementation.
+type Sequential struct{}
+
+// MultiExp implements [Backend]. numGoRoutines is ignored.
+func (Sequential) MultiExp(points []bls12381.G1Affine, scalars []fr.Element, _ int) (*bls12381.G1Affine, error) {
+	if len(points) != len(scalars) {
+		return nil, ErrMismatchedLengths
+	}
+
+	// The window size grows with the number of points, so that the cost of summing the buckets stays below the cost
+	// of filling them.
+	windowSize := bits.Len(uint(len(points))) - 2
+	if windowSize < 2 {
+		windowSize = 2
+	} else if windowSize > 16 {
+		windowSize = 16
+	}
+	numWindows := (fr.Bits + windowSize - 1) / windowSize
+
+	scalarBits := make([][fr.Limbs]uint64, len(scalars))
+	for i := 0; i < len(scalars); i++ {
+		scalarBits[i] = scalars[i].Bits()
+	}
+
+	// The zero value of a point in Jacobian coordinates is the point at infinity
+	var result bls12381.G1Jac
+	buckets := make([]bls12381.G1Jac, (1<<windowSize)-1)
+	for w := numWindows - 1; w >= 0; w-- {
+		for i := 0; i < windowSize; i++ {
+			result.DoubleAssign()
+		}
+
+		for i := range buckets {
+			buckets[i] = bls12381.G1Jac{}
+		}
+		for i := 0; i < len(points); i++ {
+			digit := windowDigit(&scalarBits[i], w*windowSize, windowSize)
+			if digit != 0 {
+				buckets[digit-1].AddMixed(&points[i])
+			}
+		}
+
+		// sum_d d * buckets[d-1], computed as a sum of running sums
+		var runningSum, windowSum bls12381.G1Jac
+		for i := len(buckets) - 1; i >= 0; i-- {
+			runningSum.AddAssign(&buckets[i])
+			windowSum.AddAssign(&runningSum)
+		}
+		result.AddAssign(&windowSum)
+	}
+
+	return new(bls12381.G1Affine).FromJacobian(&result), nil
+}
+
+// windowDigit returns the windowSize bits of the scalar, given as little-endian limbs, starting at the given bit.
+func windowDigit(scalarBits *[fr.Limbs]uint64, start, windowSize int) uint64 {
+	limb, shift := start/64, uint(start%64)
+	digit := scalarBits[limb] >> shift
+	if int(shift)+windowSize > 64 && limb+1 < fr.Limbs {
+		digit |= scalarBits[limb+1] << (64 - shift)
+	}
+	return digit & ((1 << windowSize) - 1)
+}
@@ -1,6 +1,11 @@
 package utils
 
 import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"math/bits"
+
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 )
 
@@ -48,3 +53,61 @@ func ReduceCanonicalBigEndian(serScalar []byte) (fr.Element, error) {
 
 	return scalar, err
 }
+
+// ReduceCanonicalBigEndianConstantTime is a version of ReduceCanonicalBigEndian whose running time does not depend on
+// the value of the scalar, other than on whether it is canonical.
+//
+// The comparison with the modulus is done by computing the borrow of a subtraction instead of comparing limb by limb,
+// and the conversion to Montgomery form is a single multiplication, which the gnark-crypto assembly on amd64 does
+// without branching on its inputs.
+func ReduceCanonicalBigEndianConstantTime(serScalar []byte) (fr.Element, error) {
+	if len(serScalar) != fr.Bytes {
+		return fr.Element{}, errNonCanonicalScalar
+	}
+
+	var regular fr.Element
+	regular[0] = binary.BigEndian.Uint64(serScalar[24:32])
+	regular[1] = binary.BigEndian.Uint64(serScalar[16:24])
+	regular[2] = binary.BigEndian.Uint64(serScalar[8:16])
+	regular[3] = binary.BigEndian.Uint64(serScalar[0:8])
+
+	// The scalar is canonical if and only if subtracting the modulus borrows
+	var borrow uint64
+	_, borrow = bits.Sub64(regular[0], modulusLimbs[0], 0)
+	_, borrow = bits.Sub64(regular[1], modulusLimbs[1], borrow)
+	_, borrow = bits.Sub64(regular[2], modulusLimbs[2], borrow)
+	_, borrow = bits.Sub64(regular[3], modulusLimbs[3], borrow)
+
+	// Multiplying by R^2 and reducing converts to Montgomery form
+	var scalar fr.Element
+	scalar.Mul(&regular, &rSquare)
+
+	if borrow == 0 {
+		return fr.Element{}, errNonCanonicalScalar
+	}
+	return scalar, nil
+}
+
+var errNonCanonicalScalar = errors.New("invalid fr.Element encoding")
+
+// modulusLimbs holds the modulus as little-endian 64-bit limbs, and rSquare is the element whose
+// Montgomery representation is R^2 mod q, where R = 2^256.
+var modulusLimbs, rSquare = montgomeryConstants()
+
+func montgomeryConstants() ([4]uint64, fr.Element) {
+	var modulusBytes [fr.Bytes]byte
+	modulus := fr.Modulus()
+	modulus.FillBytes(modulusBytes[:])
+	var limbs [4]uint64
+	for i := 0; i < 4; i++ {
+		limbs[i] = binary.BigEndian.Uint64(modulusBytes[fr.Bytes-8*(i+1) : fr.Bytes-8*i])
+	}
+
+	// The Montgomery representation of R mod q is R^2 mod q
+	r := new(big.Int).Lsh(big.NewInt(1), 256)
+	r.Mod(r, modulus)
+	var rSquare fr.Element
+	rSquare.SetBigInt(r)
+
+	return limbs, rSquare
+}
@@ -118,6 +118,43 @@ func TestCanonicalEncoding(t *testing.T) {
 	}
 }
 
+func TestCanonicalEncodingConstantTime(t *testing.T) {
+	modulus := fr.Modulus()
+	var inputs []*big.Int
+	for i := 0; i < 100; i++ {
+		x := randReducedBigInt()
+		inputs = append(inputs, &x)
+	}
+	maxScalar := new(big.Int).Lsh(big.NewInt(1), 256)
+	maxScalar.Sub(maxScalar, big.NewInt(1))
+	inputs = append(inputs,
+		big.NewInt(0),
+		big.NewInt(1),
+		new(big.Int).Sub(modulus, big.NewInt(1)),
+		modulus,
+		new(big.Int).Add(modulus, big.NewInt(1)),
+		maxScalar,
+	)
+
+	for _, input := range inputs {
+		var serScalar [fr.Bytes]byte
+		input.FillBytes(serScalar[:])
+
+		expected, expectedErr := ReduceCanonicalBigEndian(serScalar[:])
+		got, err := ReduceCanonicalBigEndianConstantTime(serScalar[:])
+		if (err == nil) != (expectedErr == nil) {
+			t.Fatalf("got error %v, expected %v for %s", err, expectedErr, input)
+		}
+		if !got.Equal(&expected) {
+			t.Fatalf("got %s, expected %s", got.String(), expected.String())
+		}
+	}
+
+	if _, err := ReduceCanonicalBigEndianConstantTime(make([]byte, fr.Bytes-1)); err == nil {
+		t.Error("input to ReduceCanonicalBigEndianConstantTime was too short")
+	}
+}
+
 // Adds the modulus to the big integer
 // we need to do it with a big.Int
 // since an fr.Element will apply the
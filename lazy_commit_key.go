@@ -0,0 +1,106 @@
+package gokzg4844
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/crate-crypto/go-kzg-4844/internal/kzg"
+)
+
+// LazyCommitKey commits to vectors of scalars with a trusted setup that is read from a file, for setups that are too
+// large to deserialize upfront. It is created with [OpenLazyCommitKey].
+//
+// The file is memory-mapped where the platform supports it, and read into memory otherwise. Its G1 points are only
+// decompressed and checked to be in the prime-order subgroup when a commitment first needs them, and then cached, so
+// that committing to a short vector only touches the start of the file.
+//
+// A LazyCommitKey is safe for concurrent use by multiple go-routines, including [LazyCommitKey.Close].
+type LazyCommitKey struct {
+	key *kzg.LazyCommitKey
+
+	// mu is held for reading while the file is in use, and for writing while it is released, so that the memory is
+	// never unmapped under a commitment. unmap is nil once the file was released.
+	mu    sync.RWMutex
+	unmap func() error
+}
+
+// OpenLazyCommitKey returns a [LazyCommitKey] for the file at path, which must hold the compressed G1 points of the
+// trusted setup, 48 bytes each and with nothing else, in the order in which they are multiplied with the scalars.
+//
+// Returns an error wrapping [ErrTrustedSetupLength] if the size of the file is not a positive multiple of 48 bytes.
+// The file must not be modified while the LazyCommitKey is open.
+func OpenLazyCommitKey(path string) (*LazyCommitKey, error) {
+	data, unmap, err := mapFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 || len(data)%CompressedG1Size != 0 {
+		_ = unmap()
+		return nil, fmt.Errorf("%w: got a file of %d bytes, expected a multiple of %d", ErrTrustedSetupLength, len(data), CompressedG1Size)
+	}
+
+	key, err := kzg.NewLazyCommitKey(data)
+	if err != nil {
+		_ = unmap()
+		return nil, err
+	}
+	return &LazyCommitKey{key: key, unmap: unmap}, nil
+}
+
+// Len returns the number of G1 points of the trusted setup, which is the largest number of scalars that can be
+// committed to.
+func (k *LazyCommitKey) Len() int {
+	return k.key.Len()
+}
+
+// CommitToScalars commits to a vector of scalars, by computing the multi exponentiation of the scalars with the first
+// len(scalars) G1 points of the trusted setup, which are decompressed first if they were not needed before.
+//
+// There must be between 1 and [LazyCommitKey.Len] scalars, otherwise the returned error wraps
+// [ErrInvalidPolynomialSize]. If a scalar is not canonical, the returned error contains its index and wraps
+// [ErrNonCanonicalScalar]. If one of the points of the file is invalid, the returned error contains its index.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+func (k *LazyCommitKey) CommitToScalars(scalars []Scalar, numGoRoutines int) (KZGCommitment, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if k.unmap == nil {
+		return KZGCommitment{}, ErrCommitKeyClosed
+	}
+
+	// 1. Deserialization
+	//
+	poly := make(kzg.Polynomial, len(scalars))
+	for i := 0; i < len(scalars); i++ {
+		scalar, err := DeserializeScalar(scalars[i])
+		if err != nil {
+			return KZGCommitment{}, fmt.Errorf("scalar %d: %w", i, err)
+		}
+		poly[i] = scalar
+	}
+
+	// 2. Commit to the scalars
+	//
+	commitment, err := kzg.CommitLazy(poly, k.key, numGoRoutines)
+	if err != nil {
+		return KZGCommitment{}, err
+	}
+
+	// 3. Serialization
+	//
+	return KZGCommitment(SerializeG1Point(*commitment)), nil
+}
+
+// Close releases the file, after waiting for the commitments in progress to finish. Afterwards, the LazyCommitKey
+// returns [ErrCommitKeyClosed].
+func (k *LazyCommitKey) Close() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.unmap == nil {
+		return nil
+	}
+	unmap := k.unmap
+	k.unmap = nil
+	return unmap()
+}
@@ -0,0 +1,131 @@
+package gokzg4844_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	"github.com/stretchr/testify/require"
+)
+
+// writeMonomialSetup writes the compressed monomial G1 points of the embedded setup to a file, and returns its path.
+func writeMonomialSetup(t *testing.T) (string, []byte) {
+	t.Helper()
+	var data []byte
+	for _, point := range ethereumTrustedSetupFromEmbedded(t)["g1_monomial"] {
+		raw, err := hex.DecodeString(strings.TrimPrefix(point, "0x"))
+		require.NoError(t, err)
+		data = append(data, raw...)
+	}
+	path := filepath.Join(t.TempDir(), "setup.bin")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path, data
+}
+
+func TestLazyCommitKey(t *testing.T) {
+	path, data := writeMonomialSetup(t)
+	key, err := gokzg4844.OpenLazyCommitKey(path)
+	require.NoError(t, err)
+	require.Equal(t, gokzg4844.ScalarsPerBlob, key.Len())
+
+	// The commitments match those to the polynomials with the scalars as coefficients, both for short vectors, which
+	// only need the first points, and for full ones, from concurrent callers
+	numScalarsList := []int{1, 10, gokzg4844.ScalarsPerBlob, gokzg4844.ScalarsPerBlob}
+	expected := make([]gokzg4844.KZGCommitment, len(numScalarsList))
+	got := make([]gokzg4844.KZGCommitment, len(numScalarsList))
+	errs := make([]error, len(numScalarsList))
+	var wg sync.WaitGroup
+	for k, numScalars := range numScalarsList {
+		scalars := make([]gokzg4844.Scalar, numScalars)
+		coeffs := make([]fr.Element, numScalars)
+		for i := range scalars {
+			scalars[i] = GetRandFieldElement(int64(i))
+			coeffs[i], err = gokzg4844.DeserializeScalar(scalars[i])
+			require.NoError(t, err)
+		}
+		expected[k], err = ctx.CommitMonomial(coeffs, NumGoRoutines)
+		require.NoError(t, err)
+
+		wg.Add(1)
+		go func(k int) {
+			defer wg.Done()
+			got[k], errs[k] = key.CommitToScalars(scalars, NumGoRoutines)
+		}(k)
+	}
+	wg.Wait()
+	for k := range numScalarsList {
+		require.NoError(t, errs[k])
+		require.Equal(t, expected[k], got[k])
+	}
+
+	_, err = key.CommitToScalars(nil, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidPolynomialSize)
+	_, err = key.CommitToScalars(make([]gokzg4844.Scalar, gokzg4844.ScalarsPerBlob+1), NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidPolynomialSize)
+	_, err = key.CommitToScalars([]gokzg4844.Scalar{nonCanonicalScalar(1)}, NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+
+	require.NoError(t, key.Close())
+	_, err = key.CommitToScalars(make([]gokzg4844.Scalar, 1), NumGoRoutines)
+	require.ErrorIs(t, err, gokzg4844.ErrCommitKeyClosed)
+
+	// An invalid point is only reported once it is needed
+	notInSubgroup := gokzg4844.SerializeG1Point(g1PointNotInSubgroup())
+	copy(data[3000*gokzg4844.CompressedG1Size:], notInSubgroup[:])
+	invalidPath := filepath.Join(t.TempDir(), "invalid.bin")
+	require.NoError(t, os.WriteFile(invalidPath, data, 0o600))
+	key, err = gokzg4844.OpenLazyCommitKey(invalidPath)
+	require.NoError(t, err)
+	defer key.Close()
+	_, err = key.CommitToScalars(make([]gokzg4844.Scalar, 10), NumGoRoutines)
+	require.NoError(t, err)
+	_, err = key.CommitToScalars(make([]gokzg4844.Scalar, gokzg4844.ScalarsPerBlob), NumGoRoutines)
+	require.ErrorContains(t, err, "point 3000")
+
+	truncatedPath := filepath.Join(t.TempDir(), "truncated.bin")
+	require.NoError(t, os.WriteFile(truncatedPath, data[:100], 0o600))
+	_, err = gokzg4844.OpenLazyCommitKey(truncatedPath)
+	require.ErrorIs(t, err, gokzg4844.ErrTrustedSetupLength)
+	emptyPath := filepath.Join(t.TempDir(), "empty.bin")
+	require.NoError(t, os.WriteFile(emptyPath, nil, 0o600))
+	_, err = gokzg4844.OpenLazyCommitKey(emptyPath)
+	require.ErrorIs(t, err, gokzg4844.ErrTrustedSetupLength)
+}
+
+func TestLazyCommitKeyConcurrentClose(t *testing.T) {
+	path, _ := writeMonomialSetup(t)
+	key, err := gokzg4844.OpenLazyCommitKey(path)
+	require.NoError(t, err)
+	expected, err := key.CommitToScalars([]gokzg4844.Scalar{GetRandFieldElement(1)}, NumGoRoutines)
+	require.NoError(t, err)
+
+	// Commitments which race with Close either finish on the mapped file or see that it was closed
+	const numCommits = 8
+	errs := make([]error, numCommits)
+	var wg sync.WaitGroup
+	for k := 0; k < numCommits; k++ {
+		wg.Add(1)
+		go func(k int) {
+			defer wg.Done()
+			var got gokzg4844.KZGCommitment
+			got, errs[k] = key.CommitToScalars([]gokzg4844.Scalar{GetRandFieldElement(1)}, NumGoRoutines)
+			if errs[k] == nil && got != expected {
+				errs[k] = errors.New("unexpected commitment")
+			}
+		}(k)
+	}
+	require.NoError(t, key.Close())
+	wg.Wait()
+	for k := 0; k < numCommits; k++ {
+		if errs[k] != nil {
+			require.ErrorIs(t, errs[k], gokzg4844.ErrCommitKeyClosed)
+		}
+	}
+	require.NoError(t, key.Close())
+}
@@ -0,0 +1,14 @@
+//go:build !linux && !darwin
+
+package gokzg4844
+
+import "os"
+
+// mapFile reads the file at path into memory, on platforms where it is not memory-mapped.
+func mapFile(path string) ([]byte, func() error, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() error { return nil }, nil
+}
@@ -0,0 +1,101 @@
+package gokzg4844
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// PrecompileInputSize is the number of bytes of the input to the [point evaluation precompile].
+//
+// [point evaluation precompile]: https://eips.ethereum.org/EIPS/eip-4844#point-evaluation-precompile
+const PrecompileInputSize = 32 + 2*SerializedScalarSize + 2*CompressedG1Size
+
+// VersionedHashVersionKZG is the version byte of the versioned hash of a KZG commitment.
+//
+// It matches [VERSIONED_HASH_VERSION_KZG] in the EIP.
+//
+// [VERSIONED_HASH_VERSION_KZG]: https://eips.ethereum.org/EIPS/eip-4844#parameters
+const VersionedHashVersionKZG = 0x01
+
+// PrecompileOutput is the 64 bytes returned by the [point evaluation precompile] when the proof verifies: the number
+// of field elements in a blob followed by the scalar field modulus, both as 32 byte big-endian integers.
+//
+// [point evaluation precompile]: https://eips.ethereum.org/EIPS/eip-4844#point-evaluation-precompile
+var PrecompileOutput = func() [64]byte {
+	var output [64]byte
+	binary.BigEndian.PutUint64(output[24:32], ScalarsPerBlob)
+	copy(output[32:], BlsModulus[:])
+	return output
+}()
+
+// KZGToVersionedHash implements [kzg_to_versioned_hash]. It returns the SHA-256 hash of the commitment, with its first
+// byte replaced by [VersionedHashVersionKZG].
+//
+// [kzg_to_versioned_hash]: https://eips.ethereum.org/EIPS/eip-4844#helpers
+func KZGToVersionedHash(commitment KZGCommitment) [32]byte {
+	versionedHash := sha256.Sum256(commitment[:])
+	versionedHash[0] = VersionedHashVersionKZG
+	return versionedHash
+}
+
+// ComputeVersionedHash returns the versioned hash that blob transactions refer to the commitment by. It is the same as
+// [KZGToVersionedHash], and is provided on the Context for callers which only hold a Context.
+func (c *Context) ComputeVersionedHash(commitment KZGCommitment) [32]byte {
+	return KZGToVersionedHash(commitment)
+}
+
+// VerifyVersionedHash reports whether versionedHash is the versioned hash of the commitment, as computed by
+// [Context.ComputeVersionedHash]. This also checks the version byte, so hashes of other versions are rejected.
+func (c *Context) VerifyVersionedHash(versionedHash [32]byte, commitment KZGCommitment) bool {
+	return KZGToVersionedHash(commitment) == versionedHash
+}
+
+// EncodePrecompileInput returns the input to the [point evaluation precompile] which checks that the blob with the
+// given versioned hash and commitment evaluates to y at z: the concatenation of the versioned hash, z, y, the
+// commitment and the proof.
+//
+// [point evaluation precompile]: https://eips.ethereum.org/EIPS/eip-4844#point-evaluation-precompile
+func EncodePrecompileInput(versionedHash [32]byte, z, y Scalar, commitment KZGCommitment, proof KZGProof) [PrecompileInputSize]byte {
+	var input [PrecompileInputSize]byte
+	offset := copy(input[:], versionedHash[:])
+	offset += copy(input[offset:], z[:])
+	offset += copy(input[offset:], y[:])
+	offset += copy(input[offset:], commitment[:])
+	copy(input[offset:], proof[:])
+	return input
+}
+
+// VerifyPrecompile implements the [point evaluation precompile]. It splits the input into the fields written by
+// [EncodePrecompileInput], checks that the versioned hash is the one of the commitment, and then verifies the proof
+// with [Context.VerifyKZGProof]. The precompile returns [PrecompileOutput] when this returns nil.
+//
+// Returns [ErrVersionedHashMismatch] if the versioned hash is not [KZGToVersionedHash] of the commitment.
+//
+// [point evaluation precompile]: https://eips.ethereum.org/EIPS/eip-4844#point-evaluation-precompile
+func (c *Context) VerifyPrecompile(input [PrecompileInputSize]byte) error {
+	if c.closed {
+		return ErrContextClosed
+	}
+
+	// 1. Split the input
+	//
+	var (
+		versionedHash [32]byte
+		z, y          Scalar
+		commitment    KZGCommitment
+		proof         KZGProof
+	)
+	offset := copy(versionedHash[:], input[:])
+	offset += copy(z[:], input[offset:])
+	offset += copy(y[:], input[offset:])
+	offset += copy(commitment[:], input[offset:])
+	copy(proof[:], input[offset:])
+
+	// 2. Check the versioned hash
+	if !c.VerifyVersionedHash(versionedHash, commitment) {
+		return ErrVersionedHashMismatch
+	}
+
+	// 3. Verify the proof
+	return c.VerifyKZGProof(commitment, z, y, proof)
+}
@@ -0,0 +1,71 @@
+package gokzg4844_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyPrecompile(t *testing.T) {
+	blob := GetRandBlob(80)
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	z := GetRandFieldElement(80)
+	proof, y, err := ctx.ComputeKZGProof(blob, z, NumGoRoutines)
+	require.NoError(t, err)
+
+	versionedHash := gokzg4844.KZGToVersionedHash(commitment)
+	require.Equal(t, byte(gokzg4844.VersionedHashVersionKZG), versionedHash[0])
+
+	input := gokzg4844.EncodePrecompileInput(versionedHash, z, y, commitment, proof)
+	require.Equal(t, versionedHash[:], input[:32])
+	require.Equal(t, z[:], input[32:64])
+	require.Equal(t, y[:], input[64:96])
+	require.Equal(t, commitment[:], input[96:144])
+	require.Equal(t, proof[:], input[144:])
+	require.NoError(t, ctx.VerifyPrecompile(input))
+
+	// The versioned hash must be the one of the commitment
+	badHash := versionedHash
+	badHash[0] = 0x02
+	err = ctx.VerifyPrecompile(gokzg4844.EncodePrecompileInput(badHash, z, y, commitment, proof))
+	require.ErrorIs(t, err, gokzg4844.ErrVersionedHashMismatch)
+
+	// And the claimed value the evaluation of the blob
+	err = ctx.VerifyPrecompile(gokzg4844.EncodePrecompileInput(versionedHash, z, GetRandFieldElement(81), commitment, proof))
+	require.ErrorIs(t, err, gokzg4844.ErrVerifyOpeningProof)
+	err = ctx.VerifyPrecompile(gokzg4844.EncodePrecompileInput(versionedHash, z, gokzg4844.BlsModulus, commitment, proof))
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+}
+
+func TestKZGToVersionedHash(t *testing.T) {
+	// The versioned hash of the commitment to the zero blob, computed with:
+	// hashlib.sha256(bytes.fromhex("c0" + "00" * 47)).digest(), with the first byte set to 0x01
+	expected, err := hex.DecodeString("010657f37554c781402a22917dee2f75def7ab966d7b770905398eba3c444014")
+	require.NoError(t, err)
+	got := gokzg4844.KZGToVersionedHash(gokzg4844.PointAtInfinity)
+	require.Equal(t, expected, got[:])
+}
+
+func TestVerifyVersionedHash(t *testing.T) {
+	commitment, err := ctx.BlobToKZGCommitment(GetRandBlob(82), NumGoRoutines)
+	require.NoError(t, err)
+	versionedHash := ctx.ComputeVersionedHash(commitment)
+	require.Equal(t, gokzg4844.KZGToVersionedHash(commitment), versionedHash)
+	require.True(t, ctx.VerifyVersionedHash(versionedHash, commitment))
+
+	require.False(t, ctx.VerifyVersionedHash(versionedHash, gokzg4844.PointAtInfinity))
+	otherVersion := versionedHash
+	otherVersion[0] = 0x02
+	require.False(t, ctx.VerifyVersionedHash(otherVersion, commitment))
+}
+
+func TestPrecompileOutput(t *testing.T) {
+	// FIELD_ELEMENTS_PER_BLOB and BLS_MODULUS, as in the EIP
+	expected, err := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000001000" +
+		"73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001")
+	require.NoError(t, err)
+	require.Equal(t, expected, gokzg4844.PrecompileOutput[:])
+}
@@ -1,9 +1,22 @@
 package gokzg4844
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	"github.com/crate-crypto/go-kzg-4844/internal/kzg"
+	"golang.org/x/sync/errgroup"
 )
 
+// scalarsPerReadChunk is the number of field elements that [Context.BlobToKZGCommitmentReader] buffers before
+// committing to them. This must divide [ScalarsPerBlob].
+const scalarsPerReadChunk = 256
+
 // BlobToKZGCommitment implements [blob_to_kzg_commitment].
 //
 // numGoRoutines is used to configure the amount of concurrency needed. Setting this
@@ -11,6 +24,10 @@ import (
 //
 // [blob_to_kzg_commitment]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#blob_to_kzg_commitment
 func (c *Context) BlobToKZGCommitment(blob Blob, numGoRoutines int) (KZGCommitment, error) {
+	if err := c.checkCanProve(); err != nil {
+		return KZGCommitment{}, err
+	}
+
 	// 1. Deserialization
 	//
 	// Deserialize blob into polynomial
@@ -20,7 +37,9 @@ func (c *Context) BlobToKZGCommitment(blob Blob, numGoRoutines int) (KZGCommitme
 	}
 
 	// 2. Commit to polynomial
-	commitment, err := kzg.Commit(polynomial, c.commitKey, numGoRoutines)
+	stopTiming := c.startTiming(OpCommit)
+	commitment, err := kzg.Commit(polynomial, c.commitKey, c.goRoutines(numGoRoutines))
+	stopTiming()
 	if err != nil {
 		return KZGCommitment{}, err
 	}
@@ -33,17 +52,381 @@ func (c *Context) BlobToKZGCommitment(blob Blob, numGoRoutines int) (KZGCommitme
 	return KZGCommitment(serComm), nil
 }
 
+// VerifyBlobCommitment checks that commitment is the commitment to the blob, by recomputing it with
+// [Context.BlobToKZGCommitment] and comparing the serialized commitments. This does not need a proof, but costs a
+// commitment, so it is meant for callers which hold the whole blob, such as after downloading the blob for a known
+// versioned hash.
+//
+// Returns an error wrapping [ErrBlobCommitmentMismatch], which contains both commitments, if they differ, and the
+// error from [Context.BlobToKZGCommitment] if the blob is malformed. Since it needs the commit key, a Context created
+// with [NewVerifierContext] returns [ErrProvingNotSupported].
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+func (c *Context) VerifyBlobCommitment(blob Blob, commitment KZGCommitment, numGoRoutines int) error {
+	computedCommitment, err := c.BlobToKZGCommitment(blob, numGoRoutines)
+	if err != nil {
+		return err
+	}
+	if computedCommitment != commitment {
+		return fmt.Errorf("%w: expected %#x, computed %#x", ErrBlobCommitmentMismatch, commitment[:], computedCommitment[:])
+	}
+	return nil
+}
+
+// CommitMonomial commits to a polynomial given by its coefficients in the monomial basis, using the monomial G1 points
+// of the trusted setup. This is the same commitment as [Context.BlobToKZGCommitment] would return for the blob holding
+// the evaluations of the polynomial, without needing to convert it to evaluation form first.
+//
+// There must be at most [ScalarsPerBlob] coefficients. The monomial points are only kept in the Context if the trusted
+// setup includes them and the Context was created with [WithMonomialSetup]; otherwise [ErrMissingMonomialSetup] is
+// returned.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+func (c *Context) CommitMonomial(coeffs []fr.Element, numGoRoutines int) (KZGCommitment, error) {
+	if err := c.checkCanProve(); err != nil {
+		return KZGCommitment{}, err
+	}
+	if c.monomialG1 == nil {
+		return KZGCommitment{}, ErrMissingMonomialSetup
+	}
+
+	// 1. Commit to polynomial
+	monomialCommitKey := kzg.CommitKey{G1: c.monomialG1}
+	monomialCommitKey.SetMultiExpBackend(c.multiExpBackend)
+	stopTiming := c.startTiming(OpCommit)
+	commitment, err := kzg.Commit(coeffs, &monomialCommitKey, c.goRoutines(numGoRoutines))
+	stopTiming()
+	if err != nil {
+		return KZGCommitment{}, err
+	}
+
+	// 2. Serialization
+	//
+	return KZGCommitment(SerializeG1Point(*commitment)), nil
+}
+
+// CommitToScalars commits to a vector of scalars, by computing the multi exponentiation of the scalars with the first
+// len(scalars) lagrange G1 points of the trusted setup, in the bit-reversed order that they are used in for blobs.
+// For [ScalarsPerBlob] scalars, this is the same commitment as [Context.BlobToKZGCommitment] would return for the
+// blob holding them.
+//
+// There must be between 1 and [ScalarsPerBlob] scalars, otherwise the returned error wraps [ErrInvalidPolynomialSize].
+// If a scalar is not canonical, the returned error contains its index and wraps [ErrNonCanonicalScalar].
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+func (c *Context) CommitToScalars(scalars []Scalar, numGoRoutines int) (KZGCommitment, error) {
+	if err := c.checkCanProve(); err != nil {
+		return KZGCommitment{}, err
+	}
+
+	// 1. Deserialization
+	//
+	elements, err := deserializeScalars(scalars, "scalar")
+	if err != nil {
+		return KZGCommitment{}, err
+	}
+
+	// 2. Commit to the scalars
+	stopTiming := c.startTiming(OpCommit)
+	commitment, err := kzg.Commit(elements, c.commitKey, c.goRoutines(numGoRoutines))
+	stopTiming()
+	if err != nil {
+		return KZGCommitment{}, err
+	}
+
+	// 3. Serialization
+	//
+	return KZGCommitment(SerializeG1Point(*commitment)), nil
+}
+
+// CommitToScalarsInChunks returns the same commitment as [Context.CommitToScalars], but splits the scalars into
+// chunks of chunkSize scalars which are committed to concurrently, each against its own range of lagrange G1 points,
+// and sums the partial commitments. This is useful with a [MultiExpBackend] which limits the size of a single
+// multi exponentiation. If chunkSize is not positive, or is at least len(scalars), this is
+// [Context.CommitToScalars].
+//
+// The size and canonicity of the scalars are checked as in [Context.CommitToScalars].
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+func (c *Context) CommitToScalarsInChunks(scalars []Scalar, chunkSize, numGoRoutines int) (KZGCommitment, error) {
+	if err := c.checkCanProve(); err != nil {
+		return KZGCommitment{}, err
+	}
+
+	// 1. Deserialization
+	//
+	elements, err := deserializeScalars(scalars, "scalar")
+	if err != nil {
+		return KZGCommitment{}, err
+	}
+
+	// 2. Commit to the chunks and sum the partial commitments
+	stopTiming := c.startTiming(OpCommit)
+	commitment, err := kzg.CommitLarge(elements, c.commitKey, chunkSize, c.goRoutines(numGoRoutines))
+	stopTiming()
+	if err != nil {
+		return KZGCommitment{}, err
+	}
+
+	// 3. Serialization
+	//
+	return KZGCommitment(SerializeG1Point(*commitment)), nil
+}
+
+// ComputeKZGProofForScalars computes a proof that the polynomial whose evaluations over the domain of the Context are
+// the given scalars, in bit-reversed order, evaluates to the returned claimed value at the input point. The commitment
+// to the polynomial is the one returned by [Context.CommitToScalars], and the proof is checked with
+// [Context.VerifyKZGProof]. For [ScalarsPerBlob] scalars, this is the same as [Context.ComputeKZGProof] for the blob
+// holding them.
+//
+// This is mainly useful with Contexts created by [NewContextFromJSONWithSize], whose domain is smaller than a blob.
+// There must be exactly as many scalars as the domain has points, otherwise the returned error wraps
+// [ErrInvalidPolynomialSize]. If a scalar is not canonical, the returned error contains its index and wraps
+// [ErrNonCanonicalScalar].
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+func (c *Context) ComputeKZGProofForScalars(scalars []Scalar, inputPointBytes Scalar, numGoRoutines int) (KZGProof, Scalar, error) {
+	if err := c.checkCanProve(); err != nil {
+		return KZGProof{}, [32]byte{}, err
+	}
+
+	// 1. Deserialization
+	//
+	polynomial, err := deserializeScalars(scalars, "scalar")
+	if err != nil {
+		return KZGProof{}, [32]byte{}, err
+	}
+
+	inputPoint, err := c.deserializeEvaluationPoint(inputPointBytes)
+	if err != nil {
+		return KZGProof{}, [32]byte{}, err
+	}
+
+	// 2. Create opening proof
+	stopTiming := c.startTiming(OpOpen)
+	openingProof, err := kzg.Open(c.domain, polynomial, inputPoint, c.commitKey, c.goRoutines(numGoRoutines))
+	stopTiming()
+	if err != nil {
+		return KZGProof{}, [32]byte{}, err
+	}
+
+	// 3. Serialization
+	//
+	kzgProof := SerializeG1Point(openingProof.QuotientCommitment)
+
+	return KZGProof(kzgProof), SerializeScalar(openingProof.ClaimedValue), nil
+}
+
+// DividePolyByXminusAMonomial divides a polynomial given by its coefficients in the monomial basis by X - a, and
+// returns the coefficients of the quotient along with the remainder, which is the evaluation of the polynomial at a.
+//
+// Together with [Context.CommitMonomial], this allows computing opening proofs for polynomials which are not in
+// evaluation form over the domain of blobs.
+func DividePolyByXminusAMonomial(coeffs []fr.Element, a fr.Element) ([]fr.Element, fr.Element) {
+	return kzg.DividePolyByXminusAMonomial(coeffs, a)
+}
+
+// InterpolatePolynomial returns the coefficients in the monomial basis, starting with the constant term, of the unique
+// polynomial of degree less than len(points) which evaluates to values[i] at points[i].
+//
+// Together with [Context.CommitMonomial] and [DividePolyByXminusAMonomial], this allows committing to and opening
+// polynomials given by their evaluations at arbitrary points, rather than at the domain of blobs. Interpolation takes
+// a number of field operations which is quadratic in the number of points.
+//
+// Returns [ErrInterpolationLengthMismatch] if the number of points and values differ, and an error wrapping
+// [ErrDuplicateInterpolationPoint] if two of the points are equal. If a scalar is not canonical, the returned error
+// contains its index and wraps [ErrNonCanonicalScalar].
+func InterpolatePolynomial(points, values []Scalar) ([]fr.Element, error) {
+	if len(points) != len(values) {
+		return nil, ErrInterpolationLengthMismatch
+	}
+
+	// 1. Deserialization
+	//
+	xs := make([]fr.Element, len(points))
+	ys := make([]fr.Element, len(values))
+	for i := 0; i < len(points); i++ {
+		var err error
+		xs[i], err = DeserializeScalar(points[i])
+		if err != nil {
+			return nil, fmt.Errorf("point %d: %w", i, err)
+		}
+		ys[i], err = DeserializeScalar(values[i])
+		if err != nil {
+			return nil, fmt.Errorf("value %d: %w", i, err)
+		}
+	}
+
+	// 2. Interpolate
+	return kzg.InterpolateMonomial(xs, ys)
+}
+
+// BlobsToKZGCommitments computes the commitments to each of the blobs, as [Context.BlobToKZGCommitment] would. The
+// commitments are returned in the same order as the blobs.
+//
+// The blobs are committed to with [kzg.CommitBatch], which commits to them concurrently, using at most numGoRoutines
+// go-routines in total, and shares the precomputations on the trusted setup between large batches. If any blob fails
+// to deserialize, an error which contains the index of the blob is returned.
+//
+// This is [Context.BlobsToKZGCommitmentsContext] with [context.Background], and so cannot be cancelled.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+func (c *Context) BlobsToKZGCommitments(blobs []Blob, numGoRoutines int) ([]KZGCommitment, error) {
+	return c.BlobsToKZGCommitmentsContext(context.Background(), blobs, numGoRoutines)
+}
+
+// BlobsToKZGCommitmentsContext is [Context.BlobsToKZGCommitments], but returns ctx.Err() once ctx is done. The context
+// is checked before each blob is deserialized and before each commitment is computed, with [kzg.CommitBatchContext];
+// a commitment which has started is finished, but no new one is started.
+//
+// The blobs are deserialized concurrently, and are all held in deserialized form while they are committed to, which
+// takes as much memory as the blobs themselves.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+func (c *Context) BlobsToKZGCommitmentsContext(ctx context.Context, blobs []Blob, numGoRoutines int) ([]KZGCommitment, error) {
+	if err := c.checkCanProve(); err != nil {
+		return nil, err
+	}
+	numGoRoutines = c.goRoutines(numGoRoutines)
+	if numGoRoutines <= 0 {
+		numGoRoutines = runtime.NumCPU()
+	}
+
+	// 1. Deserialization
+	//
+	polynomials := make([]kzg.Polynomial, len(blobs))
+	var errG errgroup.Group
+	errG.SetLimit(numGoRoutines)
+	for i := 0; i < len(blobs); i++ {
+		j := i // Capture the value of the loop variable
+		errG.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			polynomial, err := DeserializeBlob(blobs[j])
+			if err != nil {
+				return fmt.Errorf("blob %d: %w", j, err)
+			}
+			polynomials[j] = polynomial
+			return nil
+		})
+	}
+	if err := errG.Wait(); err != nil {
+		return nil, err
+	}
+
+	// 2. Commit to the polynomials
+	stopTiming := c.startTiming(OpCommit)
+	commitments, err := kzg.CommitBatchContext(ctx, polynomials, c.commitKey, numGoRoutines)
+	stopTiming()
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Serialization
+	//
+	serCommitments := make([]KZGCommitment, len(commitments))
+	for i := 0; i < len(commitments); i++ {
+		serCommitments[i] = KZGCommitment(SerializeG1Point(commitments[i]))
+	}
+	return serCommitments, nil
+}
+
+// BlobToKZGCommitmentReader is the streaming version of [Context.BlobToKZGCommitment]. It reads a serialized blob from
+// `r`, one field element at a time, and commits to it incrementally, so that the whole blob never needs to be held in
+// memory.
+//
+// An error is returned as soon as a non-canonical field element is read; this error contains the index of the
+// offending field element and wraps [ErrNonCanonicalScalar]. If `r` ends before a whole blob was read, the returned
+// error contains the number of bytes that were read and wraps [ErrInvalidBlobLength]. Only the bytes of the blob are
+// read, so `r` may hold more data after it.
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+func (c *Context) BlobToKZGCommitmentReader(r io.Reader, numGoRoutines int) (KZGCommitment, error) {
+	if err := c.checkCanProve(); err != nil {
+		return KZGCommitment{}, err
+	}
+	if err := c.checkBlobDomain(); err != nil {
+		return KZGCommitment{}, err
+	}
+
+	var (
+		commitment bls12381.G1Jac
+		serScalar  Scalar
+	)
+	chunk := make(kzg.Polynomial, 0, scalarsPerReadChunk)
+
+	for i := 0; i < ScalarsPerBlob; i++ {
+		// 1. Deserialization
+		//
+		// Read and deserialize the next field element
+		n, err := io.ReadFull(r, serScalar[:])
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return KZGCommitment{}, fmt.Errorf("%w: got %d bytes, expected %d", ErrInvalidBlobLength, i*SerializedScalarSize+n, len(Blob{}))
+		}
+		if err != nil {
+			return KZGCommitment{}, fmt.Errorf("field element %d: %w", i, err)
+		}
+		scalar, err := DeserializeScalar(serScalar)
+		if err != nil {
+			return KZGCommitment{}, fmt.Errorf("field element %d: %w", i, err)
+		}
+		chunk = append(chunk, scalar)
+
+		if len(chunk) < scalarsPerReadChunk {
+			continue
+		}
+
+		// 2. Commit to the chunk
+		//
+		// The field elements in this chunk correspond to the
+		// same range of points in the commit key.
+		offset := i + 1 - len(chunk)
+		chunkKey := kzg.CommitKey{G1: c.commitKey.G1[offset : i+1]}
+		chunkKey.SetMultiExpBackend(c.multiExpBackend)
+		partialCommitment, err := kzg.Commit(chunk, &chunkKey, c.goRoutines(numGoRoutines))
+		if err != nil {
+			return KZGCommitment{}, err
+		}
+		commitment.AddMixed(partialCommitment)
+		chunk = chunk[:0]
+	}
+
+	// 3. Serialization
+	//
+	// Serialize commitment
+	var commitmentAff bls12381.G1Affine
+	commitmentAff.FromJacobian(&commitment)
+	serComm := SerializeG1Point(commitmentAff)
+
+	return KZGCommitment(serComm), nil
+}
+
 // ComputeBlobKZGProof implements [compute_blob_kzg_proof]. It takes a blob and returns the KZG proof that is used to
 // verify it against the given KZG commitment at a random point.
 //
 // Note: This method does not check that the commitment corresponds to the `blob`. The method does still check that the
 // commitment is a valid commitment. One should check this externally or call [Context.BlobToKZGCommitment].
+// The commitment is not recomputed; it is only absorbed into the Fiat-Shamir challenge, so passing a commitment that
+// does not match the blob produces a proof that will fail verification.
 //
 // numGoRoutines is used to configure the amount of concurrency needed. Setting this
 // value to a negative number or 0 will make it default to the number of CPUs.
 //
 // [compute_blob_kzg_proof]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#compute_blob_kzg_proof
 func (c *Context) ComputeBlobKZGProof(blob Blob, blobCommitment KZGCommitment, numGoRoutines int) (KZGProof, error) {
+	if err := c.checkCanProve(); err != nil {
+		return KZGProof{}, err
+	}
+
 	// 1. Deserialization
 	//
 	polynomial, err := DeserializeBlob(blob)
@@ -54,16 +437,18 @@ func (c *Context) ComputeBlobKZGProof(blob Blob, blobCommitment KZGCommitment, n
 	// Deserialize commitment
 	//
 	// We only do this to check if it is in the correct subgroup
-	_, err = DeserializeKZGCommitment(blobCommitment)
+	_, err = c.deserializeKZGCommitment(blobCommitment)
 	if err != nil {
 		return KZGProof{}, err
 	}
 
 	// 2. Compute Fiat-Shamir challenge
-	evaluationChallenge := computeChallenge(blob, blobCommitment)
+	evaluationChallenge := computeChallenge(c.newChallengeHash, blob, blobCommitment)
 
 	// 3. Create opening proof
-	openingProof, err := kzg.Open(c.domain, polynomial, evaluationChallenge, c.commitKey, numGoRoutines)
+	stopTiming := c.startTiming(OpOpen)
+	openingProof, err := kzg.Open(c.domain, polynomial, evaluationChallenge, c.commitKey, c.goRoutines(numGoRoutines))
+	stopTiming()
 	if err != nil {
 		return KZGProof{}, err
 	}
@@ -78,11 +463,20 @@ func (c *Context) ComputeBlobKZGProof(blob Blob, blobCommitment KZGCommitment, n
 
 // ComputeKZGProof implements [compute_kzg_proof].
 //
+// Alongside the proof, it returns the claimed value y = f(z), serialized as a canonical 32 byte
+// big-endian integer, so that it can be forwarded to the point evaluation precompile as-is.
+//
+// If the Context was created with [WithConstantTimeScalars], the input point is deserialized in constant time.
+//
 // numGoRoutines is used to configure the amount of concurrency needed. Setting this
 // value to a negative number or 0 will make it default to the number of CPUs.
 //
 // [compute_kzg_proof]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#compute_kzg_proof
 func (c *Context) ComputeKZGProof(blob Blob, inputPointBytes Scalar, numGoRoutines int) (KZGProof, Scalar, error) {
+	if err := c.checkCanProve(); err != nil {
+		return KZGProof{}, [32]byte{}, err
+	}
+
 	// 1. Deserialization
 	//
 	polynomial, err := DeserializeBlob(blob)
@@ -90,13 +484,15 @@ func (c *Context) ComputeKZGProof(blob Blob, inputPointBytes Scalar, numGoRoutin
 		return KZGProof{}, [32]byte{}, err
 	}
 
-	inputPoint, err := DeserializeScalar(inputPointBytes)
+	inputPoint, err := c.deserializeEvaluationPoint(inputPointBytes)
 	if err != nil {
 		return KZGProof{}, [32]byte{}, err
 	}
 
 	// 2. Create opening proof
-	openingProof, err := kzg.Open(c.domain, polynomial, inputPoint, c.commitKey, numGoRoutines)
+	stopTiming := c.startTiming(OpOpen)
+	openingProof, err := kzg.Open(c.domain, polynomial, inputPoint, c.commitKey, c.goRoutines(numGoRoutines))
+	stopTiming()
 	if err != nil {
 		return KZGProof{}, [32]byte{}, err
 	}
@@ -109,3 +505,97 @@ func (c *Context) ComputeKZGProof(blob Blob, inputPointBytes Scalar, numGoRoutin
 
 	return KZGProof(kzgProof), claimedValueBytes, nil
 }
+
+// EvaluatePolynomialInEvaluationForm implements [evaluate_polynomial_in_evaluation_form]. It evaluates the polynomial
+// represented by the blob at the point z, without computing a proof.
+//
+// If z is one of the points in the domain, the corresponding evaluation in the blob is returned directly.
+//
+// If the Context was created with [WithConstantTimeScalars], z is deserialized in constant time.
+//
+// [evaluate_polynomial_in_evaluation_form]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#evaluate_polynomial_in_evaluation_form
+func (c *Context) EvaluatePolynomialInEvaluationForm(blob Blob, z Scalar) (Scalar, error) {
+	if c.closed {
+		return Scalar{}, ErrContextClosed
+	}
+
+	// 1. Deserialization
+	//
+	polynomial, err := DeserializeBlob(blob)
+	if err != nil {
+		return Scalar{}, err
+	}
+
+	evaluationPoint, err := c.deserializeEvaluationPoint(z)
+	if err != nil {
+		return Scalar{}, err
+	}
+
+	// 2. Evaluate the polynomial
+	evaluation, err := c.domain.EvaluateLagrangePolynomial(polynomial, evaluationPoint)
+	if err != nil {
+		return Scalar{}, err
+	}
+
+	// 3. Serialization
+	return SerializeScalar(*evaluation), nil
+}
+
+// ProveVanishingOnSet proves that the polynomial given by its coefficients in the monomial basis, starting with the
+// constant term, evaluates to zero at every one of `points`, as in a membership proof for an accumulator. The proof is
+// the commitment to the quotient of the polynomial by the polynomial vanishing at all of the points, and is checked
+// against the commitment from [Context.CommitMonomial] with [Context.VerifyVanishingOnSet].
+//
+// The monomial G1 points must be kept in the Context, as for [Context.CommitMonomial], otherwise
+// [ErrMissingMonomialSetup] is returned. There must be at most [ScalarsPerBlob] coefficients, otherwise the returned error wraps [ErrInvalidPolynomialSize]. If the
+// polynomial is not zero at one of the points, the returned error contains its index and wraps
+// [ErrPolynomialDoesNotVanish]. The points must be distinct, otherwise the returned error wraps
+// [ErrDuplicateInterpolationPoint]. If a scalar is not canonical, the returned error contains its index and wraps
+// [ErrNonCanonicalScalar].
+//
+// numGoRoutines is used to configure the amount of concurrency needed. Setting this
+// value to a negative number or 0 will make it default to the number of CPUs.
+func (c *Context) ProveVanishingOnSet(coeffs []fr.Element, points []Scalar, numGoRoutines int) (KZGProof, error) {
+	if err := c.checkCanProve(); err != nil {
+		return KZGProof{}, err
+	}
+	if c.monomialG1 == nil {
+		return KZGProof{}, ErrMissingMonomialSetup
+	}
+
+	// 1. Deserialization
+	//
+	vanishingPoints, err := deserializeScalars(points, "point")
+	if err != nil {
+		return KZGProof{}, err
+	}
+
+	// 2. Commit to the quotient
+	//
+	monomialCommitKey := kzg.CommitKey{G1: c.monomialG1}
+	monomialCommitKey.SetMultiExpBackend(c.multiExpBackend)
+	stopTiming := c.startTiming(OpOpen)
+	quotientCommitment, err := kzg.ProveVanishingOnSet(coeffs, vanishingPoints, &monomialCommitKey, c.goRoutines(numGoRoutines))
+	stopTiming()
+	if err != nil {
+		return KZGProof{}, err
+	}
+
+	// 3. Serialization
+	//
+	return KZGProof(SerializeG1Point(quotientCommitment)), nil
+}
+
+// deserializeScalars deserializes each of the scalars, returning an error which contains name and the index of the
+// first one that is not canonical.
+func deserializeScalars(scalars []Scalar, name string) ([]fr.Element, error) {
+	elements := make([]fr.Element, len(scalars))
+	for i := 0; i < len(scalars); i++ {
+		var err error
+		elements[i], err = DeserializeScalar(scalars[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s %d: %w", name, i, err)
+		}
+	}
+	return elements, nil
+}
@@ -0,0 +1,142 @@
+package gokzg4844
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// selfTestConstant is the value of every field element of the constant blob of [Context.SelfTest].
+const selfTestConstant = 42
+
+// selfTestInputPoint is the point that [Context.SelfTest] opens its blobs at.
+const selfTestInputPoint = 0x1337
+
+// selfTestConstantCommitment is the commitment to the constant blob, ie selfTestConstant times the G1 generator. The
+// lagrange G1 points of any trusted setup sum to the generator, so this does not depend on the setup.
+var selfTestConstantCommitment = KZGCommitment{
+	0x8c, 0xe3, 0xb5, 0x7b, 0x79, 0x17, 0x98, 0x43,
+	0x3f, 0xd3, 0x23, 0x75, 0x34, 0x89, 0xca, 0xc9,
+	0xbc, 0xa4, 0x3b, 0x98, 0xde, 0xaa, 0xfa, 0xed,
+	0x91, 0xf4, 0xcb, 0x01, 0x07, 0x30, 0xae, 0x1e,
+	0x38, 0xb1, 0x86, 0xcc, 0xd3, 0x7a, 0x09, 0xb8,
+	0xae, 0xd6, 0x2c, 0xe2, 0x3b, 0x69, 0x9c, 0x48,
+}
+
+// selfTestClaimedValue is the evaluation at selfTestInputPoint of the polynomial whose i-th evaluation is i^2 + 7. It
+// only depends on the domain, and not on the trusted setup.
+var selfTestClaimedValue = Scalar{
+	0x3e, 0xa5, 0x87, 0x46, 0x39, 0x7f, 0x70, 0x73,
+	0xe1, 0x77, 0x36, 0xb8, 0x86, 0x4e, 0x07, 0x82,
+	0xd3, 0x5d, 0x45, 0x0c, 0x72, 0xcb, 0xe0, 0xa5,
+	0xc7, 0xdd, 0xed, 0xf2, 0xc1, 0xd1, 0x66, 0x88,
+}
+
+// SelfTest runs a fixed known-answer test of the Context, so that operators can check at startup that the
+// cryptography works on their platform, and catch a miscompiled or corrupted binary. The expected answers are embedded
+// in the library and do not depend on the trusted setup that the Context was created with. It checks that:
+//
+//   - a proof for the blob with all of its field elements equal to 42 verifies against the commitment 42 * G1, and a
+//     proof for a wrong evaluation is rejected
+//   - the commitment to that blob is 42 * G1, and its opening is the point at infinity
+//   - the evaluation of a fixed non-constant blob, computed with [Context.ComputeKZGProof], is the expected one, and
+//     the proof verifies
+//   - a proof from [Context.ComputeBlobKZGProof] for that blob verifies
+//
+// A Context created with [NewVerifierContext], or over a smaller domain with [NewContextFromJSONWithSize], can only
+// run the first check. The returned error wraps [ErrSelfTestFailed].
+//
+// This costs about two commitments, three openings and four pairing checks.
+func (c *Context) SelfTest() error {
+	if c.closed {
+		return ErrContextClosed
+	}
+
+	var constant, inputPoint fr.Element
+	constant.SetUint64(selfTestConstant)
+	inputPoint.SetUint64(selfTestInputPoint)
+	serConstant := SerializeScalar(constant)
+	serInputPoint := SerializeScalar(inputPoint)
+
+	// 1. Verify the opening of the constant blob, which is the point at infinity for every input point
+	//
+	err := c.VerifyKZGProof(selfTestConstantCommitment, serInputPoint, serConstant, PointAtInfinity)
+	if err != nil {
+		return fmt.Errorf("%w: verifying the proof for the constant blob: %v", ErrSelfTestFailed, err)
+	}
+	var wrongValue fr.Element
+	wrongValue.SetUint64(selfTestConstant + 1)
+	err = c.VerifyKZGProof(selfTestConstantCommitment, serInputPoint, SerializeScalar(wrongValue), PointAtInfinity)
+	if !errors.Is(err, ErrVerifyOpeningProof) {
+		return fmt.Errorf("%w: a proof for a wrong evaluation of the constant blob was not rejected", ErrSelfTestFailed)
+	}
+
+	if c.verifierOnly || c.domain.Cardinality != ScalarsPerBlob {
+		return nil
+	}
+
+	// 2. Commit to and open the constant blob
+	//
+	constantBlob := selfTestBlob(func(int) fr.Element { return constant })
+	commitment, err := c.BlobToKZGCommitment(constantBlob, 0)
+	if err != nil {
+		return fmt.Errorf("%w: committing to the constant blob: %v", ErrSelfTestFailed, err)
+	}
+	if commitment != selfTestConstantCommitment {
+		return fmt.Errorf("%w: unexpected commitment to the constant blob", ErrSelfTestFailed)
+	}
+	proof, claimedValue, err := c.ComputeKZGProof(constantBlob, serInputPoint, 0)
+	if err != nil {
+		return fmt.Errorf("%w: opening the constant blob: %v", ErrSelfTestFailed, err)
+	}
+	if proof != PointAtInfinity || claimedValue != serConstant {
+		return fmt.Errorf("%w: unexpected opening of the constant blob", ErrSelfTestFailed)
+	}
+
+	// 3. Commit to, open and verify the non-constant blob
+	//
+	blob := selfTestBlob(func(i int) fr.Element {
+		var evaluation, seven fr.Element
+		evaluation.SetUint64(uint64(i))
+		evaluation.Square(&evaluation)
+		seven.SetUint64(7)
+		return *evaluation.Add(&evaluation, &seven)
+	})
+	commitment, err = c.BlobToKZGCommitment(blob, 0)
+	if err != nil {
+		return fmt.Errorf("%w: committing to the blob: %v", ErrSelfTestFailed, err)
+	}
+	proof, claimedValue, err = c.ComputeKZGProof(blob, serInputPoint, 0)
+	if err != nil {
+		return fmt.Errorf("%w: opening the blob: %v", ErrSelfTestFailed, err)
+	}
+	if claimedValue != selfTestClaimedValue {
+		return fmt.Errorf("%w: unexpected evaluation of the blob", ErrSelfTestFailed)
+	}
+	if err := c.VerifyKZGProof(commitment, serInputPoint, claimedValue, proof); err != nil {
+		return fmt.Errorf("%w: verifying the proof for the blob: %v", ErrSelfTestFailed, err)
+	}
+
+	// 4. Prove and verify the blob with a Fiat-Shamir challenge
+	//
+	blobProof, err := c.ComputeBlobKZGProof(blob, commitment, 0)
+	if err != nil {
+		return fmt.Errorf("%w: computing the blob proof: %v", ErrSelfTestFailed, err)
+	}
+	if err := c.VerifyBlobKZGProof(blob, commitment, blobProof); err != nil {
+		return fmt.Errorf("%w: verifying the blob proof: %v", ErrSelfTestFailed, err)
+	}
+
+	return nil
+}
+
+// selfTestBlob returns the blob whose i-th field element is evaluation(i).
+func selfTestBlob(evaluation func(i int) fr.Element) Blob {
+	var blob Blob
+	for i := 0; i < ScalarsPerBlob; i++ {
+		serScalar := SerializeScalar(evaluation(i))
+		copy(blob[i*SerializedScalarSize:], serScalar[:])
+	}
+	return blob
+}
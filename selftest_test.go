@@ -0,0 +1,39 @@
+package gokzg4844_test
+
+import (
+	"bytes"
+	"testing"
+
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfTest(t *testing.T) {
+	// The known answers do not depend on the trusted setup
+	require.NoError(t, ctx.SelfTest())
+
+	setup := ethereumTrustedSetupFromEmbedded(t)
+	jsonCtx, err := gokzg4844.NewContextFromJSON(bytes.NewReader(marshalJSON(t, setup)), NumGoRoutines)
+	require.NoError(t, err)
+	require.NoError(t, jsonCtx.SelfTest())
+
+	verifierCtx, err := gokzg4844.NewVerifierContext(bytes.NewReader(marshalJSON(t, setup)))
+	require.NoError(t, err)
+	require.NoError(t, verifierCtx.SelfTest())
+
+	singleThreadedCtx, err := gokzg4844.NewContext4096Insecure1337(gokzg4844.WithSingleThreaded())
+	require.NoError(t, err)
+	require.NoError(t, singleThreadedCtx.SelfTest())
+
+	// Lagrange points which do not sum to the generator give a different commitment to the constant blob
+	delete(setup, "g1_monomial")
+	setup["g1_lagrange"][5] = setup["g1_lagrange"][6]
+	badCtx, err := gokzg4844.NewContextFromJSON(bytes.NewReader(marshalJSON(t, setup)), NumGoRoutines)
+	require.NoError(t, err)
+	require.ErrorIs(t, badCtx.SelfTest(), gokzg4844.ErrSelfTestFailed)
+
+	closedCtx, err := gokzg4844.NewContext4096Insecure1337()
+	require.NoError(t, err)
+	require.NoError(t, closedCtx.Close())
+	require.ErrorIs(t, closedCtx.SelfTest(), gokzg4844.ErrContextClosed)
+}
@@ -1,6 +1,10 @@
 package gokzg4844
 
 import (
+	"bytes"
+	"crypto/subtle"
+	"fmt"
+
 	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	"github.com/crate-crypto/go-kzg-4844/internal/kzg"
@@ -10,6 +14,9 @@ import (
 // CompressedG1Size is the number of bytes needed to represent a group element in G1 when compressed.
 const CompressedG1Size = 48
 
+// UncompressedG1Size is the number of bytes needed to represent a group element in G1 when uncompressed.
+const UncompressedG1Size = 96
+
 // CompressedG2Size is the number of bytes needed to represent a group element in G2 when compressed.
 const CompressedG2Size = 96
 
@@ -45,11 +52,22 @@ type (
 
 	// Scalar matches [BLSFieldElement] in the spec.
 	//
+	// A Scalar is a 32 byte big-endian integer, as in EIP-4844. Every method of this package which takes or returns
+	// scalars, including the field elements of a [Blob] and of a [Cell], uses this encoding. Scalars from libraries
+	// which use little-endian integers must be converted with [DeserializeScalarLE], since reading them as big-endian
+	// silently gives a different field element, and so a different commitment.
+	//
 	// [BLSFieldElement]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#custom-types
 	Scalar [SerializedScalarSize]byte
 
 	// Blob is a flattened representation of a serialized polynomial.
 	//
+	// Its field elements are big-endian [Scalar]s. Since it is an array, every blob has exactly ScalarsPerBlob * 32
+	// bytes, and so methods which take blobs do not need to check their length. A blob held in a []byte should be
+	// converted with [BlobFromBytes], which returns [ErrInvalidBlobLength] for a slice of the wrong length, since the
+	// conversion (*Blob)(s) panics if s is too short. Similarly, [Context.BlobToKZGCommitmentReader] returns
+	// [ErrInvalidBlobLength] for a reader which ends too early.
+	//
 	// It matches [Blob] in the spec.
 	//
 	// [Blob]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#custom-types
@@ -70,42 +88,207 @@ type (
 	KZGCommitment G1Point
 )
 
+// Equal reports whether c and other are the same commitment. Since the compressed encoding of a point is unique,
+// this is a comparison of the bytes.
+func (c KZGCommitment) Equal(other KZGCommitment) bool {
+	return c == other
+}
+
+// EqualCT is a version of [KZGCommitment.Equal] whose running time does not depend on the commitments.
+func (c KZGCommitment) EqualCT(other KZGCommitment) bool {
+	return subtle.ConstantTimeCompare(c[:], other[:]) == 1
+}
+
+// Equal reports whether p and other are the same proof. Since the compressed encoding of a point is unique, this is a
+// comparison of the bytes.
+func (p KZGProof) Equal(other KZGProof) bool {
+	return p == other
+}
+
+// EqualCT is a version of [KZGProof.Equal] whose running time does not depend on the proofs.
+func (p KZGProof) EqualCT(other KZGProof) bool {
+	return subtle.ConstantTimeCompare(p[:], other[:]) == 1
+}
+
+const (
+	// compressionFlag is the bit of the first byte of a serialized point which is set if the point is compressed.
+	compressionFlag = 0x80
+	// infinityFlag is the bit of the first byte of a serialized point which is set for the point at infinity.
+	infinityFlag = 0x40
+	// signFlag is the bit of the first byte of a compressed point which selects between the two possible y coordinates.
+	signFlag = 0x20
+)
+
 // SerializeG1Point converts a [bls12381.G1Affine] to [G1Point].
 func SerializeG1Point(affine bls12381.G1Affine) G1Point {
 	return affine.Bytes()
 }
 
 // deserializeG1Point converts a [G1Point] to the internal [bls12381.G1Affine] type. It will return an error if the
-// point is not on the group or if the point is not in the correct subgroup.
+// point is not on the group or, if subgroupCheck is true, if the point is not in the correct subgroup.
+//
+// The point at infinity must be encoded exactly as [PointAtInfinity]. In particular, gnark-crypto ignores the
+// infinity flag when the sign flag is also set, and would otherwise decode such an encoding as a point of small order.
 //
-// It implements [validate_kzg_g1].
+// With subgroupCheck set to true, it implements [validate_kzg_g1].
 //
 // [validate_kzg_g1]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#validate_kzg_g1
-func deserializeG1Point(serPoint G1Point) (bls12381.G1Affine, error) {
+func deserializeG1Point(serPoint G1Point, subgroupCheck bool) (bls12381.G1Affine, error) {
+	if serPoint[0]&infinityFlag != 0 && serPoint != PointAtInfinity {
+		return bls12381.G1Affine{}, ErrInvalidInfinityEncoding
+	}
+
 	var point bls12381.G1Affine
-	_, err := point.SetBytes(serPoint[:])
-	if err != nil {
+	d := bls12381.NewDecoder(bytes.NewReader(serPoint[:]), bls12381.NoSubgroupChecks())
+	if err := d.Decode(&point); err != nil {
 		return bls12381.G1Affine{}, err
 	}
+	if subgroupCheck && !point.IsInSubGroup() {
+		return bls12381.G1Affine{}, ErrPointNotInSubgroup
+	}
+	return point, nil
+}
+
+// Uncompressed returns the 96-byte uncompressed encoding of the commitment, ie the big-endian x and y coordinates, with
+// the infinity flag set for the point at infinity. It is twice as large as the compressed encoding, but decoding it
+// with [DeserializeCommitmentUncompressed] does not need the square root that decompression computes.
+//
+// The y coordinate is recovered by decompressing the commitment, which fails if the bytes are not a valid commitment.
+// Since a KZGCommitment may hold any bytes, for example when it was received from the network, an error is returned
+// in that case rather than a panic, for the same reasons as [DeserializeKZGCommitment]. The commitments computed by a
+// [Context] are always valid, so this never returns an error for them.
+func (c KZGCommitment) Uncompressed() ([UncompressedG1Size]byte, error) {
+	point, err := DeserializeKZGCommitment(c)
+	if err != nil {
+		return [UncompressedG1Size]byte{}, err
+	}
+	return point.RawBytes(), nil
+}
+
+// DeserializeCommitmentUncompressed deserializes a commitment in the encoding of [KZGCommitment.Uncompressed] into a
+// handle that can be passed to [Context.VerifyKZGProofWithCommitment].
+//
+// Returns [ErrCompressedPoint] if the compression flag or the sign flag is set, [ErrPointNotOnCurve] if the
+// coordinates are not a point on the curve, and [ErrPointNotInSubgroup] if the point is not in the prime-order
+// subgroup. The point at infinity must have every byte but the infinity flag set to zero, otherwise
+// [ErrInvalidInfinityEncoding] is returned.
+func DeserializeCommitmentUncompressed(serPoint [UncompressedG1Size]byte) (DeserializedKZGCommitment, error) {
+	// gnark-crypto reads an encoding with only the sign flag set as a compressed point, and ignores the y coordinate
+	if serPoint[0]&(compressionFlag|signFlag) != 0 {
+		return DeserializedKZGCommitment{}, ErrCompressedPoint
+	}
+	if serPoint[0]&infinityFlag != 0 && serPoint != [UncompressedG1Size]byte{infinityFlag} {
+		return DeserializedKZGCommitment{}, ErrInvalidInfinityEncoding
+	}
+
+	// The decoder does not check that uncompressed points are on the curve
+	var point bls12381.G1Affine
+	d := bls12381.NewDecoder(bytes.NewReader(serPoint[:]), bls12381.NoSubgroupChecks())
+	if err := d.Decode(&point); err != nil {
+		return DeserializedKZGCommitment{}, err
+	}
+	if !point.IsOnCurve() {
+		return DeserializedKZGCommitment{}, ErrPointNotOnCurve
+	}
+	if !point.IsInSubGroup() {
+		return DeserializedKZGCommitment{}, ErrPointNotInSubgroup
+	}
+	return DeserializedKZGCommitment{point: point}, nil
+}
+
+// SerializeG2Point converts a [bls12381.G2Affine] to [G2Point].
+func SerializeG2Point(affine bls12381.G2Affine) G2Point {
+	return affine.Bytes()
+}
+
+// DeserializeG2Point converts a compressed [G2Point], such as one of the G2 points of a trusted setup, to the
+// [bls12381.G2Affine] type. The point is checked to be on the curve and in the prime-order subgroup.
+//
+// Returns [ErrUncompressedPoint] if the compression flag is not set, since an uncompressed point does not fit in
+// [CompressedG2Size] bytes. As for G1 points, the point at infinity must be encoded exactly as the compression and
+// infinity flags followed by zeros, otherwise [ErrInvalidInfinityEncoding] is returned.
+func DeserializeG2Point(serPoint G2Point) (bls12381.G2Affine, error) {
+	if serPoint[0]&compressionFlag == 0 {
+		return bls12381.G2Affine{}, ErrUncompressedPoint
+	}
+	if serPoint[0]&infinityFlag != 0 && serPoint != SerializeG2Point(bls12381.G2Affine{}) {
+		return bls12381.G2Affine{}, ErrInvalidInfinityEncoding
+	}
+
+	var point bls12381.G2Affine
+	d := bls12381.NewDecoder(bytes.NewReader(serPoint[:]), bls12381.NoSubgroupChecks())
+	if err := d.Decode(&point); err != nil {
+		return bls12381.G2Affine{}, err
+	}
+	if !point.IsInSubGroup() {
+		return bls12381.G2Affine{}, ErrPointNotInSubgroup
+	}
 	return point, nil
 }
 
 // DeserializeKZGCommitment implements [bytes_to_kzg_commitment].
 //
+// Returns [ErrPointNotInSubgroup] if the commitment is a point on the curve that is not in the prime-order subgroup.
+// The point at infinity is accepted, but only in the encoding of [PointAtInfinity]; other encodings with the
+// infinity flag return [ErrInvalidInfinityEncoding].
+//
 // [bytes_to_kzg_commitment]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#bytes_to_kzg_commitment
 func DeserializeKZGCommitment(commitment KZGCommitment) (bls12381.G1Affine, error) {
-	return deserializeG1Point(G1Point(commitment))
+	return deserializeG1Point(G1Point(commitment), true)
 }
 
 // DeserializeKZGProof implements [bytes_to_kzg_proof].
 //
+// Returns [ErrPointNotInSubgroup] if the proof is a point on the curve that is not in the prime-order subgroup.
+// The point at infinity is accepted, but only in the encoding of [PointAtInfinity]; other encodings with the
+// infinity flag return [ErrInvalidInfinityEncoding].
+//
 // [bytes_to_kzg_proof]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#bytes_to_kzg_proof
 func DeserializeKZGProof(proof KZGProof) (bls12381.G1Affine, error) {
-	return deserializeG1Point(G1Point(proof))
+	return deserializeG1Point(G1Point(proof), true)
+}
+
+// DeserializedKZGCommitment is a [KZGCommitment] which was decompressed and validated by
+// [Context.DeserializeCommitment], so that it can be reused without paying for either again.
+type DeserializedKZGCommitment struct {
+	point bls12381.G1Affine
+}
+
+// DeserializeCommitment deserializes a commitment into a handle that can be passed to
+// [Context.VerifyKZGProofWithCommitment] any number of times.
+//
+// Like the other methods of the Context, the subgroup check is skipped if the Context was created with
+// [WithoutSubgroupChecks].
+func (c *Context) DeserializeCommitment(commitment KZGCommitment) (DeserializedKZGCommitment, error) {
+	if c.closed {
+		return DeserializedKZGCommitment{}, ErrContextClosed
+	}
+
+	point, err := c.deserializeKZGCommitment(commitment)
+	if err != nil {
+		return DeserializedKZGCommitment{}, err
+	}
+	return DeserializedKZGCommitment{point: point}, nil
+}
+
+// deserializeKZGCommitment deserializes a commitment, only checking subgroup membership if
+// the Context was not created with [WithoutSubgroupChecks].
+func (c *Context) deserializeKZGCommitment(commitment KZGCommitment) (bls12381.G1Affine, error) {
+	return deserializeG1Point(G1Point(commitment), !c.skipSubgroupChecks)
+}
+
+// deserializeKZGProof deserializes a proof, only checking subgroup membership if
+// the Context was not created with [WithoutSubgroupChecks].
+func (c *Context) deserializeKZGProof(proof KZGProof) (bls12381.G1Affine, error) {
+	return deserializeG1Point(G1Point(proof), !c.skipSubgroupChecks)
 }
 
 // DeserializeBlob implements [blob_to_polynomial].
 //
+// Every field element must be canonical, ie strictly less than the scalar field modulus, so that a blob has a single
+// byte encoding. Otherwise, the returned error contains the index of the offending field element and wraps
+// [ErrNonCanonicalScalar].
+//
 // [blob_to_polynomial]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#blob_to_polynomial
 func DeserializeBlob(blob Blob) (kzg.Polynomial, error) {
 	poly := make(kzg.Polynomial, ScalarsPerBlob)
@@ -114,13 +297,77 @@ func DeserializeBlob(blob Blob) (kzg.Polynomial, error) {
 		serScalar := (*Scalar)(chunk)
 		scalar, err := DeserializeScalar(*serScalar)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("field element %d: %w", i, err)
 		}
 		poly[i] = scalar
 	}
 	return poly, nil
 }
 
+// PadToBlob returns the blob whose first len(scalars) field elements are the given scalars, in order, and whose
+// remaining field elements are zero. Each scalar is copied as-is, as a 32 byte big-endian integer.
+//
+// The polynomial of the blob is the one which takes the given values at the first len(scalars) roots of the domain,
+// in the bit-reversed order of [Context.DomainRoots], and zero at the other roots. Its commitment is the same as
+// [Context.CommitToScalars] returns for the scalars.
+//
+// Returns an error wrapping [ErrInvalidPolynomialSize] if there are more than [ScalarsPerBlob] scalars. If a scalar is
+// not canonical, the returned error contains its index and wraps [ErrNonCanonicalScalar].
+func PadToBlob(scalars []Scalar) (Blob, error) {
+	if len(scalars) > ScalarsPerBlob {
+		return Blob{}, fmt.Errorf("%w: got %d scalars, expected at most %d", ErrInvalidPolynomialSize, len(scalars), ScalarsPerBlob)
+	}
+
+	var blob Blob
+	for i := 0; i < len(scalars); i++ {
+		if _, err := DeserializeScalar(scalars[i]); err != nil {
+			return Blob{}, fmt.Errorf("scalar %d: %w", i, err)
+		}
+		copy(blob[i*SerializedScalarSize:(i+1)*SerializedScalarSize], scalars[i][:])
+	}
+	return blob, nil
+}
+
+// BlobFromBytes returns the blob held in b, which must have exactly ScalarsPerBlob * 32 bytes, otherwise the returned
+// error contains the length of b and wraps [ErrInvalidBlobLength]. The returned blob shares its memory with b, and
+// its field elements are not checked to be canonical until it is deserialized.
+func BlobFromBytes(b []byte) (*Blob, error) {
+	if len(b) != len(Blob{}) {
+		return nil, fmt.Errorf("%w: got %d bytes, expected %d", ErrInvalidBlobLength, len(b), len(Blob{}))
+	}
+	return (*Blob)(b), nil
+}
+
+// FieldElement returns the field element at index i of the blob, as a 32 byte big-endian integer. The bytes are
+// returned as-is, so the scalar is not necessarily canonical; [DeserializeScalar] checks this.
+//
+// Returns an error wrapping [ErrScalarIndexOutOfRange] if i is not in [0, [ScalarsPerBlob]).
+func (blob *Blob) FieldElement(i int) (Scalar, error) {
+	if i < 0 || i >= ScalarsPerBlob {
+		return Scalar{}, fmt.Errorf("%w: got %d", ErrScalarIndexOutOfRange, i)
+	}
+
+	var s Scalar
+	copy(s[:], blob[i*SerializedScalarSize:(i+1)*SerializedScalarSize])
+	return s, nil
+}
+
+// SetFieldElement sets the field element at index i of the blob to the scalar s, given as a 32 byte big-endian integer.
+//
+// Returns an error wrapping [ErrScalarIndexOutOfRange] if i is not in [0, [ScalarsPerBlob]), and
+// [ErrNonCanonicalScalar] if s is not canonical. The blob is left unchanged on error.
+func (blob *Blob) SetFieldElement(i int, s Scalar) error {
+	if i < 0 || i >= ScalarsPerBlob {
+		return fmt.Errorf("%w: got %d", ErrScalarIndexOutOfRange, i)
+	}
+	if _, err := DeserializeScalar(s); err != nil {
+		return err
+	}
+
+	copy(blob[i*SerializedScalarSize:(i+1)*SerializedScalarSize], s[:])
+	return nil
+}
+
 // DeserializeScalar implements [bytes_to_bls_field].
 //
 // Note: Returns an error if the scalar is not in the range [0, p-1] (inclusive) where `p` is the prime associated with the scalar field.
@@ -134,11 +381,99 @@ func DeserializeScalar(serScalar Scalar) (fr.Element, error) {
 	return scalar, nil
 }
 
+// DeserializeScalarConstantTime is a version of [DeserializeScalar] whose running time does not depend on the value of
+// the scalar, other than on whether it is canonical. It should be used when the scalar is secret.
+func DeserializeScalarConstantTime(serScalar Scalar) (fr.Element, error) {
+	scalar, err := utils.ReduceCanonicalBigEndianConstantTime(serScalar[:])
+	if err != nil {
+		return fr.Element{}, ErrNonCanonicalScalar
+	}
+	return scalar, nil
+}
+
+// deserializeEvaluationPoint deserializes a point at which a blob is evaluated, in constant time if
+// the Context was created with [WithConstantTimeScalars].
+func (c *Context) deserializeEvaluationPoint(serScalar Scalar) (fr.Element, error) {
+	if c.constantTimeScalars {
+		return DeserializeScalarConstantTime(serScalar)
+	}
+	return DeserializeScalar(serScalar)
+}
+
 // SerializeScalar converts a [fr.Element] to [Scalar].
 func SerializeScalar(element fr.Element) Scalar {
 	return element.Bytes()
 }
 
+// SerializeScalarBE converts a [fr.Element] to a [Scalar], as a 32 byte big-endian integer. This is the encoding of
+// EIP-4844, and is the same as [SerializeScalar].
+func SerializeScalarBE(element fr.Element) Scalar {
+	return SerializeScalar(element)
+}
+
+// SerializeScalarLE converts a [fr.Element] to a 32 byte little-endian integer. The result is not a [Scalar] as used
+// by the rest of this package, which is big-endian, and is only meant for other libraries.
+func SerializeScalarLE(element fr.Element) [SerializedScalarSize]byte {
+	serScalar := SerializeScalar(element)
+	reverseBytes(serScalar[:])
+	return serScalar
+}
+
+// DeserializeScalarBE deserializes a 32 byte big-endian integer. This is the encoding of EIP-4844, and is the same as
+// [DeserializeScalar].
+//
+// Returns [ErrNonCanonicalScalar] if the integer is not less than the scalar field modulus.
+func DeserializeScalarBE(serScalar Scalar) (fr.Element, error) {
+	return DeserializeScalar(serScalar)
+}
+
+// DeserializeScalarLE deserializes a 32 byte little-endian integer, as written by [SerializeScalarLE]. To pass it to
+// the rest of this package, convert the result to a big-endian [Scalar] with [SerializeScalarBE].
+//
+// Returns [ErrNonCanonicalScalar] if the integer is not less than the scalar field modulus.
+func DeserializeScalarLE(serScalar [SerializedScalarSize]byte) (fr.Element, error) {
+	reverseBytes(serScalar[:])
+	return DeserializeScalar(serScalar)
+}
+
+// reverseBytes reverses b in place.
+func reverseBytes(b []byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+}
+
+// ScalarFromBytesReduce interprets b as a big-endian integer of any length, such as a 32 or 48-byte hash, and reduces
+// it modulo the scalar field modulus. This is how [hash_to_bls_field] turns a digest into a challenge.
+//
+// [hash_to_bls_field]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#hash_to_bls_field
+func ScalarFromBytesReduce(b []byte) Scalar {
+	var scalar fr.Element
+	scalar.SetBytes(b)
+	return SerializeScalar(scalar)
+}
+
+// ScalarFromBytesCanonical interprets b as a big-endian integer of any length and returns it as a [Scalar], without
+// reducing it.
+//
+// Returns [ErrNonCanonicalScalar] if the integer is not strictly less than the scalar field modulus.
+func ScalarFromBytesCanonical(b []byte) (Scalar, error) {
+	// Leading zeroes do not change the integer, so inputs longer than a scalar are accepted if they are small enough
+	for len(b) > SerializedScalarSize {
+		if b[0] != 0 {
+			return Scalar{}, ErrNonCanonicalScalar
+		}
+		b = b[1:]
+	}
+
+	var serScalar Scalar
+	copy(serScalar[SerializedScalarSize-len(b):], b)
+	if _, err := DeserializeScalar(serScalar); err != nil {
+		return Scalar{}, err
+	}
+	return serScalar, nil
+}
+
 // SerializePoly converts a [kzg.Polynomial] to [Blob].
 //
 // Note: This method is never used in the API because we always expect a byte array and will never receive deserialized
@@ -2,9 +2,13 @@ package gokzg4844_test
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
 	"testing"
 
 	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
 	"github.com/crate-crypto/go-kzg-4844/internal/kzg"
@@ -23,6 +27,238 @@ func TestG1RoundTripSmoke(t *testing.T) {
 	}
 }
 
+func TestDeserializeG1NotInSubgroup(t *testing.T) {
+	point := g1PointNotInSubgroup()
+	serPoint := gokzg4844.SerializeG1Point(point)
+
+	_, err := gokzg4844.DeserializeKZGCommitment(gokzg4844.KZGCommitment(serPoint))
+	require.ErrorIs(t, err, gokzg4844.ErrPointNotInSubgroup)
+	_, err = gokzg4844.DeserializeKZGProof(gokzg4844.KZGProof(serPoint))
+	require.ErrorIs(t, err, gokzg4844.ErrPointNotInSubgroup)
+}
+
+func TestDeserializePointAtInfinity(t *testing.T) {
+	point, err := gokzg4844.DeserializeKZGCommitment(gokzg4844.PointAtInfinity)
+	require.NoError(t, err)
+	require.True(t, point.IsInfinity())
+	point, err = gokzg4844.DeserializeKZGProof(gokzg4844.PointAtInfinity)
+	require.NoError(t, err)
+	require.True(t, point.IsInfinity())
+	commitment, err := ctx.DeserializeCommitment(gokzg4844.PointAtInfinity)
+	require.NoError(t, err)
+
+	// The identity commitment opens to zero everywhere, with the identity as the proof
+	zero := gokzg4844.Scalar{}
+	for _, inputPoint := range []gokzg4844.Scalar{GetRandFieldElement(6), ctx.DomainRoots()[5]} {
+		require.NoError(t, ctx.VerifyKZGProof(gokzg4844.PointAtInfinity, inputPoint, zero, gokzg4844.PointAtInfinity))
+		require.NoError(t, ctx.VerifyKZGProofWithCommitment(commitment, inputPoint, zero, gokzg4844.PointAtInfinity))
+
+		one := gokzg4844.SerializeScalar(fr.One())
+		err = ctx.VerifyKZGProof(gokzg4844.PointAtInfinity, inputPoint, one, gokzg4844.PointAtInfinity)
+		require.ErrorIs(t, err, gokzg4844.ErrVerifyOpeningProof)
+	}
+
+	// Any other encoding with the infinity flag is rejected, even without subgroup checks. With the sign flag also
+	// set, gnark-crypto would decode a point of small order.
+	uncheckedCtx, err := gokzg4844.NewContext4096Insecure1337(gokzg4844.WithoutSubgroupChecks())
+	require.NoError(t, err)
+	withSign := gokzg4844.PointAtInfinity
+	withSign[0] |= 0x20
+	withData := gokzg4844.PointAtInfinity
+	withData[47] = 1
+	for _, serPoint := range [][48]byte{withSign, withData} {
+		_, err = gokzg4844.DeserializeKZGCommitment(serPoint)
+		require.ErrorIs(t, err, gokzg4844.ErrInvalidInfinityEncoding)
+		_, err = gokzg4844.DeserializeKZGProof(serPoint)
+		require.ErrorIs(t, err, gokzg4844.ErrInvalidInfinityEncoding)
+		_, err = uncheckedCtx.DeserializeCommitment(serPoint)
+		require.ErrorIs(t, err, gokzg4844.ErrInvalidInfinityEncoding)
+	}
+
+	// Without the compression flag, the encoding is rejected as well
+	uncompressed := gokzg4844.PointAtInfinity
+	uncompressed[0] = 0x40
+	_, err = gokzg4844.DeserializeKZGCommitment(uncompressed)
+	require.Error(t, err)
+}
+
+func TestDeserializeG2Point(t *testing.T) {
+	_, _, _, genG2 := bls12381.Generators()
+	var point bls12381.G2Affine
+	point.ScalarMultiplication(&genG2, big.NewInt(1337))
+	for _, expected := range []bls12381.G2Affine{genG2, point, {}} {
+		got, err := gokzg4844.DeserializeG2Point(gokzg4844.SerializeG2Point(expected))
+		require.NoError(t, err)
+		require.True(t, got.Equal(&expected))
+	}
+
+	serPoint := gokzg4844.SerializeG2Point(point)
+	uncompressed := serPoint
+	uncompressed[0] &^= 0x80
+	_, err := gokzg4844.DeserializeG2Point(uncompressed)
+	require.ErrorIs(t, err, gokzg4844.ErrUncompressedPoint)
+
+	infinity := gokzg4844.SerializeG2Point(bls12381.G2Affine{})
+	withSign := infinity
+	withSign[0] |= 0x20
+	withData := infinity
+	withData[95] = 1
+	for _, serPoint := range []gokzg4844.G2Point{withSign, withData} {
+		_, err = gokzg4844.DeserializeG2Point(serPoint)
+		require.ErrorIs(t, err, gokzg4844.ErrInvalidInfinityEncoding)
+	}
+
+	_, err = gokzg4844.DeserializeG2Point(gokzg4844.SerializeG2Point(g2PointNotInSubgroup()))
+	require.ErrorIs(t, err, gokzg4844.ErrPointNotInSubgroup)
+
+}
+
+// g2PointNotInSubgroup returns a point on the G2 curve, y^2 = x^3 + 4(1 + u), which is not in the prime-order subgroup.
+func g2PointNotInSubgroup() bls12381.G2Affine {
+	var point bls12381.G2Affine
+	for x := uint64(1); ; x++ {
+		point.X.A0.SetUint64(x)
+		point.X.A1.SetZero()
+		rhs := point.X
+		rhs.Square(&point.X).Mul(&rhs, &point.X)
+		rhs.A0.Add(&rhs.A0, new(fp.Element).SetUint64(4))
+		rhs.A1.Add(&rhs.A1, new(fp.Element).SetUint64(4))
+		if rhs.Legendre() != 1 {
+			continue
+		}
+		point.Y.Sqrt(&rhs)
+		if point.IsOnCurve() && !point.IsInSubGroup() {
+			return point
+		}
+	}
+}
+
+func TestCommitmentUncompressed(t *testing.T) {
+	blob := GetRandBlob(13)
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	inputPoint := GetRandFieldElement(14)
+	proof, claimedValue, err := ctx.ComputeKZGProof(blob, inputPoint, NumGoRoutines)
+	require.NoError(t, err)
+
+	serPoint, err := commitment.Uncompressed()
+	require.NoError(t, err)
+	deserializedCommitment, err := gokzg4844.DeserializeCommitmentUncompressed(serPoint)
+	require.NoError(t, err)
+	require.NoError(t, ctx.VerifyKZGProofWithCommitment(deserializedCommitment, inputPoint, claimedValue, proof))
+
+	infinity, err := gokzg4844.KZGCommitment(gokzg4844.PointAtInfinity).Uncompressed()
+	require.NoError(t, err)
+	require.Equal(t, [gokzg4844.UncompressedG1Size]byte{0x40}, infinity)
+	_, err = gokzg4844.DeserializeCommitmentUncompressed(infinity)
+	require.NoError(t, err)
+
+	// Commitments which are not valid points have no uncompressed encoding
+	serNotInSubgroup, err := gokzg4844.KZGCommitment(gokzg4844.SerializeG1Point(g1PointNotInSubgroup())).Uncompressed()
+	require.ErrorIs(t, err, gokzg4844.ErrPointNotInSubgroup)
+	require.Equal(t, [gokzg4844.UncompressedG1Size]byte{}, serNotInSubgroup)
+	infinityWithData := gokzg4844.KZGCommitment(gokzg4844.PointAtInfinity)
+	infinityWithData[47] = 1
+	_, err = infinityWithData.Uncompressed()
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidInfinityEncoding)
+	notOnCurveCommitment := commitment
+	for {
+		notOnCurveCommitment[47]++
+		if _, err := gokzg4844.DeserializeKZGCommitment(notOnCurveCommitment); err != nil {
+			break
+		}
+	}
+	_, err = notOnCurveCommitment.Uncompressed()
+	require.Error(t, err)
+
+	for _, flag := range []byte{0x80, 0x20} {
+		withFlag := serPoint
+		withFlag[0] |= flag
+		_, err = gokzg4844.DeserializeCommitmentUncompressed(withFlag)
+		require.ErrorIs(t, err, gokzg4844.ErrCompressedPoint)
+	}
+	withData := infinity
+	withData[95] = 1
+	_, err = gokzg4844.DeserializeCommitmentUncompressed(withData)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidInfinityEncoding)
+	notOnCurve := serPoint
+	notOnCurve[95] ^= 1
+	_, err = gokzg4844.DeserializeCommitmentUncompressed(notOnCurve)
+	require.ErrorIs(t, err, gokzg4844.ErrPointNotOnCurve)
+	notInSubgroup := g1PointNotInSubgroup()
+	_, err = gokzg4844.DeserializeCommitmentUncompressed(notInSubgroup.RawBytes())
+	require.ErrorIs(t, err, gokzg4844.ErrPointNotInSubgroup)
+}
+
+func TestVerifyKZGProofSubgroupCheckOption(t *testing.T) {
+	blob := GetRandBlob(5)
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	inputPoint := GetRandFieldElement(5)
+	_, claimedValue, err := ctx.ComputeKZGProof(blob, inputPoint, NumGoRoutines)
+	require.NoError(t, err)
+	badProof := gokzg4844.KZGProof(gokzg4844.SerializeG1Point(g1PointNotInSubgroup()))
+
+	// By default, the point is rejected before any pairing is computed.
+	err = ctx.VerifyKZGProof(commitment, inputPoint, claimedValue, badProof)
+	require.ErrorIs(t, err, gokzg4844.ErrPointNotInSubgroup)
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidProof)
+
+	// With the checks disabled, the point is accepted by deserialization,
+	// but the proof is still invalid.
+	uncheckedCtx, err := gokzg4844.NewContext4096Insecure1337(gokzg4844.WithoutSubgroupChecks())
+	require.NoError(t, err)
+	err = uncheckedCtx.VerifyKZGProof(commitment, inputPoint, claimedValue, badProof)
+	require.Error(t, err)
+	require.False(t, errors.Is(err, gokzg4844.ErrPointNotInSubgroup))
+}
+
+// g1PointNotInSubgroup returns a point that is on the curve, but not in the prime-order subgroup.
+func g1PointNotInSubgroup() bls12381.G1Affine {
+	var point bls12381.G1Affine
+	var four fp.Element
+	four.SetUint64(4)
+	for x := uint64(1); ; x++ {
+		// y^2 = x^3 + 4
+		point.X.SetUint64(x)
+		var rhs fp.Element
+		rhs.Square(&point.X).Mul(&rhs, &point.X).Add(&rhs, &four)
+		if point.Y.Sqrt(&rhs) == nil {
+			continue
+		}
+		if !point.IsInSubGroup() {
+			return point
+		}
+	}
+}
+
+func TestCommitmentAndProofEqual(t *testing.T) {
+	blob := GetRandBlob(29)
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	proof, err := ctx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+
+	// Re-serializing a deserialized point gives an equal encoding
+	point, err := gokzg4844.DeserializeKZGCommitment(commitment)
+	require.NoError(t, err)
+	reserialized := gokzg4844.KZGCommitment(gokzg4844.SerializeG1Point(point))
+	require.True(t, commitment.Equal(reserialized))
+	require.True(t, commitment.EqualCT(reserialized))
+
+	point, err = gokzg4844.DeserializeKZGProof(proof)
+	require.NoError(t, err)
+	reserializedProof := gokzg4844.KZGProof(gokzg4844.SerializeG1Point(point))
+	require.True(t, proof.Equal(reserializedProof))
+	require.True(t, proof.EqualCT(reserializedProof))
+
+	// The proof is a different point from the commitment
+	require.False(t, commitment.Equal(gokzg4844.KZGCommitment(proof)))
+	require.False(t, commitment.EqualCT(gokzg4844.KZGCommitment(proof)))
+	require.False(t, proof.Equal(gokzg4844.KZGProof(commitment)))
+	require.False(t, proof.EqualCT(gokzg4844.KZGProof(commitment)))
+}
+
 func TestSerializePolyNotZero(t *testing.T) {
 	// Check that blobs are not all zeroes
 	// This would indicate that serialization
@@ -58,6 +294,199 @@ func TestSerializePolyRoundTrip(t *testing.T) {
 	assertPolyNotEqual(t, expectedPolyA, gotPolyB)
 }
 
+func TestDeserializeScalarConstantTime(t *testing.T) {
+	for i := int64(0); i < 10; i++ {
+		serScalar := GetRandFieldElement(i)
+		expected, err := gokzg4844.DeserializeScalar(serScalar)
+		require.NoError(t, err)
+		got, err := gokzg4844.DeserializeScalarConstantTime(serScalar)
+		require.NoError(t, err)
+		require.True(t, expected.Equal(&got))
+	}
+
+	_, err := gokzg4844.DeserializeScalarConstantTime(gokzg4844.BlsModulus)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+	_, err = gokzg4844.DeserializeScalarConstantTime(nonCanonicalScalar(24))
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+}
+
+func TestDeserializeBlobNonCanonical(t *testing.T) {
+	blob := GetRandBlob(22)
+	_, err := gokzg4844.DeserializeBlob(blob)
+	require.NoError(t, err)
+
+	// The modulus itself is the smallest non-canonical encoding
+	for _, badIndex := range []int{0, 2049, gokzg4844.ScalarsPerBlob - 1} {
+		blobBad := blob
+		modifyBlob(&blobBad, gokzg4844.BlsModulus, badIndex*gokzg4844.SerializedScalarSize)
+		_, err = gokzg4844.DeserializeBlob(blobBad)
+		require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+		require.ErrorContains(t, err, fmt.Sprintf("field element %d:", badIndex))
+
+		_, err = ctx.BlobToKZGCommitment(blobBad, NumGoRoutines)
+		require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+	}
+}
+
+func TestPadToBlob(t *testing.T) {
+	scalars := make([]gokzg4844.Scalar, 100)
+	for i := 0; i < len(scalars); i++ {
+		scalars[i] = GetRandFieldElement(int64(70 + i))
+	}
+
+	blob, err := gokzg4844.PadToBlob(scalars)
+	require.NoError(t, err)
+	for i := 0; i < gokzg4844.ScalarsPerBlob; i++ {
+		var expected gokzg4844.Scalar
+		if i < len(scalars) {
+			expected = scalars[i]
+		}
+		require.Equal(t, expected[:], blob[i*gokzg4844.SerializedScalarSize:(i+1)*gokzg4844.SerializedScalarSize])
+	}
+
+	// The commitment to the padded blob is the commitment to the scalars
+	expected, err := ctx.CommitToScalars(scalars, NumGoRoutines)
+	require.NoError(t, err)
+	got, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expected, got)
+
+	blob, err = gokzg4844.PadToBlob(nil)
+	require.NoError(t, err)
+	require.Equal(t, gokzg4844.Blob{}, blob)
+
+	_, err = gokzg4844.PadToBlob(make([]gokzg4844.Scalar, gokzg4844.ScalarsPerBlob+1))
+	require.ErrorIs(t, err, gokzg4844.ErrInvalidPolynomialSize)
+
+	scalars[42] = gokzg4844.BlsModulus
+	_, err = gokzg4844.PadToBlob(scalars)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+	require.ErrorContains(t, err, "scalar 42:")
+}
+
+func TestBlobFromBytes(t *testing.T) {
+	blob := GetRandBlob(71)
+	data := append([]byte(nil), blob[:]...)
+	got, err := gokzg4844.BlobFromBytes(data)
+	require.NoError(t, err)
+	require.Equal(t, blob, *got)
+
+	for _, length := range []int{0, len(data) - 1, len(data) + 1} {
+		_, err = gokzg4844.BlobFromBytes(make([]byte, length))
+		require.ErrorIs(t, err, gokzg4844.ErrInvalidBlobLength)
+		require.ErrorContains(t, err, fmt.Sprintf("got %d bytes", length))
+	}
+}
+
+func TestBlobFieldElement(t *testing.T) {
+	var blob gokzg4844.Blob
+	for _, i := range []int{0, 1, 2047, gokzg4844.ScalarsPerBlob - 1} {
+		s := GetRandFieldElement(int64(i))
+		require.NoError(t, blob.SetFieldElement(i, s))
+		got, err := blob.FieldElement(i)
+		require.NoError(t, err)
+		require.Equal(t, gokzg4844.Scalar(s), got)
+		require.Equal(t, s[:], blob[i*gokzg4844.SerializedScalarSize:(i+1)*gokzg4844.SerializedScalarSize])
+	}
+
+	// Building a blob element by element gives the same blob as PadToBlob
+	scalars := make([]gokzg4844.Scalar, 10)
+	blob = gokzg4844.Blob{}
+	for i := range scalars {
+		scalars[i] = GetRandFieldElement(int64(100 + i))
+		require.NoError(t, blob.SetFieldElement(i, scalars[i]))
+	}
+	expected, err := gokzg4844.PadToBlob(scalars)
+	require.NoError(t, err)
+	require.Equal(t, expected, blob)
+
+	for _, i := range []int{-1, gokzg4844.ScalarsPerBlob} {
+		_, err := blob.FieldElement(i)
+		require.ErrorIs(t, err, gokzg4844.ErrScalarIndexOutOfRange)
+		require.ErrorIs(t, blob.SetFieldElement(i, gokzg4844.Scalar{}), gokzg4844.ErrScalarIndexOutOfRange)
+	}
+
+	// A non-canonical scalar is rejected, and leaves the blob unchanged
+	require.ErrorIs(t, blob.SetFieldElement(3, gokzg4844.BlsModulus), gokzg4844.ErrNonCanonicalScalar)
+	require.Equal(t, expected, blob)
+
+	// Non-canonical field elements are still returned, as they are stored
+	modifyBlob(&blob, gokzg4844.BlsModulus, 5*gokzg4844.SerializedScalarSize)
+	got, err := blob.FieldElement(5)
+	require.NoError(t, err)
+	require.Equal(t, gokzg4844.Scalar(gokzg4844.BlsModulus), got)
+}
+
+func TestScalarFromBytes(t *testing.T) {
+	// The modulus reduces to zero, and the modulus plus one to one
+	modulusPlusOne := gokzg4844.BlsModulus
+	modulusPlusOne[gokzg4844.SerializedScalarSize-1]++
+	require.Equal(t, gokzg4844.Scalar{}, gokzg4844.ScalarFromBytesReduce(gokzg4844.BlsModulus[:]))
+	require.Equal(t, gokzg4844.SerializeScalar(fr.One()), gokzg4844.ScalarFromBytesReduce(modulusPlusOne[:]))
+
+	// A wide input is reduced as a single big-endian integer: 2^256 * 1 + 1
+	wide := make([]byte, 48)
+	wide[15] = 1
+	wide[47] = 1
+	var expected fr.Element
+	expected.SetBigInt(new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1)))
+	require.Equal(t, gokzg4844.SerializeScalar(expected), gokzg4844.ScalarFromBytesReduce(wide))
+
+	// Canonical inputs are returned unchanged, whatever their length
+	serScalar := gokzg4844.Scalar(GetRandFieldElement(25))
+	got, err := gokzg4844.ScalarFromBytesCanonical(serScalar[:])
+	require.NoError(t, err)
+	require.Equal(t, serScalar, got)
+	require.Equal(t, serScalar, gokzg4844.ScalarFromBytesReduce(serScalar[:]))
+
+	got, err = gokzg4844.ScalarFromBytesCanonical(append(make([]byte, 16), serScalar[:]...))
+	require.NoError(t, err)
+	require.Equal(t, serScalar, got)
+
+	got, err = gokzg4844.ScalarFromBytesCanonical([]byte{1, 2})
+	require.NoError(t, err)
+	require.Equal(t, gokzg4844.SerializeScalar(fr.NewElement(258)), got)
+
+	_, err = gokzg4844.ScalarFromBytesCanonical(gokzg4844.BlsModulus[:])
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+	_, err = gokzg4844.ScalarFromBytesCanonical(wide)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+}
+
+func FuzzDeserializeBlob(f *testing.F) {
+	modulus := gokzg4844.BlsModulus
+	f.Add(uint32(0), []byte{})
+	f.Add(uint32(0), modulus[:])
+	f.Add(uint32(1234*gokzg4844.SerializedScalarSize), modulus[:])
+	f.Add(uint32(len(gokzg4844.Blob{})-1), []byte{0xff})
+
+	f.Fuzz(func(t *testing.T, offset uint32, data []byte) {
+		// Write the data into an otherwise zero blob, starting at the offset
+		var blob gokzg4844.Blob
+		start := int(offset % uint32(len(blob)))
+		copy(blob[start:], data)
+
+		// Find the first non-canonical field element, if any
+		badIndex := -1
+		for i := 0; i < gokzg4844.ScalarsPerBlob && badIndex == -1; i++ {
+			var serScalar gokzg4844.Scalar
+			copy(serScalar[:], blob[i*gokzg4844.SerializedScalarSize:])
+			if _, err := gokzg4844.DeserializeScalar(serScalar); err != nil {
+				badIndex = i
+			}
+		}
+
+		poly, err := gokzg4844.DeserializeBlob(blob)
+		if badIndex != -1 {
+			require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+			require.ErrorContains(t, err, fmt.Sprintf("field element %d:", badIndex))
+			return
+		}
+		require.NoError(t, err)
+		require.Equal(t, blob, gokzg4844.SerializePoly(poly))
+	})
+}
+
 // Check element-wise that each evaluation in the polynomial is the same
 func assertPolyEqual(t *testing.T, lhs, rhs kzg.Polynomial) {
 	t.Helper()
@@ -119,3 +548,40 @@ func randPoly4096() kzg.Polynomial {
 	}
 	return poly
 }
+
+func TestScalarEndianness(t *testing.T) {
+	var element fr.Element
+	element.SetUint64(0x0102)
+
+	be := gokzg4844.SerializeScalarBE(element)
+	le := gokzg4844.SerializeScalarLE(element)
+	require.Equal(t, gokzg4844.SerializeScalar(element), be)
+	require.Equal(t, byte(0x02), be[31])
+	require.Equal(t, byte(0x01), be[30])
+	require.Equal(t, byte(0x02), le[0])
+	require.Equal(t, byte(0x01), le[1])
+
+	for i := int64(0); i < 10; i++ {
+		serScalar := GetRandFieldElement(i)
+		expected, err := gokzg4844.DeserializeScalar(serScalar)
+		require.NoError(t, err)
+
+		got, err := gokzg4844.DeserializeScalarBE(serScalar)
+		require.NoError(t, err)
+		require.True(t, expected.Equal(&got))
+
+		got, err = gokzg4844.DeserializeScalarLE(gokzg4844.SerializeScalarLE(expected))
+		require.NoError(t, err)
+		require.True(t, expected.Equal(&got))
+	}
+
+	// The modulus is not canonical in either encoding
+	_, err := gokzg4844.DeserializeScalarBE(gokzg4844.BlsModulus)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+	modulusLE := gokzg4844.BlsModulus
+	for i, j := 0, len(modulusLE)-1; i < j; i, j = i+1, j-1 {
+		modulusLE[i], modulusLE[j] = modulusLE[j], modulusLE[i]
+	}
+	_, err = gokzg4844.DeserializeScalarLE(modulusLE)
+	require.ErrorIs(t, err, gokzg4844.ErrNonCanonicalScalar)
+}
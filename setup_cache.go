@@ -0,0 +1,192 @@
+package gokzg4844
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/crate-crypto/go-kzg-4844/internal/kzg"
+)
+
+// setupCacheVersion is the version of the binary format written by [Context.SerializeSetup].
+//
+// It must be bumped whenever the layout changes, so that stale caches are rejected.
+const setupCacheVersion = 2
+
+// SerializeSetup writes the trusted setup of the Context to w in a compact binary format, which can be read back with
+// [LoadContextFromBinary]. The monomial G1 points are only written if the Context keeps them, see [WithMonomialSetup].
+//
+// The layout is:
+//   - a version byte
+//   - the number of lagrange G1 points, as a big-endian uint32, followed by the points in order
+//   - the degree-0 G1 element
+//   - the number of monomial G1 points (possibly zero), as a big-endian uint32, followed by the points in order
+//   - the number of G2 points, as a big-endian uint32, followed by the points in order
+//   - the sha256 digest of everything above
+//
+// All points are uncompressed.
+func (c *Context) SerializeSetup(w io.Writer) error {
+	if err := c.checkCanProve(); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte(setupCacheVersion)
+
+	// The commit key is stored in bit-reversed order in the Context
+	lagrangeG1 := kzg.CommitKey{G1: make([]bls12381.G1Affine, len(c.commitKey.G1))}
+	copy(lagrangeG1.G1, c.commitKey.G1)
+	lagrangeG1.ReversePoints()
+
+	writeG1Points(&buf, lagrangeG1.G1)
+	genG1 := c.openKey.GenG1.RawBytes()
+	buf.Write(genG1[:])
+	writeG1Points(&buf, c.monomialG1)
+
+	writeLength(&buf, len(c.g2Points))
+	for i := 0; i < len(c.g2Points); i++ {
+		raw := c.g2Points[i].RawBytes()
+		buf.Write(raw[:])
+	}
+
+	checksum := sha256.Sum256(buf.Bytes())
+	buf.Write(checksum[:])
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeLength writes n as a big-endian uint32.
+func writeLength(buf *bytes.Buffer, n int) {
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(n))
+	buf.Write(length[:])
+}
+
+// writeG1Points writes the number of points followed by the uncompressed points.
+func writeG1Points(buf *bytes.Buffer, points []bls12381.G1Affine) {
+	writeLength(buf, len(points))
+	for i := 0; i < len(points); i++ {
+		raw := points[i].RawBytes()
+		buf.Write(raw[:])
+	}
+}
+
+// LoadContextFromBinary creates a new context object from the output of [Context.SerializeSetup].
+//
+// Since the cached points were validated when the original Context was created, no subgroup checks are
+// performed. Instead, the version byte and the checksum are used to reject caches which were corrupted or
+// written by an incompatible version of this library.
+func LoadContextFromBinary(r io.Reader, opts ...ContextOption) (*Context, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < 1+sha256.Size {
+		return nil, ErrSetupCacheCorrupted
+	}
+	payload, checksum := data[:len(data)-sha256.Size], data[len(data)-sha256.Size:]
+	expectedChecksum := sha256.Sum256(payload)
+	if !bytes.Equal(checksum, expectedChecksum[:]) {
+		return nil, ErrSetupCacheCorrupted
+	}
+
+	if payload[0] != setupCacheVersion {
+		return nil, fmt.Errorf("%w: got version %d, expected %d", ErrSetupCacheVersion, payload[0], setupCacheVersion)
+	}
+
+	body := bytes.NewReader(payload[1:])
+	dec := bls12381.NewDecoder(body, bls12381.NoSubgroupChecks())
+
+	var setup parsedTrustedSetup
+	setup.lagrangeG1, err = readG1Points(body, dec)
+	if err != nil {
+		return nil, err
+	}
+	if len(setup.lagrangeG1) != ScalarsPerBlob {
+		return nil, fmt.Errorf("%w: got %d G1 points, expected %d", ErrTrustedSetupLength, len(setup.lagrangeG1), ScalarsPerBlob)
+	}
+	if err := dec.Decode(&setup.genG1); err != nil {
+		return nil, err
+	}
+	setup.monomialG1, err = readG1Points(body, dec)
+	if err != nil {
+		return nil, err
+	}
+
+	numG2, err := readLength(body)
+	if err != nil {
+		return nil, err
+	}
+	if numG2 < 2 {
+		return nil, fmt.Errorf("%w: got %d G2 points, expected at least 2", ErrTrustedSetupLength, numG2)
+	}
+	setup.g2 = make([]bls12381.G2Affine, numG2)
+	for i := 0; i < len(setup.g2); i++ {
+		if err := dec.Decode(&setup.g2[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if body.Len() != 0 {
+		return nil, ErrSetupCacheCorrupted
+	}
+
+	return newContext(setup, ScalarsPerBlob, opts...)
+}
+
+// MarshalBinary implements [encoding.BinaryMarshaler], so that a Context can also be cached with encoding/gob. The
+// encoding is the output of [Context.SerializeSetup].
+//
+// Only the trusted setup is encoded. Tables precomputed from it, along with the behavior configured by
+// [ContextOption]s, are not, and must be set up again when decoding with [LoadContextFromBinary].
+func (c *Context) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := c.SerializeSetup(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler]. It replaces the Context with one created by
+// [LoadContextFromBinary] from data, without any [ContextOption]s.
+func (c *Context) UnmarshalBinary(data []byte) error {
+	loaded, err := LoadContextFromBinary(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	*c = *loaded
+	return nil
+}
+
+// readLength reads a big-endian uint32 written by writeLength.
+func readLength(r io.Reader) (int, error) {
+	var length [4]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		return 0, ErrSetupCacheCorrupted
+	}
+	return int(binary.BigEndian.Uint32(length[:])), nil
+}
+
+// readG1Points reads the points written by writeG1Points. It returns nil if there are no points.
+func readG1Points(r io.Reader, dec *bls12381.Decoder) ([]bls12381.G1Affine, error) {
+	numPoints, err := readLength(r)
+	if err != nil || numPoints == 0 {
+		return nil, err
+	}
+	if numPoints > ScalarsPerBlob {
+		return nil, fmt.Errorf("%w: got %d G1 points, expected at most %d", ErrTrustedSetupLength, numPoints, ScalarsPerBlob)
+	}
+
+	points := make([]bls12381.G1Affine, numPoints)
+	for i := 0; i < numPoints; i++ {
+		if err := dec.Decode(&points[i]); err != nil {
+			return nil, err
+		}
+	}
+	return points, nil
+}
@@ -0,0 +1,86 @@
+package gokzg4844_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"testing"
+
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetupCacheRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, ctx.SerializeSetup(&buf))
+
+	cachedCtx, err := gokzg4844.LoadContextFromBinary(&buf)
+	require.NoError(t, err)
+
+	blob := GetRandBlob(11)
+	expected, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	got, err := cachedCtx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	require.Equal(t, expected, got)
+
+	proof, err := cachedCtx.ComputeBlobKZGProof(blob, got, NumGoRoutines)
+	require.NoError(t, err)
+	require.NoError(t, cachedCtx.VerifyBlobKZGProof(blob, got, proof))
+	require.NoError(t, ctx.VerifyBlobKZGProof(blob, got, proof))
+}
+
+func TestContextMarshalBinary(t *testing.T) {
+	data, err := ctx.MarshalBinary()
+	require.NoError(t, err)
+	var unmarshaledCtx gokzg4844.Context
+	require.NoError(t, unmarshaledCtx.UnmarshalBinary(data))
+
+	// Contexts can also be cached with encoding/gob
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(ctx))
+	var gobCtx gokzg4844.Context
+	require.NoError(t, gob.NewDecoder(&buf).Decode(&gobCtx))
+
+	blob := GetRandBlob(12)
+	expected, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+	expectedProof, err := ctx.ComputeBlobKZGProof(blob, expected, NumGoRoutines)
+	require.NoError(t, err)
+	for _, decodedCtx := range []*gokzg4844.Context{&unmarshaledCtx, &gobCtx} {
+		got, err := decodedCtx.BlobToKZGCommitment(blob, NumGoRoutines)
+		require.NoError(t, err)
+		require.Equal(t, expected, got)
+		proof, err := decodedCtx.ComputeBlobKZGProof(blob, got, NumGoRoutines)
+		require.NoError(t, err)
+		require.Equal(t, expectedProof, proof)
+		require.NoError(t, decodedCtx.VerifyBlobKZGProof(blob, got, proof))
+	}
+
+	// A corrupted encoding is rejected
+	data[len(data)/2] ^= 1
+	require.ErrorIs(t, unmarshaledCtx.UnmarshalBinary(data), gokzg4844.ErrSetupCacheCorrupted)
+}
+
+func TestSetupCacheRejectsCorruption(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, ctx.SerializeSetup(&buf))
+	cache := buf.Bytes()
+
+	// Flip a bit in one of the points
+	corrupted := append([]byte(nil), cache...)
+	corrupted[100] ^= 1
+	_, err := gokzg4844.LoadContextFromBinary(bytes.NewReader(corrupted))
+	require.ErrorIs(t, err, gokzg4844.ErrSetupCacheCorrupted)
+
+	// Truncate the cache
+	_, err = gokzg4844.LoadContextFromBinary(bytes.NewReader(cache[:len(cache)-1]))
+	require.ErrorIs(t, err, gokzg4844.ErrSetupCacheCorrupted)
+
+	// Change the version, with a valid checksum
+	payload := append([]byte(nil), cache[:len(cache)-sha256.Size]...)
+	payload[0]++
+	checksum := sha256.Sum256(payload)
+	_, err = gokzg4844.LoadContextFromBinary(bytes.NewReader(append(payload, checksum[:]...)))
+	require.ErrorIs(t, err, gokzg4844.ErrSetupCacheVersion)
+}
@@ -4,11 +4,18 @@ import (
 	"bytes"
 	_ "embed"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
 	"sync"
 
 	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	"github.com/crate-crypto/go-kzg-4844/internal/kzg"
+	"golang.org/x/sync/errgroup"
 )
 
 // This library will not :
@@ -28,6 +35,18 @@ type JSONTrustedSetup struct {
 	SetupG1Lagrange [ScalarsPerBlob]G1CompressedHexStr `json:"setup_G1_lagrange"`
 }
 
+// NumG2PointsEthereumSetup is the number of G2 points in the trusted setup produced by the Ethereum KZG ceremony.
+const NumG2PointsEthereumSetup = 65
+
+// ethereumJSONTrustedSetup is the JSON format of the trusted setup published by the Ethereum KZG ceremony.
+//
+// Older versions of the file do not contain the monomial G1 points.
+type ethereumJSONTrustedSetup struct {
+	G1Monomial []G1CompressedHexStr `json:"g1_monomial"`
+	G1Lagrange []G1CompressedHexStr `json:"g1_lagrange"`
+	G2Monomial []G2CompressedHexStr `json:"g2_monomial"`
+}
+
 // G1CompressedHexStr is a hex-string (with the 0x prefix) of a compressed G1 point.
 type G1CompressedHexStr = string
 
@@ -93,22 +112,373 @@ func CheckTrustedSetupIsWellFormed(trustedSetup *JSONTrustedSetup) error {
 	return nil
 }
 
+// ValidateSetup checks that the trusted setup held by the Context is internally consistent, which catches mismatched
+// G1 and G2 files or a corrupted download. It checks that:
+//
+//   - the degree-0 G1 and G2 elements are the standard generators
+//   - the lagrange G1 points are those of a polynomial basis, ie sum_i L_i(s) = 1
+//   - the lagrange G1 points and the degree-1 G2 element use the same secret, ie e([s]G1, G2) = e(G1, [s]G2), where
+//     [s]G1 is computed from the lagrange points
+//   - if the Context keeps the monomial G1 points, see [WithMonomialSetup], the degree-1 point is that same [s]G1
+//
+// This costs about two commitments and two pairings, and only needs to be done once after loading a setup. The
+// returned error wraps [ErrInvalidTrustedSetup].
+//
+// For a Context created with [NewVerifierContext], which has no lagrange points, only the generators are checked,
+// along with the degree-1 monomial G1 point against the degree-1 G2 element when the monomial points were given.
+func (c *Context) ValidateSetup() error {
+	if c.closed {
+		return ErrContextClosed
+	}
+
+	_, _, genG1, genG2 := bls12381.Generators()
+	if !c.openKey.GenG1.Equal(&genG1) {
+		return fmt.Errorf("%w: the degree-0 G1 element is not the generator", ErrInvalidTrustedSetup)
+	}
+	if !c.openKey.GenG2.Equal(&genG2) {
+		return fmt.Errorf("%w: the degree-0 G2 element is not the generator", ErrInvalidTrustedSetup)
+	}
+
+	// A verifier Context has no lagrange points, so only the monomial points for cells can be checked against the G2
+	// points
+	if c.verifierOnly {
+		if c.cellOpenKey == nil {
+			return nil
+		}
+		return checkSameSecret(c.openKey, c.cellOpenKey.G1[1])
+	}
+
+	// The constant polynomial 1 has all of its evaluations equal to 1
+	ones := make([]fr.Element, ScalarsPerBlob)
+	for i := 0; i < len(ones); i++ {
+		ones[i].SetOne()
+	}
+	oneG1, err := kzg.Commit(ones, c.commitKey, 0)
+	if err != nil {
+		return err
+	}
+	if !oneG1.Equal(&c.openKey.GenG1) {
+		return fmt.Errorf("%w: the lagrange G1 points do not sum to the generator", ErrInvalidTrustedSetup)
+	}
+
+	// The polynomial X evaluates to the roots of the domain, which are in the same (bit-reversed) order as the
+	// commit key
+	sG1, err := kzg.Commit(c.domain.Roots, c.commitKey, 0)
+	if err != nil {
+		return err
+	}
+	if err := checkSameSecret(c.openKey, *sG1); err != nil {
+		return err
+	}
+
+	if len(c.monomialG1) > 1 && !c.monomialG1[1].Equal(sG1) {
+		return fmt.Errorf("%w: the monomial and lagrange G1 points use different secrets", ErrInvalidTrustedSetup)
+	}
+
+	return nil
+}
+
+// checkSameSecret checks that [s]G1 and the degree-1 G2 point [s]G2 of the opening key use the same secret s, ie that
+// e([s]G1, G2) = e(G1, [s]G2), where G1 and G2 are the generators of the opening key. The returned error wraps
+// [ErrInvalidTrustedSetup].
+func checkSameSecret(openKey *kzg.OpeningKey, sG1 bls12381.G1Affine) error {
+	var negSG1 bls12381.G1Affine
+	negSG1.Neg(&sG1)
+	check, err := bls12381.PairingCheck(
+		[]bls12381.G1Affine{openKey.GenG1, negSG1},
+		[]bls12381.G2Affine{openKey.AlphaG2, openKey.GenG2},
+	)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return fmt.Errorf("%w: the G1 points and the G2 points use different secrets", ErrInvalidTrustedSetup)
+	}
+	return nil
+}
+
+// SetupEqual reports whether the Context and other were created from the same trusted setup, over the same domain.
+// It compares the affine coordinates of all of the G1 and G2 points directly, along with the size and generator of the
+// domain, so that it does not depend on how the setups were serialized.
+//
+// The lagrange and the monomial G1 points are each compared only if both Contexts hold them, or neither does: a
+// Context created with [NewVerifierContext] is not equal to a full Context, even for the same ceremony output, and
+// neither is a Context which keeps the monomial points, see [WithMonomialSetup], equal to one which does not. The
+// options that the Contexts were created with are not compared. Returns false if either Context was closed.
+func (c *Context) SetupEqual(other *Context) bool {
+	if c.closed || other.closed {
+		return false
+	}
+	if c.domain.Cardinality != other.domain.Cardinality || !c.domain.Generator.Equal(&other.domain.Generator) {
+		return false
+	}
+	if !c.openKey.GenG1.Equal(&other.openKey.GenG1) ||
+		!c.openKey.GenG2.Equal(&other.openKey.GenG2) ||
+		!c.openKey.AlphaG2.Equal(&other.openKey.AlphaG2) {
+		return false
+	}
+	if (c.commitKey == nil) != (other.commitKey == nil) {
+		return false
+	}
+	if c.commitKey != nil && !g1PointsEqual(c.commitKey.G1, other.commitKey.G1) {
+		return false
+	}
+	if !g1PointsEqual(c.monomialG1, other.monomialG1) {
+		return false
+	}
+
+	if len(c.g2Points) != len(other.g2Points) {
+		return false
+	}
+	for i := 0; i < len(c.g2Points); i++ {
+		if !c.g2Points[i].Equal(&other.g2Points[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// g1PointsEqual reports whether the two lists hold the same G1 points, in the same order.
+func g1PointsEqual(a, b []bls12381.G1Affine) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := 0; i < len(a); i++ {
+		if !a[i].Equal(&b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// parsedTrustedSetup holds the group elements of a trusted setup, with all points in order.
+type parsedTrustedSetup struct {
+	// genG1 is the degree-0 G1 element.
+	genG1 bls12381.G1Affine
+	// lagrangeG1 are the G1 points in lagrange form.
+	lagrangeG1 []bls12381.G1Affine
+	// monomialG1 are the G1 points in monomial form, or nil if they were not part of the trusted setup.
+	monomialG1 []bls12381.G1Affine
+	// g2 are the G2 points in monomial form.
+	g2 []bls12381.G2Affine
+}
+
 // parseTrustedSetup parses the trusted setup in `JSONTrustedSetup` format
 // which contains hex encoded strings to corresponding group elements.
 // Elements are assumed to be well-formed.
-func parseTrustedSetup(trustedSetup *JSONTrustedSetup) (bls12381.G1Affine, []bls12381.G1Affine, []bls12381.G2Affine, error) {
+func parseTrustedSetup(trustedSetup *JSONTrustedSetup) (parsedTrustedSetup, error) {
 	// Take the generator point from the monomial SRS
 	if len(trustedSetup.SetupG1) < 1 {
-		return bls12381.G1Affine{}, nil, nil, kzg.ErrMinSRSSize
-	}
-	genG1, err := parseG1PointNoSubgroupCheck(trustedSetup.SetupG1[0])
-	if err != nil {
-		return bls12381.G1Affine{}, nil, nil, err
+		return parsedTrustedSetup{}, kzg.ErrMinSRSSize
 	}
 
+	monomialG1Points := parseG1PointsNoSubgroupCheck(trustedSetup.SetupG1[:])
 	setupLagrangeG1Points := parseG1PointsNoSubgroupCheck(trustedSetup.SetupG1Lagrange[:])
 	g2Points := parseG2PointsNoSubgroupCheck(trustedSetup.SetupG2)
-	return genG1, setupLagrangeG1Points, g2Points, nil
+	return parsedTrustedSetup{
+		genG1:      monomialG1Points[0],
+		lagrangeG1: setupLagrangeG1Points,
+		monomialG1: monomialG1Points,
+		g2:         g2Points,
+	}, nil
+}
+
+// parseEthereumTrustedSetup reads a trusted setup in the [ethereumJSONTrustedSetup] format from r.
+//
+// Unlike parseTrustedSetup, the input is not assumed to be well-formed: the number of points is checked
+// and all points are checked to be in the correct subgroup.
+//
+// If numG1Points is less than [ScalarsPerBlob], only the first numG1Points monomial G1 points are parsed, and the
+// lagrange G1 points are skipped, since a prefix of them is not a lagrange basis for a smaller domain. The returned
+// setup then holds no lagrange points. Returns [ErrMissingMonomialSetup] if there are no monomial G1 points to parse.
+func parseEthereumTrustedSetup(r io.Reader, numGoRoutines int, numG1Points int) (parsedTrustedSetup, error) {
+	var setup ethereumJSONTrustedSetup
+	if err := json.NewDecoder(r).Decode(&setup); err != nil {
+		return parsedTrustedSetup{}, err
+	}
+
+	if len(setup.G1Lagrange) != ScalarsPerBlob {
+		return parsedTrustedSetup{}, fmt.Errorf("%w: got %d g1_lagrange points, expected %d", ErrTrustedSetupLength, len(setup.G1Lagrange), ScalarsPerBlob)
+	}
+	if len(setup.G1Monomial) != 0 && len(setup.G1Monomial) != ScalarsPerBlob {
+		return parsedTrustedSetup{}, fmt.Errorf("%w: got %d g1_monomial points, expected %d", ErrTrustedSetupLength, len(setup.G1Monomial), ScalarsPerBlob)
+	}
+	if len(setup.G2Monomial) != NumG2PointsEthereumSetup {
+		return parsedTrustedSetup{}, fmt.Errorf("%w: got %d g2_monomial points, expected %d", ErrTrustedSetupLength, len(setup.G2Monomial), NumG2PointsEthereumSetup)
+	}
+
+	if numG1Points < ScalarsPerBlob {
+		if len(setup.G1Monomial) == 0 {
+			return parsedTrustedSetup{}, ErrMissingMonomialSetup
+		}
+		setup.G1Lagrange = nil
+		setup.G1Monomial = setup.G1Monomial[:numG1Points]
+	}
+
+	if numGoRoutines <= 0 {
+		numGoRoutines = runtime.NumCPU()
+	}
+	var errG errgroup.Group
+	errG.SetLimit(numGoRoutines)
+
+	var g1Points []bls12381.G1Affine
+	if len(setup.G1Lagrange) != 0 {
+		g1Points = make([]bls12381.G1Affine, len(setup.G1Lagrange))
+	}
+	for i := range setup.G1Lagrange {
+		j := i // Capture the value of the loop variable
+		errG.Go(func() error {
+			point, err := parseG1Point(setup.G1Lagrange[j])
+			if err != nil {
+				return fmt.Errorf("g1_lagrange[%d]: %w", j, err)
+			}
+			g1Points[j] = point
+			return nil
+		})
+	}
+	var monomialG1Points []bls12381.G1Affine
+	if len(setup.G1Monomial) != 0 {
+		monomialG1Points = make([]bls12381.G1Affine, len(setup.G1Monomial))
+	}
+	for i := range setup.G1Monomial {
+		j := i // Capture the value of the loop variable
+		errG.Go(func() error {
+			point, err := parseG1Point(setup.G1Monomial[j])
+			if err != nil {
+				return fmt.Errorf("g1_monomial[%d]: %w", j, err)
+			}
+			monomialG1Points[j] = point
+			return nil
+		})
+	}
+	g2Points := make([]bls12381.G2Affine, len(setup.G2Monomial))
+	for i := range setup.G2Monomial {
+		j := i // Capture the value of the loop variable
+		errG.Go(func() error {
+			point, err := parseG2Point(setup.G2Monomial[j])
+			if err != nil {
+				return fmt.Errorf("g2_monomial[%d]: %w", j, err)
+			}
+			g2Points[j] = point
+			return nil
+		})
+	}
+	if err := errG.Wait(); err != nil {
+		return parsedTrustedSetup{}, err
+	}
+
+	// Without the monomial points, the generator is the sum of the lagrange points, since the lagrange polynomials
+	// sum to 1. This keeps setups with a non-standard generator working.
+	var genG1 bls12381.G1Affine
+	if monomialG1Points != nil {
+		genG1 = monomialG1Points[0]
+	} else {
+		var sum bls12381.G1Jac
+		for i := range g1Points {
+			sum.AddMixed(&g1Points[i])
+		}
+		genG1.FromJacobian(&sum)
+	}
+
+	return parsedTrustedSetup{
+		genG1:      genG1,
+		lagrangeG1: g1Points,
+		monomialG1: monomialG1Points,
+		g2:         g2Points,
+	}, nil
+}
+
+// parseEthereumVerifierSetup reads a trusted setup in the [ethereumJSONTrustedSetup] format from r, parsing only the
+// points needed for verification: the G2 points, and the first [ScalarsPerCell] monomial G1 points if there are any.
+// The lagrange G1 points are not parsed, and the returned setup does not hold them.
+//
+// As in parseEthereumTrustedSetup, the number of points is checked and the parsed points are checked to be in the
+// correct subgroup.
+func parseEthereumVerifierSetup(r io.Reader) (parsedTrustedSetup, error) {
+	var setup ethereumJSONTrustedSetup
+	if err := json.NewDecoder(r).Decode(&setup); err != nil {
+		return parsedTrustedSetup{}, err
+	}
+
+	if len(setup.G1Monomial) != 0 && len(setup.G1Monomial) != ScalarsPerBlob {
+		return parsedTrustedSetup{}, fmt.Errorf("%w: got %d g1_monomial points, expected %d", ErrTrustedSetupLength, len(setup.G1Monomial), ScalarsPerBlob)
+	}
+	if len(setup.G2Monomial) != NumG2PointsEthereumSetup {
+		return parsedTrustedSetup{}, fmt.Errorf("%w: got %d g2_monomial points, expected %d", ErrTrustedSetupLength, len(setup.G2Monomial), NumG2PointsEthereumSetup)
+	}
+
+	g2Points := make([]bls12381.G2Affine, len(setup.G2Monomial))
+	for i := range setup.G2Monomial {
+		point, err := parseG2Point(setup.G2Monomial[i])
+		if err != nil {
+			return parsedTrustedSetup{}, fmt.Errorf("g2_monomial[%d]: %w", i, err)
+		}
+		g2Points[i] = point
+	}
+
+	// Without the monomial points, we fall back to the standard generator, since the lagrange points are not parsed.
+	// A setup with a non-standard generator therefore needs its monomial points to be verified with.
+	_, _, genG1, _ := bls12381.Generators()
+	var monomialG1Points []bls12381.G1Affine
+	if len(setup.G1Monomial) != 0 {
+		monomialG1Points = make([]bls12381.G1Affine, ScalarsPerCell)
+		for i := range monomialG1Points {
+			point, err := parseG1Point(setup.G1Monomial[i])
+			if err != nil {
+				return parsedTrustedSetup{}, fmt.Errorf("g1_monomial[%d]: %w", i, err)
+			}
+			monomialG1Points[i] = point
+		}
+		genG1 = monomialG1Points[0]
+	}
+
+	return parsedTrustedSetup{
+		genG1:      genG1,
+		monomialG1: monomialG1Points,
+		g2:         g2Points,
+	}, nil
+}
+
+// parseG1Point parses a hex-string (with the 0x prefix) into a G1 point, checking
+// that the point is in the correct subgroup.
+func parseG1Point(hexString string) (bls12381.G1Affine, error) {
+	byts, err := decodeHexPoint(hexString, CompressedG1Size)
+	if err != nil {
+		return bls12381.G1Affine{}, err
+	}
+	return deserializeG1Point(*(*G1Point)(byts), true)
+}
+
+// parseG2Point parses a hex-string (with the 0x prefix) into a G2 point, checking
+// that the point is in the correct subgroup.
+func parseG2Point(hexString string) (bls12381.G2Affine, error) {
+	byts, err := decodeHexPoint(hexString, CompressedG2Size)
+	if err != nil {
+		return bls12381.G2Affine{}, err
+	}
+
+	var point bls12381.G2Affine
+	d := bls12381.NewDecoder(bytes.NewReader(byts), bls12381.NoSubgroupChecks())
+	if err := d.Decode(&point); err != nil {
+		return bls12381.G2Affine{}, err
+	}
+	if !point.IsInSubGroup() {
+		return bls12381.G2Affine{}, ErrPointNotInSubgroup
+	}
+	return point, nil
+}
+
+// decodeHexPoint decodes a hex-string (with the 0x prefix) of a compressed point
+// which is expected to be `size` bytes long.
+func decodeHexPoint(hexString string, size int) ([]byte, error) {
+	if !strings.HasPrefix(hexString, "0x") {
+		return nil, ErrMalformedHexPoint
+	}
+	byts, err := hex.DecodeString(hexString[2:])
+	if err != nil || len(byts) != size {
+		return nil, ErrMalformedHexPoint
+	}
+	return byts, nil
 }
 
 // parseG1PointNoSubgroupCheck parses a hex-string (with the 0x prefix) into a G1 point.
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/crate-crypto/go-kzg-4844/internal/kzg"
 	"github.com/stretchr/testify/require"
 )
 
@@ -15,3 +17,39 @@ func TestTransformTrustedSetup(t *testing.T) {
 	err = CheckTrustedSetupIsWellFormed(&parsedSetup)
 	require.NoError(t, err)
 }
+
+func TestNewContextDomainSize(t *testing.T) {
+	jsonSetup := JSONTrustedSetup{}
+	err := json.Unmarshal([]byte(testKzgSetupStr), &jsonSetup)
+	require.NoError(t, err)
+	setup, err := parseTrustedSetup(&jsonSetup)
+	require.NoError(t, err)
+
+	for _, scalarsPerBlob := range []int{0, 3000, 2 * ScalarsPerBlob} {
+		_, err = newContext(setup, scalarsPerBlob)
+		require.ErrorIs(t, err, ErrInvalidDomainSize)
+	}
+
+	// A smaller domain commits to its evaluations using lagrange points derived from the monomial points
+	const smallSize = 256
+	smallCtx, err := newContext(setup, smallSize)
+	require.NoError(t, err)
+	require.Equal(t, uint64(smallSize), smallCtx.domain.Cardinality)
+
+	poly := make([]fr.Element, smallSize)
+	for i := range poly {
+		poly[i].SetUint64(uint64(i*i + 1))
+	}
+	commitment, err := kzg.Commit(poly, smallCtx.commitKey, 0)
+	require.NoError(t, err)
+	coeffs := smallCtx.domain.LagrangeBitReversedToMonomial(poly)
+	monomialCommitKey := kzg.CommitKey{G1: setup.monomialG1}
+	expected, err := kzg.Commit(coeffs, &monomialCommitKey, 0)
+	require.NoError(t, err)
+	require.True(t, commitment.Equal(expected))
+
+	// Without the monomial points, only the size of the lagrange points is supported
+	setup.monomialG1 = nil
+	_, err = newContext(setup, smallSize)
+	require.ErrorIs(t, err, ErrMissingMonomialSetup)
+}
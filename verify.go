@@ -1,75 +1,216 @@
 package gokzg4844
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	"github.com/crate-crypto/go-kzg-4844/internal/kzg"
 	"golang.org/x/sync/errgroup"
 )
 
 // VerifyKZGProof implements [verify_kzg_proof].
 //
+// The proof, input point and claimed value are validated before any pairing is computed. If one of them is malformed,
+// the returned error matches [ErrInvalidProof] with [errors.Is], along with the specific cause, such as
+// [ErrNonCanonicalScalar] or [ErrPointNotInSubgroup].
+//
 // [verify_kzg_proof]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_kzg_proof
 func (c *Context) VerifyKZGProof(blobCommitment KZGCommitment, inputPointBytes, claimedValueBytes Scalar, kzgProof KZGProof) error {
+	if c.closed {
+		return ErrContextClosed
+	}
+
 	// 1. Deserialization
 	//
-	claimedValue, err := DeserializeScalar(claimedValueBytes)
+	proof, err := c.deserializeOpeningProof(inputPointBytes, claimedValueBytes, kzgProof)
 	if err != nil {
 		return err
 	}
 
-	inputPoint, err := DeserializeScalar(inputPointBytes)
+	polynomialCommitment, err := c.deserializeKZGCommitment(blobCommitment)
 	if err != nil {
 		return err
 	}
 
-	polynomialCommitment, err := DeserializeKZGCommitment(blobCommitment)
-	if err != nil {
-		return err
+	// 2. Verify opening proof
+	defer c.startTiming(OpVerify)()
+	return kzg.Verify(&polynomialCommitment, &proof, c.openKey)
+}
+
+// VerifyKZGProofWithCommitment is a version of [Context.VerifyKZGProof] which takes a commitment that was already
+// deserialized with [Context.DeserializeCommitment]. This avoids decompressing the same commitment when verifying
+// many proofs against it.
+func (c *Context) VerifyKZGProofWithCommitment(commitment DeserializedKZGCommitment, inputPointBytes, claimedValueBytes Scalar, kzgProof KZGProof) error {
+	if c.closed {
+		return ErrContextClosed
 	}
 
-	quotientCommitment, err := DeserializeKZGProof(kzgProof)
+	// 1. Deserialization
+	//
+	proof, err := c.deserializeOpeningProof(inputPointBytes, claimedValueBytes, kzgProof)
 	if err != nil {
 		return err
 	}
 
 	// 2. Verify opening proof
-	proof := kzg.OpeningProof{
+	defer c.startTiming(OpVerify)()
+	return kzg.Verify(&commitment.point, &proof, c.openKey)
+}
+
+// deserializeOpeningProof deserializes and validates the claimed value, the input point and the proof, in that order.
+// The scalars must be canonical, and the proof is checked to be in the prime-order subgroup unless the Context was
+// created with [WithoutSubgroupChecks]. The returned error matches both [ErrInvalidProof] and the deserialization
+// error.
+func (c *Context) deserializeOpeningProof(inputPointBytes, claimedValueBytes Scalar, kzgProof KZGProof) (kzg.OpeningProof, error) {
+	claimedValue, err := DeserializeScalar(claimedValueBytes)
+	if err != nil {
+		return kzg.OpeningProof{}, invalidProofError{fmt.Errorf("claimed value: %w", err)}
+	}
+
+	inputPoint, err := DeserializeScalar(inputPointBytes)
+	if err != nil {
+		return kzg.OpeningProof{}, invalidProofError{fmt.Errorf("input point: %w", err)}
+	}
+
+	quotientCommitment, err := c.deserializeKZGProof(kzgProof)
+	if err != nil {
+		return kzg.OpeningProof{}, invalidProofError{fmt.Errorf("proof: %w", err)}
+	}
+
+	return kzg.OpeningProof{
 		QuotientCommitment: quotientCommitment,
 		InputPoint:         inputPoint,
 		ClaimedValue:       claimedValue,
+	}, nil
+}
+
+// VerifyKZGProofBatch is the batched version of [Context.VerifyKZGProof]. The i'th proof attests that the polynomial
+// committed to by commitments[i] evaluates to claimedValues[i] at inputPoints[i].
+//
+// Rather than verifying each proof individually, the proofs are combined using a random linear combination so that
+// only a single pairing check is needed. If the batch is empty, nil is returned.
+func (c *Context) VerifyKZGProofBatch(commitments []KZGCommitment, inputPoints, claimedValues []Scalar, proofs []KZGProof) error {
+	if c.closed {
+		return ErrContextClosed
 	}
 
-	return kzg.Verify(&polynomialCommitment, &proof, c.openKey)
+	// 1. Check that all components in the batch have the same size
+	//
+	batchSize := len(commitments)
+	lengthsAreEqual := batchSize == len(inputPoints) && batchSize == len(claimedValues) && batchSize == len(proofs)
+	if !lengthsAreEqual {
+		return ErrKZGProofBatchLengthCheck
+	}
+
+	// 2. Collect opening proofs
+	//
+	openingProofs := make([]kzg.OpeningProof, batchSize)
+	polynomialCommitments := make([]bls12381.G1Affine, batchSize)
+	for i := 0; i < batchSize; i++ {
+		// 2a. Deserialize
+		//
+		claimedValue, err := DeserializeScalar(claimedValues[i])
+		if err != nil {
+			return err
+		}
+
+		inputPoint, err := DeserializeScalar(inputPoints[i])
+		if err != nil {
+			return err
+		}
+
+		polynomialCommitment, err := c.deserializeKZGCommitment(commitments[i])
+		if err != nil {
+			return err
+		}
+
+		quotientCommitment, err := c.deserializeKZGProof(proofs[i])
+		if err != nil {
+			return err
+		}
+
+		// 2b. Append opening proof to list
+		openingProofs[i] = kzg.OpeningProof{
+			QuotientCommitment: quotientCommitment,
+			InputPoint:         inputPoint,
+			ClaimedValue:       claimedValue,
+		}
+		polynomialCommitments[i] = polynomialCommitment
+	}
+
+	// 3. Verify opening proofs
+	defer c.startTiming(OpBatchVerify)()
+	return kzg.BatchVerifyMultiPoints(polynomialCommitments, openingProofs, c.openKey)
 }
 
 // VerifyBlobKZGProof implements [verify_blob_kzg_proof].
 //
 // [verify_blob_kzg_proof]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_blob_kzg_proof
 func (c *Context) VerifyBlobKZGProof(blob Blob, blobCommitment KZGCommitment, kzgProof KZGProof) error {
-	// 1. Deserialize
+	if c.closed {
+		return ErrContextClosed
+	}
+
+	_, _, err := c.verifyBlobKZGProof(blob, blobCommitment, kzgProof)
+	return err
+}
+
+// VerifyBlobKZGProofDebug is [Context.VerifyBlobKZGProof], which also returns the evaluation point z derived from the
+// blob and commitment with Fiat-Shamir, and the evaluation y of the blob at z. These can be compared with the values
+// used by the prover, to find out why a proof does not verify.
+//
+// ok is false, and err is nil, if the proof does not verify. err is not nil if any of the inputs could not be
+// deserialized, in which case z and y are only returned if they could be computed.
+func (c *Context) VerifyBlobKZGProofDebug(blob Blob, blobCommitment KZGCommitment, kzgProof KZGProof) (z Scalar, y Scalar, ok bool, err error) {
+	if c.closed {
+		return Scalar{}, Scalar{}, false, ErrContextClosed
+	}
+
+	evaluationChallenge, outputPoint, err := c.verifyBlobKZGProof(blob, blobCommitment, kzgProof)
+	if evaluationChallenge != nil {
+		z = SerializeScalar(*evaluationChallenge)
+	}
+	if outputPoint != nil {
+		y = SerializeScalar(*outputPoint)
+	}
+	if errors.Is(err, kzg.ErrVerifyOpeningProof) {
+		return z, y, false, nil
+	}
+	return z, y, err == nil, err
+}
+
+// verifyBlobKZGProof implements [Context.VerifyBlobKZGProof], returning the evaluation challenge and the claimed value
+// as soon as they are computed.
+func (c *Context) verifyBlobKZGProof(blob Blob, blobCommitment KZGCommitment, kzgProof KZGProof) (*fr.Element, *fr.Element, error) {
+	// 1. Compute the evaluation challenge
+	//
+	// This only depends on the serialized inputs, so it is available even if they fail to deserialize
+	evaluationChallenge := computeChallenge(c.newChallengeHash, blob, blobCommitment)
+
+	// 2. Deserialize
 	//
 	polynomial, err := DeserializeBlob(blob)
 	if err != nil {
-		return err
+		return &evaluationChallenge, nil, err
 	}
 
-	polynomialCommitment, err := DeserializeKZGCommitment(blobCommitment)
+	polynomialCommitment, err := c.deserializeKZGCommitment(blobCommitment)
 	if err != nil {
-		return err
+		return &evaluationChallenge, nil, err
 	}
 
-	quotientCommitment, err := DeserializeKZGProof(kzgProof)
+	quotientCommitment, err := c.deserializeKZGProof(kzgProof)
 	if err != nil {
-		return err
+		return &evaluationChallenge, nil, err
 	}
 
-	// 2. Compute the evaluation challenge
-	evaluationChallenge := computeChallenge(blob, blobCommitment)
-
 	// 3. Compute output point/ claimed value
 	outputPoint, err := c.domain.EvaluateLagrangePolynomial(polynomial, evaluationChallenge)
 	if err != nil {
-		return err
+		return &evaluationChallenge, nil, err
 	}
 
 	// 4. Verify opening proof
@@ -79,13 +220,31 @@ func (c *Context) VerifyBlobKZGProof(blob Blob, blobCommitment KZGCommitment, kz
 		ClaimedValue:       *outputPoint,
 	}
 
-	return kzg.Verify(&polynomialCommitment, &openingProof, c.openKey)
+	defer c.startTiming(OpVerify)()
+	return &evaluationChallenge, outputPoint, kzg.Verify(&polynomialCommitment, &openingProof, c.openKey)
 }
 
 // VerifyBlobKZGProofBatch implements [verify_blob_kzg_proof_batch].
 //
+// All of the blobs, commitments and proofs are deserialized before any of the proofs are checked. If one of them is
+// malformed, the returned error says which one, as in "commitment 3: ...", and wraps the deserialization error, such
+// as [ErrNonCanonicalScalar] or [ErrPointNotInSubgroup]. [ErrVerifyOpeningProof] is only returned when all of the
+// inputs are well-formed but a proof is wrong.
+//
+// This is [Context.VerifyBlobKZGProofBatchContext] with [context.Background], and so cannot be cancelled.
+//
 // [verify_blob_kzg_proof_batch]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_blob_kzg_proof_batch
 func (c *Context) VerifyBlobKZGProofBatch(blobs []Blob, polynomialCommitments []KZGCommitment, kzgProofs []KZGProof) error {
+	return c.VerifyBlobKZGProofBatchContext(context.Background(), blobs, polynomialCommitments, kzgProofs)
+}
+
+// VerifyBlobKZGProofBatchContext is [Context.VerifyBlobKZGProofBatch], but returns ctx.Err() once ctx is done. The
+// context is checked before each blob is deserialized and evaluated, and before the final pairing check.
+func (c *Context) VerifyBlobKZGProofBatchContext(ctx context.Context, blobs []Blob, polynomialCommitments []KZGCommitment, kzgProofs []KZGProof) error {
+	if c.closed {
+		return ErrContextClosed
+	}
+
 	// 1. Check that all components in the batch have the same size
 	//
 	blobsLen := len(blobs)
@@ -95,75 +254,276 @@ func (c *Context) VerifyBlobKZGProofBatch(blobs []Blob, polynomialCommitments []
 	}
 	batchSize := blobsLen
 
-	// 2. Collect opening proofs
+	// 2. Deserialize all of the inputs
 	//
-	openingProofs := make([]kzg.OpeningProof, batchSize)
 	commitments := make([]bls12381.G1Affine, batchSize)
+	quotientCommitments := make([]bls12381.G1Affine, batchSize)
+	polynomials := make([]kzg.Polynomial, batchSize)
 	for i := 0; i < batchSize; i++ {
-		// 2a. Deserialize
-		//
-		serComm := polynomialCommitments[i]
-		polynomialCommitment, err := DeserializeKZGCommitment(serComm)
-		if err != nil {
+		if err := ctx.Err(); err != nil {
 			return err
 		}
 
-		kzgProof := kzgProofs[i]
-		quotientCommitment, err := DeserializeKZGProof(kzgProof)
+		var err error
+		commitments[i], err = c.deserializeKZGCommitment(polynomialCommitments[i])
 		if err != nil {
-			return err
+			return fmt.Errorf("commitment %d: %w", i, err)
+		}
+
+		quotientCommitments[i], err = c.deserializeKZGProof(kzgProofs[i])
+		if err != nil {
+			return fmt.Errorf("proof %d: %w", i, err)
 		}
 
-		blob := blobs[i]
-		polynomial, err := DeserializeBlob(blob)
+		polynomials[i], err = DeserializeBlob(blobs[i])
 		if err != nil {
+			return fmt.Errorf("blob %d: %w", i, err)
+		}
+	}
+
+	// 3. Collect opening proofs
+	//
+	openingProofs := make([]kzg.OpeningProof, batchSize)
+	for i := 0; i < batchSize; i++ {
+		if err := ctx.Err(); err != nil {
 			return err
 		}
 
-		// 2b. Compute the evaluation challenge
-		evaluationChallenge := computeChallenge(blob, serComm)
+		// 3a. Compute the evaluation challenge
+		evaluationChallenge := computeChallenge(c.newChallengeHash, blobs[i], polynomialCommitments[i])
 
-		// 2c. Compute output point/ claimed value
-		outputPoint, err := c.domain.EvaluateLagrangePolynomial(polynomial, evaluationChallenge)
+		// 3b. Compute output point/ claimed value
+		outputPoint, err := c.domain.EvaluateLagrangePolynomial(polynomials[i], evaluationChallenge)
 		if err != nil {
 			return err
 		}
 
-		// 2d. Append opening proof to list
-		openingProof := kzg.OpeningProof{
-			QuotientCommitment: quotientCommitment,
+		// 3c. Append opening proof to list
+		openingProofs[i] = kzg.OpeningProof{
+			QuotientCommitment: quotientCommitments[i],
 			InputPoint:         evaluationChallenge,
 			ClaimedValue:       *outputPoint,
 		}
-		openingProofs[i] = openingProof
-		commitments[i] = polynomialCommitment
 	}
 
-	// 3. Verify opening proofs
+	// 4. Verify opening proofs
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer c.startTiming(OpBatchVerify)()
 	return kzg.BatchVerifyMultiPoints(commitments, openingProofs, c.openKey)
 }
 
+// VerifyBlobKZGProofBatchWithFailures is [Context.VerifyBlobKZGProofBatch], but returns the indices, in increasing
+// order, of the elements of the batch whose proofs do not verify, so that only those blobs need to be dropped. The
+// batch verifies if and only if the returned indices are empty and the error is nil.
+//
+// An element whose blob, commitment or proof is malformed is reported as failing, rather than failing the whole batch.
+// The remaining proofs are checked together first, as in [Context.VerifyBlobKZGProofBatch], and only if that fails
+// is the batch bisected to find the invalid proofs. A valid batch thus costs the same as with
+// [Context.VerifyBlobKZGProofBatch], and each invalid proof adds about 2*log2(n) batch checks.
+//
+// Returns [ErrBatchLengthCheck] if the number of blobs, commitments and proofs differ.
+func (c *Context) VerifyBlobKZGProofBatchWithFailures(blobs []Blob, polynomialCommitments []KZGCommitment, kzgProofs []KZGProof) ([]int, error) {
+	if c.closed {
+		return nil, ErrContextClosed
+	}
+
+	// 1. Check that all components in the batch have the same size
+	//
+	batchSize := len(blobs)
+	if batchSize != len(polynomialCommitments) || batchSize != len(kzgProofs) {
+		return nil, ErrBatchLengthCheck
+	}
+
+	// 2. Deserialize the inputs and collect the opening proofs of the well-formed elements
+	//
+	failed := make([]bool, batchSize)
+	indices := make([]int, 0, batchSize)
+	commitments := make([]bls12381.G1Affine, 0, batchSize)
+	openingProofs := make([]kzg.OpeningProof, 0, batchSize)
+	for i := 0; i < batchSize; i++ {
+		commitment, err := c.deserializeKZGCommitment(polynomialCommitments[i])
+		if err != nil {
+			failed[i] = true
+			continue
+		}
+		quotientCommitment, err := c.deserializeKZGProof(kzgProofs[i])
+		if err != nil {
+			failed[i] = true
+			continue
+		}
+		polynomial, err := DeserializeBlob(blobs[i])
+		if err != nil {
+			failed[i] = true
+			continue
+		}
+
+		evaluationChallenge := computeChallenge(c.newChallengeHash, blobs[i], polynomialCommitments[i])
+		outputPoint, err := c.domain.EvaluateLagrangePolynomial(polynomial, evaluationChallenge)
+		if err != nil {
+			return nil, err
+		}
+
+		indices = append(indices, i)
+		commitments = append(commitments, commitment)
+		openingProofs = append(openingProofs, kzg.OpeningProof{
+			QuotientCommitment: quotientCommitment,
+			InputPoint:         evaluationChallenge,
+			ClaimedValue:       *outputPoint,
+		})
+	}
+
+	// 3. Find the opening proofs which do not verify
+	//
+	stopTiming := c.startTiming(OpBatchVerify)
+	failing, err := kzg.FindFailingProofs(commitments, openingProofs, c.openKey)
+	stopTiming()
+	if err != nil {
+		return nil, err
+	}
+	for _, j := range failing {
+		failed[indices[j]] = true
+	}
+
+	// 4. Collect the indices of the failed elements, in increasing order
+	//
+	var failedIndices []int
+	for i := 0; i < batchSize; i++ {
+		if failed[i] {
+			failedIndices = append(failedIndices, i)
+		}
+	}
+	return failedIndices, nil
+}
+
 // VerifyBlobKZGProofBatchPar implements [verify_blob_kzg_proof_batch]. This is the parallelized version of
 // [Context.VerifyBlobKZGProofBatch], which is single-threaded. This function uses go-routines to process each proof in
 // parallel. If you are worried about resource starvation on large batches, it is advised to schedule your own
 // go-routines in a more intricate way than done below for large batches.
 //
+// It returns the same errors as [Context.VerifyBlobKZGProofBatch]: a malformed input is reported with its index, and
+// the first one in the batch is reported regardless of which go-routine finishes first.
+//
 // [verify_blob_kzg_proof_batch]: https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_blob_kzg_proof_batch
 func (c *Context) VerifyBlobKZGProofBatchPar(blobs []Blob, commitments []KZGCommitment, proofs []KZGProof) error {
+	if c.closed {
+		return ErrContextClosed
+	}
+
 	// 1. Check that all components in the batch have the same size
 	if len(commitments) != len(blobs) || len(proofs) != len(blobs) {
 		return ErrBatchLengthCheck
 	}
 
-	// 2. Verify each opening proof using green threads
-	var errG errgroup.Group
-	for i := range blobs {
-		j := i // Capture the value of the loop variable
-		errG.Go(func() error {
-			return c.VerifyBlobKZGProof(blobs[j], commitments[j], proofs[j])
-		})
+	// 2. Deserialize and verify each element of the batch
+	//
+	// The errors are collected by index, so that a malformed input is reported with its index, and the same one as by
+	// VerifyBlobKZGProofBatch, whichever go-routine finishes first
+	deserializationErrs := make([]error, len(blobs))
+	verifyErrs := make([]error, len(blobs))
+	verify := func(i int) {
+		polyCommitment, err := c.deserializeKZGCommitment(commitments[i])
+		if err != nil {
+			deserializationErrs[i] = fmt.Errorf("commitment %d: %w", i, err)
+			return
+		}
+		quotientCommitment, err := c.deserializeKZGProof(proofs[i])
+		if err != nil {
+			deserializationErrs[i] = fmt.Errorf("proof %d: %w", i, err)
+			return
+		}
+		polynomial, err := DeserializeBlob(blobs[i])
+		if err != nil {
+			deserializationErrs[i] = fmt.Errorf("blob %d: %w", i, err)
+			return
+		}
+
+		evaluationChallenge := computeChallenge(c.newChallengeHash, blobs[i], commitments[i])
+		outputPoint, err := c.domain.EvaluateLagrangePolynomial(polynomial, evaluationChallenge)
+		if err != nil {
+			verifyErrs[i] = err
+			return
+		}
+		openingProof := kzg.OpeningProof{
+			QuotientCommitment: quotientCommitment,
+			InputPoint:         evaluationChallenge,
+			ClaimedValue:       *outputPoint,
+		}
+		verifyErrs[i] = kzg.Verify(&polyCommitment, &openingProof, c.openKey)
+	}
+
+	// 3. Verify each opening proof using green threads, or one after the other without go-routines
+	//
+	// The whole batch is timed as one batch verification, which also includes the deserialization since it is done by
+	// the same go-routines
+	stopTiming := c.startTiming(OpBatchVerify)
+	if c.singleThreaded {
+		for i := range blobs {
+			verify(i)
+		}
+	} else {
+		var errG errgroup.Group
+		for i := range blobs {
+			j := i // Capture the value of the loop variable
+			errG.Go(func() error {
+				verify(j)
+				return nil
+			})
+		}
+		_ = errG.Wait()
+	}
+	stopTiming()
+
+	// 4. Report the first malformed input, and otherwise the first proof which does not verify
+	for _, err := range deserializationErrs {
+		if err != nil {
+			return err
+		}
+	}
+	for _, err := range verifyErrs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifyVanishingOnSet checks a proof from [Context.ProveVanishingOnSet] that the polynomial committed to by
+// `commitment` evaluates to zero at every one of `points`, with the pairing check e(C, G2) = e(Q, [Z_S(s)]G2), where
+// Z_S is the polynomial vanishing at all of the points.
+//
+// [Z_S(s)]G2 is computed from the G2 points of the trusted setup, and so there must be fewer points than
+// [Context.NumG2Points], which is at most 64 for the Ethereum setup; otherwise the returned error wraps
+// [ErrTooManyVanishingPoints]. This works with a Context created with [NewVerifierContext], but one created with
+// [NewVerifierContextFromG2] only has the G2 points for a single point.
+//
+// The points must be distinct, otherwise the returned error wraps [ErrDuplicateInterpolationPoint]. Returns
+// [ErrVerifyOpeningProof] if the proof is incorrect.
+func (c *Context) VerifyVanishingOnSet(commitment KZGCommitment, points []Scalar, proof KZGProof) error {
+	if c.closed {
+		return ErrContextClosed
+	}
+	if len(points) >= len(c.g2Points) {
+		return fmt.Errorf("%w: got %d points, expected at most %d", ErrTooManyVanishingPoints, len(points), len(c.g2Points)-1)
+	}
+
+	// 1. Deserialization
+	//
+	polyCommitment, err := c.deserializeKZGCommitment(commitment)
+	if err != nil {
+		return err
+	}
+	quotientCommitment, err := c.deserializeKZGProof(proof)
+	if err != nil {
+		return invalidProofError{fmt.Errorf("proof: %w", err)}
+	}
+	vanishingPoints, err := deserializeScalars(points, "point")
+	if err != nil {
+		return err
 	}
 
-	// 3. Wait for all go routines to complete and check if any returned an error
-	return errG.Wait()
+	// 2. Verify the proof
+	defer c.startTiming(OpVerify)()
+	return kzg.VerifyVanishingOnSet(&polyCommitment, &quotientCommitment, vanishingPoints, c.g2Points)
 }
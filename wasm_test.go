@@ -0,0 +1,35 @@
+//go:build js && wasm
+
+package gokzg4844_test
+
+import (
+	"bytes"
+	"testing"
+
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	"github.com/stretchr/testify/require"
+)
+
+// TestVerifyWasm checks that a verifier Context works under GOOS=js GOARCH=wasm, without go-routines. It can be run
+// with node, using the wasm_exec script that ships with Go:
+//
+//	GOOS=js GOARCH=wasm go test -exec="$(go env GOROOT)/lib/wasm/go_js_wasm_exec" -run TestVerifyWasm .
+func TestVerifyWasm(t *testing.T) {
+	setup := ethereumTrustedSetupFromEmbedded(t)
+	verifierCtx, err := gokzg4844.NewVerifierContext(bytes.NewReader(marshalJSON(t, setup)), gokzg4844.WithSingleThreaded())
+	require.NoError(t, err)
+
+	blob := GetRandBlob(42)
+	commitment, err := ctx.BlobToKZGCommitment(blob, NumGoRoutines)
+	require.NoError(t, err)
+
+	inputPoint := GetRandFieldElement(42)
+	proof, claimedValue, err := ctx.ComputeKZGProof(blob, inputPoint, NumGoRoutines)
+	require.NoError(t, err)
+	require.NoError(t, verifierCtx.VerifyKZGProof(commitment, inputPoint, claimedValue, proof))
+
+	blobProof, err := ctx.ComputeBlobKZGProof(blob, commitment, NumGoRoutines)
+	require.NoError(t, err)
+	require.NoError(t, verifierCtx.VerifyBlobKZGProof(blob, commitment, blobProof))
+	require.ErrorIs(t, verifierCtx.VerifyBlobKZGProof(blob, commitment, proof), gokzg4844.ErrVerifyOpeningProof)
+}